@@ -1,6 +1,7 @@
 package apiv2
 
 import (
+	"net/http"
 	"net/url"
 	"time"
 
@@ -20,13 +21,13 @@ type NodeSetClient struct {
 	*common.CommonNodeSetClient
 
 	// Core routes
-	Core *v2core.V2CoreClient
+	Core v2core.CoreClient
 
 	// StakeWise routes
-	StakeWise *v2stakewise.V2StakeWiseClient
+	StakeWise v2stakewise.StakeWiseClient
 
 	// Constellation routes
-	Constellation *v2constellation.V2ConstellationClient
+	Constellation v2constellation.ConstellationClient
 }
 
 // Creates a new NodeSet client
@@ -34,6 +35,19 @@ type NodeSetClient struct {
 func NewNodeSetClient(baseUrl string, timeout time.Duration) *NodeSetClient {
 	expandedUrl, _ := url.JoinPath(baseUrl, ApiVersion) // becomes [https://nodeset.io/api/v2]
 	commonClient := common.NewCommonNodeSetClient(expandedUrl, timeout)
+	return newNodeSetClient(commonClient)
+}
+
+// Creates a new NodeSet client that sends its requests with the provided HTTP client, such as one with a custom transport
+// baseUrl: The base URL to use for the client, for example [https://nodeset.io/api]
+func NewNodeSetClientWithHttpClient(baseUrl string, httpClient *http.Client) *NodeSetClient {
+	expandedUrl, _ := url.JoinPath(baseUrl, ApiVersion)
+	commonClient := common.NewCommonNodeSetClientWithHttpClient(expandedUrl, httpClient)
+	return newNodeSetClient(commonClient)
+}
+
+// Creates a new NodeSet client with the HTTP route bindings for each module
+func newNodeSetClient(commonClient *common.CommonNodeSetClient) *NodeSetClient {
 	return &NodeSetClient{
 		CommonNodeSetClient: commonClient,
 		Core:                v2core.NewV2CoreClient(commonClient),
@@ -0,0 +1,48 @@
+package v2constellation
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Binding for the validators routes of a single deployment, for use with the exits.ExitMessageReconciler
+type ExitMessageClient struct {
+	client     ConstellationClient
+	deployment string
+}
+
+// Creates a new exit message client for the provided deployment
+func (c *V2ConstellationClient) NewExitMessageClient(deployment string) *ExitMessageClient {
+	return NewExitMessageClient(c, deployment)
+}
+
+// Creates a new exit message client for the provided deployment that uses any Constellation client implementation
+func NewExitMessageClient(client ConstellationClient, deployment string) *ExitMessageClient {
+	return &ExitMessageClient{
+		client:     client,
+		deployment: deployment,
+	}
+}
+
+// Get the pubkeys of the node's minipool validators that NodeSet doesn't have an exit message for yet
+func (c *ExitMessageClient) GetValidatorsMissingExitMessages(ctx context.Context, logger *slog.Logger) ([]beacon.ValidatorPubkey, error) {
+	data, err := c.client.Validators_Get(ctx, logger, c.deployment)
+	if err != nil {
+		return nil, err
+	}
+	pubkeys := []beacon.ValidatorPubkey{}
+	for _, validator := range data.Validators {
+		if validator.RequiresExitMessage {
+			pubkeys = append(pubkeys, validator.Pubkey)
+		}
+	}
+	return pubkeys, nil
+}
+
+// Upload encrypted exit messages for the provided validators
+func (c *ExitMessageClient) UploadExitMessages(ctx context.Context, logger *slog.Logger, exitData []common.EncryptedExitData) error {
+	return c.client.Validators_Patch(ctx, logger, c.deployment, exitData)
+}
@@ -1,11 +1,30 @@
 package v2core
 
-import "github.com/nodeset-org/nodeset-client-go/common"
+import (
+	"context"
+	"log/slog"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/core"
+)
 
 const (
 	CorePrefix string = "core/"
 )
 
+// Binding for the core routes, implemented by V2CoreClient and by fakes for testing
+type CoreClient interface {
+	// Registers the node with the NodeSet server
+	NodeAddress(ctx context.Context, logger *slog.Logger, email string, nodeWallet ethcommon.Address, signer func([]byte) ([]byte, error)) error
+
+	// Get a nonce from the NodeSet server for a new session
+	Nonce(ctx context.Context, logger *slog.Logger) (core.NonceData, error)
+
+	// Logs into the NodeSet server, starting a new session
+	Login(ctx context.Context, logger *slog.Logger, nonce string, address ethcommon.Address, signer func([]byte) ([]byte, error)) (core.LoginData, error)
+}
+
 type V2CoreClient struct {
 	commonClient *common.CommonNodeSetClient
 }
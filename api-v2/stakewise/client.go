@@ -1,11 +1,45 @@
 package v2stakewise
 
-import "github.com/nodeset-org/nodeset-client-go/common"
+import (
+	"context"
+	"log/slog"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
 
 const (
 	StakeWisePrefix string = "modules/stakewise/"
 )
 
+// Binding for the StakeWise routes, implemented by V2StakeWiseClient and by fakes for testing
+type StakeWiseClient interface {
+	common.DeploymentsClient
+
+	// Gets the list of vaults available on the server for the provided deployment
+	Vaults(ctx context.Context, logger *slog.Logger, deployment string) (VaultsData, error)
+
+	// Get the current version of the aggregated deposit data on the server
+	DepositDataMeta(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address) (stakewise.DepositDataMetaData, error)
+
+	// Get the aggregated deposit data from the server
+	DepositData_Get(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address) (stakewise.DepositDataData, error)
+
+	// Uploads deposit data to NodeSet
+	DepositData_Post(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, depositData []beacon.ExtendedDepositData) error
+
+	// Get a list of all of the pubkeys that have already been registered with NodeSet for this node on the provided deployment and vault
+	Validators_Get(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address) (ValidatorsData, error)
+
+	// Submit signed exit data to NodeSet
+	Validators_Patch(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, exitData []common.EncryptedExitData) error
+
+	// Creates a new exit message client for the provided deployment and vault
+	NewExitMessageClient(deployment string, vault ethcommon.Address) *ExitMessageClient
+}
+
 type V2StakeWiseClient struct {
 	commonClient *common.CommonNodeSetClient
 }
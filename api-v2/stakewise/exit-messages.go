@@ -0,0 +1,51 @@
+package v2stakewise
+
+import (
+	"context"
+	"log/slog"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Binding for the validators routes of a single vault, for use with the exits.ExitMessageReconciler
+type ExitMessageClient struct {
+	client     StakeWiseClient
+	deployment string
+	vault      ethcommon.Address
+}
+
+// Creates a new exit message client for the provided deployment and vault
+func (c *V2StakeWiseClient) NewExitMessageClient(deployment string, vault ethcommon.Address) *ExitMessageClient {
+	return NewExitMessageClient(c, deployment, vault)
+}
+
+// Creates a new exit message client for the provided deployment and vault that uses any StakeWise client implementation
+func NewExitMessageClient(client StakeWiseClient, deployment string, vault ethcommon.Address) *ExitMessageClient {
+	return &ExitMessageClient{
+		client:     client,
+		deployment: deployment,
+		vault:      vault,
+	}
+}
+
+// Get the pubkeys of the node's validators in the vault that NodeSet doesn't have an exit message for yet
+func (c *ExitMessageClient) GetValidatorsMissingExitMessages(ctx context.Context, logger *slog.Logger) ([]beacon.ValidatorPubkey, error) {
+	data, err := c.client.Validators_Get(ctx, logger, c.deployment, c.vault)
+	if err != nil {
+		return nil, err
+	}
+	pubkeys := []beacon.ValidatorPubkey{}
+	for _, validator := range data.Validators {
+		if !validator.ExitMessageUploaded {
+			pubkeys = append(pubkeys, validator.Pubkey)
+		}
+	}
+	return pubkeys, nil
+}
+
+// Upload encrypted exit messages for the provided validators
+func (c *ExitMessageClient) UploadExitMessages(ctx context.Context, logger *slog.Logger, exitData []common.EncryptedExitData) error {
+	return c.client.Validators_Patch(ctx, logger, c.deployment, c.vault, exitData)
+}
@@ -1,11 +1,45 @@
 package v3constellation
 
-import "github.com/nodeset-org/nodeset-client-go/common"
+import (
+	"context"
+	"log/slog"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/exits"
+)
 
 const (
 	ConstellationPrefix string = "modules/constellation/"
 )
 
+// Binding for the Constellation routes, implemented by V3ConstellationClient and by fakes for testing
+type ConstellationClient interface {
+	common.DeploymentsClient
+
+	// Get the whitelist status of the node
+	Whitelist_Get(ctx context.Context, logger *slog.Logger, deployment string) (Whitelist_GetData, error)
+
+	// Get a signature for adding the node to the Constellation whitelist
+	Whitelist_Post(ctx context.Context, logger *slog.Logger, deployment string) (Whitelist_PostData, error)
+
+	// Get a signature for creating a new minipool with the provided address and salt
+	MinipoolDepositSignature(ctx context.Context, logger *slog.Logger, deployment string, minipoolAddress ethcommon.Address, salt *big.Int) (MinipoolDepositSignatureData, error)
+
+	// Get a list of all of the pubkeys that have already been registered with NodeSet for this node on the provided deployment
+	Validators_Get(ctx context.Context, logger *slog.Logger, deployment string) (ValidatorsData, error)
+
+	// Submit signed exit data to NodeSet
+	Validators_Patch(ctx context.Context, logger *slog.Logger, deployment string, exitData []common.EncryptedExitData) error
+
+	// Creates a new exit message client for the provided deployment
+	NewExitMessageClient(deployment string) *ExitMessageClient
+
+	// Creates a new onboarding workflow for the node on the provided deployment
+	NewOnboardingWorkflow(deployment string, nodeAddress ethcommon.Address, chain OnboardingChain, chainIDProvider common.ChainIDProvider, store OnboardingStore, reconciler *exits.ExitMessageReconciler) *OnboardingWorkflow
+}
+
 type V3ConstellationClient struct {
 	commonClient *common.CommonNodeSetClient
 }
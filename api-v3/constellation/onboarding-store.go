@@ -0,0 +1,66 @@
+package v3constellation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-json"
+)
+
+// Persists onboarding progress so it can be resumed after a restart
+type OnboardingStore interface {
+	// Load the saved progress; returns nil if there isn't any yet
+	Load() (*OnboardingProgress, error)
+
+	// Save the current progress
+	Save(progress *OnboardingProgress) error
+}
+
+// Onboarding store that saves progress to a JSON file
+type FileOnboardingStore struct {
+	path string
+}
+
+// Creates a new file-backed onboarding store
+func NewFileOnboardingStore(path string) *FileOnboardingStore {
+	return &FileOnboardingStore{
+		path: path,
+	}
+}
+
+// Load the saved progress from the file; returns nil if the file doesn't exist yet
+func (s *FileOnboardingStore) Load() (*OnboardingProgress, error) {
+	bytes, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading onboarding progress file [%s]: %w", s.path, err)
+	}
+
+	var progress OnboardingProgress
+	err = json.Unmarshal(bytes, &progress)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing onboarding progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// Save the progress to the file, replacing it atomically so a crash can't leave a partial file behind
+func (s *FileOnboardingStore) Save(progress *OnboardingProgress) error {
+	bytes, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("error serializing onboarding progress: %w", err)
+	}
+	tempPath := s.path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing onboarding progress file [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, s.path)
+	if err != nil {
+		return fmt.Errorf("error replacing onboarding progress file [%s]: %w", s.path, err)
+	}
+	return nil
+}
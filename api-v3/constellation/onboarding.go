@@ -0,0 +1,317 @@
+package v3constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/exits"
+	"github.com/rocket-pool/node-manager-core/utils"
+)
+
+// A stage of the Constellation onboarding workflow
+type OnboardingStage string
+
+const (
+	// Checking if the node is on the Constellation whitelist
+	OnboardingStage_CheckWhitelist OnboardingStage = "CHECK_WHITELIST"
+
+	// A whitelist signature has been retrieved and needs to be submitted on-chain
+	OnboardingStage_SubmitWhitelist OnboardingStage = "SUBMIT_WHITELIST"
+
+	// The node is whitelisted and is creating minipools
+	OnboardingStage_CreateMinipools OnboardingStage = "CREATE_MINIPOOLS"
+
+	// A minipool deposit signature has been retrieved and needs to be submitted on-chain
+	OnboardingStage_SubmitMinipool OnboardingStage = "SUBMIT_MINIPOOL"
+
+	// Onboarding is done, either because the target number of minipools was reached or NodeSet won't allow any more
+	OnboardingStage_Complete OnboardingStage = "COMPLETE"
+)
+
+// A minipool that has a deposit signature but may not have been created on-chain yet
+type PendingMinipool struct {
+	// The address of the minipool
+	Address ethcommon.Address `json:"address"`
+
+	// The CREATE2 salt for the minipool, as a hex string
+	Salt string `json:"salt"`
+
+	// The SuperNodeAccount nonce the signature was created for
+	SuperNodeNonce uint64 `json:"superNodeNonce"`
+
+	// The deposit signature from NodeSet
+	Signature string `json:"signature"`
+}
+
+// Saved progress of the onboarding workflow
+type OnboardingProgress struct {
+	// The current stage
+	Stage OnboardingStage `json:"stage"`
+
+	// The whitelist signature from NodeSet, if it's been retrieved
+	WhitelistSignature string `json:"whitelistSignature,omitempty"`
+
+	// The minipool currently being created, if there is one
+	PendingMinipool *PendingMinipool `json:"pendingMinipool,omitempty"`
+
+	// The minipools that have been created during onboarding
+	CreatedMinipools []ethcommon.Address `json:"createdMinipools"`
+
+	// True if onboarding stopped because NodeSet reported the node can't create any more minipools
+	MinipoolLimitReached bool `json:"minipoolLimitReached"`
+}
+
+// Binding for the Constellation contracts the onboarding workflow interacts with
+type OnboardingChain interface {
+	// Check if the node is on the Constellation Whitelist contract
+	IsWhitelisted(ctx context.Context) (bool, error)
+
+	// Call Whitelist.addOperator() with the signature from NodeSet and wait for it to be included
+	SubmitWhitelistSignature(ctx context.Context, signature []byte) error
+
+	// Get the node's current SuperNodeAccount nonce
+	GetSuperNodeNonce(ctx context.Context) (uint64, error)
+
+	// Get the address and CREATE2 salt of the next minipool to create
+	GetNextMinipool(ctx context.Context) (ethcommon.Address, *big.Int, error)
+
+	// Call SuperNodeAccount.createMinipool() with the signature from NodeSet and wait for it to be included
+	SubmitMinipoolDeposit(ctx context.Context, minipool ethcommon.Address, salt *big.Int, signature []byte) error
+}
+
+// Drives a node through Constellation onboarding: getting whitelisted, then creating minipools one at a time.
+// Progress is saved after every step so a restart resumes where it left off.
+type OnboardingWorkflow struct {
+	client          ConstellationClient
+	deployment      string
+	nodeAddress     ethcommon.Address
+	chain           OnboardingChain
+	chainIDProvider common.ChainIDProvider
+	store           OnboardingStore
+	reconciler      *exits.ExitMessageReconciler
+	progress        *OnboardingProgress
+}
+
+// Creates a new onboarding workflow for the node on the provided deployment.
+// chainIDProvider is the Execution client the deployment is checked against before each run.
+// If reconciler is set, it will be used to upload missing exit messages when NodeSet requires them before creating another minipool.
+func (c *V3ConstellationClient) NewOnboardingWorkflow(deployment string, nodeAddress ethcommon.Address, chain OnboardingChain, chainIDProvider common.ChainIDProvider, store OnboardingStore, reconciler *exits.ExitMessageReconciler) *OnboardingWorkflow {
+	return NewOnboardingWorkflow(c, deployment, nodeAddress, chain, chainIDProvider, store, reconciler)
+}
+
+// Creates a new onboarding workflow for the node on the provided deployment that uses any Constellation client implementation
+func NewOnboardingWorkflow(client ConstellationClient, deployment string, nodeAddress ethcommon.Address, chain OnboardingChain, chainIDProvider common.ChainIDProvider, store OnboardingStore, reconciler *exits.ExitMessageReconciler) *OnboardingWorkflow {
+	return &OnboardingWorkflow{
+		client:          client,
+		deployment:      deployment,
+		nodeAddress:     nodeAddress,
+		chain:           chain,
+		chainIDProvider: chainIDProvider,
+		store:           store,
+		reconciler:      reconciler,
+	}
+}
+
+// Get the current progress, loading it from the store if necessary
+func (w *OnboardingWorkflow) GetProgress() (*OnboardingProgress, error) {
+	if w.progress != nil {
+		return w.progress, nil
+	}
+	progress, err := w.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("error loading onboarding progress: %w", err)
+	}
+	if progress == nil {
+		progress = &OnboardingProgress{
+			Stage:            OnboardingStage_CheckWhitelist,
+			CreatedMinipools: []ethcommon.Address{},
+		}
+	}
+	w.progress = progress
+	return progress, nil
+}
+
+// Runs the workflow until the node has created targetMinipools minipools during onboarding, NodeSet won't allow any
+// more, or an error occurs. It can be called again after an error or restart to resume. Nothing is done if the
+// deployment isn't for the chain the Execution client is connected to.
+func (w *OnboardingWorkflow) Run(ctx context.Context, logger *slog.Logger, targetMinipools int) (*OnboardingProgress, error) {
+	progress, err := w.GetProgress()
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the deployment is for the right chain
+	_, err = common.ValidateDeployment(ctx, logger, w.client, w.chainIDProvider, w.deployment)
+	if err != nil {
+		return progress, fmt.Errorf("error validating deployment: %w", err)
+	}
+
+	// A new target can reopen a completed workflow
+	if progress.Stage == OnboardingStage_Complete && !progress.MinipoolLimitReached && len(progress.CreatedMinipools) < targetMinipools {
+		progress.Stage = OnboardingStage_CreateMinipools
+	}
+
+	for progress.Stage != OnboardingStage_Complete {
+		common.SafeDebugLog(logger, "Running onboarding step",
+			"stage", progress.Stage,
+			"minipools", len(progress.CreatedMinipools),
+		)
+		switch progress.Stage {
+		case OnboardingStage_CheckWhitelist:
+			err = w.checkWhitelist(ctx, logger)
+		case OnboardingStage_SubmitWhitelist:
+			err = w.submitWhitelist(ctx)
+		case OnboardingStage_CreateMinipools:
+			err = w.createMinipool(ctx, logger, targetMinipools)
+		case OnboardingStage_SubmitMinipool:
+			err = w.submitMinipool(ctx)
+		default:
+			err = fmt.Errorf("unknown onboarding stage [%s]", progress.Stage)
+		}
+		if err != nil {
+			return progress, err
+		}
+		err = w.store.Save(progress)
+		if err != nil {
+			return progress, fmt.Errorf("error saving onboarding progress: %w", err)
+		}
+	}
+	return progress, nil
+}
+
+// Check if the node has been whitelisted yet, and get a signature for it if not
+func (w *OnboardingWorkflow) checkWhitelist(ctx context.Context, logger *slog.Logger) error {
+	data, err := w.client.Whitelist_Get(ctx, logger, w.deployment)
+	if err != nil {
+		return fmt.Errorf("error checking whitelist status: %w", err)
+	}
+	if data.Whitelisted && data.Address != w.nodeAddress {
+		return fmt.Errorf("user already has node %s whitelisted: %w", data.Address.Hex(), common.ErrIncorrectNodeAddress)
+	}
+	if data.Whitelisted {
+		onChain, err := w.chain.IsWhitelisted(ctx)
+		if err != nil {
+			return fmt.Errorf("error checking on-chain whitelist status: %w", err)
+		}
+		if onChain {
+			w.progress.Stage = OnboardingStage_CreateMinipools
+			return nil
+		}
+	}
+
+	// Get a signature
+	signature, err := w.client.Whitelist_Post(ctx, logger, w.deployment)
+	if err != nil {
+		return fmt.Errorf("error getting whitelist signature: %w", err)
+	}
+	w.progress.WhitelistSignature = signature.Signature
+	w.progress.Stage = OnboardingStage_SubmitWhitelist
+	return nil
+}
+
+// Submit the whitelist signature on-chain unless a previous run already did
+func (w *OnboardingWorkflow) submitWhitelist(ctx context.Context) error {
+	onChain, err := w.chain.IsWhitelisted(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking on-chain whitelist status: %w", err)
+	}
+	if !onChain {
+		signature, err := utils.DecodeHex(w.progress.WhitelistSignature)
+		if err != nil {
+			return fmt.Errorf("error decoding whitelist signature: %w", err)
+		}
+		err = w.chain.SubmitWhitelistSignature(ctx, signature)
+		if err != nil {
+			return fmt.Errorf("error submitting whitelist signature: %w", err)
+		}
+	}
+	w.progress.WhitelistSignature = ""
+	w.progress.Stage = OnboardingStage_CreateMinipools
+	return nil
+}
+
+// Get a deposit signature for the next minipool
+func (w *OnboardingWorkflow) createMinipool(ctx context.Context, logger *slog.Logger, targetMinipools int) error {
+	if len(w.progress.CreatedMinipools) >= targetMinipools {
+		w.progress.Stage = OnboardingStage_Complete
+		return nil
+	}
+
+	nonce, err := w.chain.GetSuperNodeNonce(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting SuperNodeAccount nonce: %w", err)
+	}
+	minipool, salt, err := w.chain.GetNextMinipool(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting next minipool address: %w", err)
+	}
+
+	// Get the signature, uploading any missing exit messages if required
+	data, err := w.client.MinipoolDepositSignature(ctx, logger, w.deployment, minipool, salt)
+	if errors.Is(err, common.ErrMissingExitMessage) && w.reconciler != nil {
+		result, reconcileErr := w.reconciler.Reconcile(ctx, logger)
+		if reconcileErr != nil {
+			return fmt.Errorf("error uploading missing exit messages: %w", reconcileErr)
+		}
+		if failures := result.Err(); failures != nil {
+			return fmt.Errorf("error uploading missing exit messages: %w", failures)
+		}
+		data, err = w.client.MinipoolDepositSignature(ctx, logger, w.deployment, minipool, salt)
+	}
+	if errors.Is(err, common.ErrMinipoolLimitReached) {
+		w.progress.MinipoolLimitReached = true
+		w.progress.Stage = OnboardingStage_Complete
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting minipool deposit signature: %w", err)
+	}
+
+	w.progress.PendingMinipool = &PendingMinipool{
+		Address:        minipool,
+		Salt:           salt.Text(16),
+		SuperNodeNonce: nonce,
+		Signature:      data.Signature,
+	}
+	w.progress.Stage = OnboardingStage_SubmitMinipool
+	return nil
+}
+
+// Create the pending minipool on-chain unless a previous run already did
+func (w *OnboardingWorkflow) submitMinipool(ctx context.Context) error {
+	pending := w.progress.PendingMinipool
+	if pending == nil {
+		w.progress.Stage = OnboardingStage_CreateMinipools
+		return nil
+	}
+
+	// If the nonce moved, the minipool was created before the last run stopped
+	nonce, err := w.chain.GetSuperNodeNonce(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting SuperNodeAccount nonce: %w", err)
+	}
+	if nonce == pending.SuperNodeNonce {
+		salt, success := new(big.Int).SetString(pending.Salt, 16)
+		if !success {
+			return fmt.Errorf("error decoding salt [%s]", pending.Salt)
+		}
+		signature, err := utils.DecodeHex(pending.Signature)
+		if err != nil {
+			return fmt.Errorf("error decoding minipool deposit signature: %w", err)
+		}
+		err = w.chain.SubmitMinipoolDeposit(ctx, pending.Address, salt, signature)
+		if err != nil {
+			return fmt.Errorf("error creating minipool %s: %w", pending.Address.Hex(), err)
+		}
+	}
+
+	w.progress.CreatedMinipools = append(w.progress.CreatedMinipools, pending.Address)
+	w.progress.PendingMinipool = nil
+	w.progress.Stage = OnboardingStage_CreateMinipools
+	return nil
+}
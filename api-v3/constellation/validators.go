@@ -8,9 +8,11 @@ import (
 	"log/slog"
 	"net/http"
 	"path"
+	"time"
 
 	"github.com/goccy/go-json"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/rocket-pool/node-manager-core/beacon"
 )
@@ -30,8 +32,11 @@ var (
 
 // Validator status info
 type ValidatorStatus struct {
-	Pubkey              beacon.ValidatorPubkey `json:"pubkey"`
-	RequiresExitMessage bool                   `json:"requiresExitMessage"`
+	Pubkey              beacon.ValidatorPubkey          `json:"pubkey"`
+	RequiresExitMessage bool                            `json:"requiresExitMessage"`
+	Status              common.ValidatorLifecycleStatus `json:"status"`
+	BeaconDepositRoot   ethcommon.Hash                  `json:"beaconDepositRoot"`
+	RegistrationTime    time.Time                       `json:"registrationTime"`
 }
 
 // Response to a validators request
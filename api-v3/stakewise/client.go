@@ -1,11 +1,39 @@
 package v3stakewise
 
-import "github.com/nodeset-org/nodeset-client-go/common"
+import (
+	"context"
+	"log/slog"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/exits"
+	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
+)
 
 const (
 	StakeWisePrefix string = "modules/stakewise/"
 )
 
+// Binding for the StakeWise routes, implemented by V3StakeWiseClient and by fakes for testing
+type StakeWiseClient interface {
+	common.DeploymentsClient
+
+	// Gets the list of vaults available on the server for the provided deployment
+	Vaults(ctx context.Context, logger *slog.Logger, deployment string) (VaultsData, error)
+
+	// Returns information about the requesting user's node account with respect to the number of validators the user has deployed and can deploy on this vault
+	ValidatorMeta_Get(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address) (stakewise.ValidatorsMetaData, error)
+
+	// Get a list of all of the pubkeys that have already been registered with NodeSet for this node on the provided deployment and vault
+	Validators_Get(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address) (ValidatorsData, error)
+
+	// Register new validators with NodeSet, getting the signature for the vault's validators manager
+	Validators_Post(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, validators []ValidatorRegistrationDetails, beaconDepositRoot ethcommon.Hash) (PostValidatorData, error)
+
+	// Creates a new validator registrar for the provided deployment and vault
+	NewValidatorRegistrar(deployment string, vault ethcommon.Address, chainIDProvider common.ChainIDProvider, buildDepositData DepositDataBuilder, signExit exits.ExitSigner, getExitEpoch exits.EpochProvider, depositRoots DepositRootProvider, encryptionKey string) *ValidatorRegistrar
+}
+
 type V3StakeWiseClient struct {
 	commonClient *common.CommonNodeSetClient
 }
@@ -0,0 +1,91 @@
+package v3stakewise
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	// Selector for the Beacon deposit contract's get_deposit_root() function
+	GetDepositRootSelector []byte = crypto.Keccak256([]byte("get_deposit_root()"))[:4]
+)
+
+// Provides the current deposit root of the Beacon deposit contract
+type DepositRootProvider interface {
+	// Get the current deposit root
+	GetDepositRoot(ctx context.Context) (ethcommon.Hash, error)
+}
+
+// Adapter to allow the use of ordinary functions as deposit root providers
+type DepositRootProviderFunc func(ctx context.Context) (ethcommon.Hash, error)
+
+// Get the current deposit root by calling the function
+func (f DepositRootProviderFunc) GetDepositRoot(ctx context.Context) (ethcommon.Hash, error) {
+	return f(ctx)
+}
+
+// Deposit root provider that calls get_deposit_root() on the deposit contract via an Execution client's JSON-RPC API.
+// The root is cached for the latest block, so it's only queried again once a new block has been produced.
+type RpcDepositRootProvider struct {
+	client          *ethclient.Client
+	depositContract ethcommon.Address
+
+	lock        sync.Mutex
+	cachedBlock uint64
+	cachedRoot  ethcommon.Hash
+	hasCache    bool
+}
+
+// Creates a new deposit root provider for the Execution client at the provided URL
+func NewRpcDepositRootProvider(ctx context.Context, rpcUrl string, depositContract ethcommon.Address) (*RpcDepositRootProvider, error) {
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to Execution client at [%s]: %w", rpcUrl, err)
+	}
+	return &RpcDepositRootProvider{
+		client:          client,
+		depositContract: depositContract,
+	}, nil
+}
+
+// Get the deposit root as of the latest block
+func (p *RpcDepositRootProvider) GetDepositRoot(ctx context.Context) (ethcommon.Hash, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	block, err := p.client.BlockNumber(ctx)
+	if err != nil {
+		return ethcommon.Hash{}, fmt.Errorf("error getting latest block number: %w", err)
+	}
+	if p.hasCache && p.cachedBlock == block {
+		return p.cachedRoot, nil
+	}
+
+	result, err := p.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &p.depositContract,
+		Data: GetDepositRootSelector,
+	}, new(big.Int).SetUint64(block))
+	if err != nil {
+		return ethcommon.Hash{}, fmt.Errorf("error calling get_deposit_root on [%s]: %w", p.depositContract.Hex(), err)
+	}
+	if len(result) != ethcommon.HashLength {
+		return ethcommon.Hash{}, fmt.Errorf("get_deposit_root returned %d bytes but expected %d", len(result), ethcommon.HashLength)
+	}
+
+	p.cachedBlock = block
+	p.cachedRoot = ethcommon.BytesToHash(result)
+	p.hasCache = true
+	return p.cachedRoot, nil
+}
+
+// Close the connection to the Execution client
+func (p *RpcDepositRootProvider) Close() {
+	p.client.Close()
+}
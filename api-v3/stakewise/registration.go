@@ -0,0 +1,275 @@
+package v3stakewise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/exits"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// Default number of times to retry a registration with a fresh deposit root if the old one was already assigned
+	DefaultDepositRootRetries int = 3
+
+	// Default time to wait before getting a fresh deposit root, roughly one Beacon slot
+	DefaultDepositRootRetryDelay time.Duration = 12 * time.Second
+)
+
+// Creates the deposit data for the validator with the provided pubkey, using the vault as the withdrawal address
+type DepositDataBuilder func(ctx context.Context, pubkey beacon.ValidatorPubkey, vault ethcommon.Address) (beacon.ExtendedDepositData, error)
+
+// A batch of validators submitted in a single registration request
+type RegistrationBatch struct {
+	// The validators in the batch
+	Pubkeys []beacon.ValidatorPubkey
+
+	// The Beacon deposit root the batch was registered with
+	BeaconDepositRoot ethcommon.Hash
+
+	// The signature NodeSet returned for the batch; empty in dry-run mode
+	Signature string
+}
+
+// Result of a registration run
+type RegistrationResult struct {
+	// True if this was a dry run and nothing was submitted to NodeSet
+	DryRun bool
+
+	// The number of validators the user was allowed to register before the run
+	Available int
+
+	// Candidates that were skipped because NodeSet already has them
+	AlreadyRegistered []beacon.ValidatorPubkey
+
+	// Batches that were registered (or would have been, in dry-run mode)
+	Batches []RegistrationBatch
+}
+
+// Get the pubkeys of all of the validators that were registered
+func (r RegistrationResult) Registered() []beacon.ValidatorPubkey {
+	pubkeys := []beacon.ValidatorPubkey{}
+	for _, batch := range r.Batches {
+		pubkeys = append(pubkeys, batch.Pubkeys...)
+	}
+	return pubkeys
+}
+
+// Registers new validators for a node with a StakeWise vault, handling slot availability, exit message encryption,
+// and deposit root conflicts
+type ValidatorRegistrar struct {
+	// If true, everything is prepared but nothing is submitted to NodeSet
+	DryRun bool
+
+	// The max number of validators to submit in a single request; 0 means submit them all at once
+	BatchSize int
+
+	// The number of times to retry a batch with a fresh deposit root if NodeSet reports the current one has already been assigned
+	DepositRootRetries int
+
+	// How long to wait before getting a fresh deposit root for a retry
+	DepositRootRetryDelay time.Duration
+
+	client           StakeWiseClient
+	deployment       string
+	vault            ethcommon.Address
+	chainIDProvider  common.ChainIDProvider
+	buildDepositData DepositDataBuilder
+	signExit         exits.ExitSigner
+	getExitEpoch     exits.EpochProvider
+	depositRoots     DepositRootProvider
+	encryptionKey    string
+}
+
+// Creates a new validator registrar for the provided deployment and vault.
+// chainIDProvider is the Execution client the deployment is checked against before anything is registered.
+// encryptionKey is the age public key of the NodeSet service that the exit messages will be encrypted for.
+func (c *V3StakeWiseClient) NewValidatorRegistrar(
+	deployment string,
+	vault ethcommon.Address,
+	chainIDProvider common.ChainIDProvider,
+	buildDepositData DepositDataBuilder,
+	signExit exits.ExitSigner,
+	getExitEpoch exits.EpochProvider,
+	depositRoots DepositRootProvider,
+	encryptionKey string,
+) *ValidatorRegistrar {
+	return NewValidatorRegistrar(c, deployment, vault, chainIDProvider, buildDepositData, signExit, getExitEpoch, depositRoots, encryptionKey)
+}
+
+// Creates a new validator registrar for the provided deployment and vault that uses any StakeWise client implementation
+func NewValidatorRegistrar(
+	client StakeWiseClient,
+	deployment string,
+	vault ethcommon.Address,
+	chainIDProvider common.ChainIDProvider,
+	buildDepositData DepositDataBuilder,
+	signExit exits.ExitSigner,
+	getExitEpoch exits.EpochProvider,
+	depositRoots DepositRootProvider,
+	encryptionKey string,
+) *ValidatorRegistrar {
+	return &ValidatorRegistrar{
+		DepositRootRetries:    DefaultDepositRootRetries,
+		DepositRootRetryDelay: DefaultDepositRootRetryDelay,
+		client:                client,
+		deployment:            deployment,
+		vault:                 vault,
+		chainIDProvider:       chainIDProvider,
+		buildDepositData:      buildDepositData,
+		signExit:              signExit,
+		getExitEpoch:          getExitEpoch,
+		depositRoots:          depositRoots,
+		encryptionKey:         encryptionKey,
+	}
+}
+
+// Registers as many of the candidate validators as the user has slots available for, in the order provided.
+// Candidates that NodeSet already knows about are skipped. If a batch fails, the result will contain the batches that
+// were registered before it along with the error. Nothing is registered if the deployment isn't for the chain the
+// Execution client is connected to.
+func (r *ValidatorRegistrar) Register(ctx context.Context, logger *slog.Logger, candidates []beacon.ValidatorPubkey) (RegistrationResult, error) {
+	result := RegistrationResult{
+		DryRun:            r.DryRun,
+		AlreadyRegistered: []beacon.ValidatorPubkey{},
+		Batches:           []RegistrationBatch{},
+	}
+
+	// Make sure the deployment is for the right chain
+	_, err := common.ValidateDeployment(ctx, logger, r.client, r.chainIDProvider, r.deployment)
+	if err != nil {
+		return result, fmt.Errorf("error validating deployment: %w", err)
+	}
+
+	// Get the number of available slots
+	meta, err := r.client.ValidatorMeta_Get(ctx, logger, r.deployment, r.vault)
+	if err != nil {
+		return result, fmt.Errorf("error getting validator meta: %w", err)
+	}
+	result.Available = meta.Available
+
+	// Filter out the validators NodeSet already has
+	validators, err := r.client.Validators_Get(ctx, logger, r.deployment, r.vault)
+	if err != nil {
+		return result, fmt.Errorf("error getting registered validators: %w", err)
+	}
+	known := map[beacon.ValidatorPubkey]bool{}
+	for _, validator := range validators.Validators {
+		known[validator.Pubkey] = true
+	}
+	selected := []beacon.ValidatorPubkey{}
+	for _, pubkey := range candidates {
+		if known[pubkey] {
+			result.AlreadyRegistered = append(result.AlreadyRegistered, pubkey)
+			continue
+		}
+		if len(selected) < meta.Available {
+			selected = append(selected, pubkey)
+		}
+	}
+	if len(selected) == 0 {
+		common.SafeDebugLog(logger, "No validators to register",
+			"available", meta.Available,
+			"candidates", len(candidates),
+		)
+		return result, nil
+	}
+
+	// Build the registration details
+	details, err := r.prepareValidators(ctx, selected)
+	if err != nil {
+		return result, err
+	}
+
+	// Submit them in batches
+	batchSize := r.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(details)
+	}
+	for start := 0; start < len(details); start += batchSize {
+		end := min(start+batchSize, len(details))
+		batch, err := r.submitBatch(ctx, logger, selected[start:end], details[start:end])
+		if err != nil {
+			return result, err
+		}
+		result.Batches = append(result.Batches, batch)
+	}
+	return result, nil
+}
+
+// Creates the deposit data and encrypted exit message for each validator
+func (r *ValidatorRegistrar) prepareValidators(ctx context.Context, pubkeys []beacon.ValidatorPubkey) ([]ValidatorRegistrationDetails, error) {
+	epoch, err := r.getExitEpoch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting exit epoch: %w", err)
+	}
+
+	details := make([]ValidatorRegistrationDetails, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		depositData, err := r.buildDepositData(ctx, pubkey, r.vault)
+		if err != nil {
+			return nil, fmt.Errorf("error creating deposit data for validator %s: %w", pubkey.HexWithPrefix(), err)
+		}
+		exitMessage, err := r.signExit(ctx, pubkey, epoch)
+		if err != nil {
+			return nil, fmt.Errorf("error signing exit message for validator %s: %w", pubkey.HexWithPrefix(), err)
+		}
+		encryptedExit, err := common.EncryptSignedExitMessage(exitMessage, r.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting exit message for validator %s: %w", pubkey.HexWithPrefix(), err)
+		}
+		details[i] = ValidatorRegistrationDetails{
+			DepositData: depositData,
+			ExitMessage: encryptedExit,
+		}
+	}
+	return details, nil
+}
+
+// Submits a batch of validators, retrying with a fresh deposit root if the current one was already assigned
+func (r *ValidatorRegistrar) submitBatch(ctx context.Context, logger *slog.Logger, pubkeys []beacon.ValidatorPubkey, details []ValidatorRegistrationDetails) (RegistrationBatch, error) {
+	batch := RegistrationBatch{
+		Pubkeys: pubkeys,
+	}
+	for attempt := 0; ; attempt++ {
+		// Get the deposit root right before posting so it's as fresh as possible
+		root, err := r.depositRoots.GetDepositRoot(ctx)
+		if err != nil {
+			return batch, fmt.Errorf("error getting beacon deposit root: %w", err)
+		}
+		batch.BeaconDepositRoot = root
+		if r.DryRun {
+			common.SafeDebugLog(logger, "Dry run, skipping validator registration",
+				"count", len(pubkeys),
+				"depositRoot", root.Hex(),
+			)
+			return batch, nil
+		}
+
+		// Submit the batch
+		data, err := r.client.Validators_Post(ctx, logger, r.deployment, r.vault, details, root)
+		if err == nil {
+			batch.Signature = data.Signature
+			return batch, nil
+		}
+		if !errors.Is(err, ErrDepositRootAlreadyAssigned) || attempt >= r.DepositRootRetries {
+			return batch, fmt.Errorf("error registering validators: %w", err)
+		}
+
+		// Wait for a new deposit root
+		common.SafeDebugLog(logger, "Deposit root already assigned, retrying with a fresh one",
+			"depositRoot", root.Hex(),
+			"attempt", attempt+1,
+		)
+		select {
+		case <-ctx.Done():
+			return batch, ctx.Err()
+		case <-time.After(r.DepositRootRetryDelay):
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	"path"
+	"time"
 
 	"github.com/goccy/go-json"
 
@@ -89,8 +90,11 @@ type validatorRegistrationDetailsImpl struct {
 
 // Validator status info
 type ValidatorStatus struct {
-	Pubkey              beacon.ValidatorPubkey `json:"pubkey"`
-	ExitMessageUploaded bool                   `json:"exitMessage"`
+	Pubkey              beacon.ValidatorPubkey          `json:"pubkey"`
+	ExitMessageUploaded bool                            `json:"exitMessage"`
+	Status              common.ValidatorLifecycleStatus `json:"status"`
+	BeaconDepositRoot   ethcommon.Hash                  `json:"beaconDepositRoot"`
+	RegistrationTime    time.Time                       `json:"registrationTime"`
 }
 
 // Response to a validators request
@@ -143,6 +147,9 @@ func (c *V3StakeWiseClient) Validators_Post(
 		case common.InvalidVaultKey:
 			// Invalid vault
 			return PostValidatorData{}, common.ErrInvalidVault
+		case common.InvalidExitMessageKey:
+			// Invalid exit message
+			return PostValidatorData{}, common.ErrInvalidExitMessage
 		}
 	case http.StatusUnauthorized:
 		switch response.Error {
@@ -0,0 +1,74 @@
+// Package cassette provides HTTP transports that record NodeSet API traffic to a file and replay it later,
+// so integrations can be regression-tested against real server responses without network access.
+// Use them with common.NewCommonNodeSetClientWithHttpClient or the versioned NewNodeSetClientWithHttpClient constructors.
+package cassette
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// Version of the cassette file format
+	cassetteVersion int = 1
+)
+
+// A request recorded in a cassette, with secrets scrubbed
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// A response recorded in a cassette, with secrets scrubbed
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// A request and the response the server gave for it
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// A recording of API traffic
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load a cassette from a file
+func Load(path string) (*Cassette, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette [%s]: %w", path, err)
+	}
+	var cassette Cassette
+	err = json.Unmarshal(bytes, &cassette)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing cassette [%s]: %w", path, err)
+	}
+	if cassette.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette [%s] has unsupported version %d", path, cassette.Version)
+	}
+	return &cassette, nil
+}
+
+// Save the cassette to a file
+func (c *Cassette) Save(path string) error {
+	bytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing cassette: %w", err)
+	}
+	err = os.WriteFile(path, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing cassette [%s]: %w", path, err)
+	}
+	return nil
+}
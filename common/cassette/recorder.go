@@ -0,0 +1,118 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// HTTP transport that sends requests to the real server and records each request and response, with secrets scrubbed
+type Recorder struct {
+	transport http.RoundTripper
+	scrubber  *scrubber
+	cassette  *Cassette
+	lock      sync.Mutex
+}
+
+// Creates a new recorder that sends requests with the provided transport, or http.DefaultTransport if it's nil.
+// The JSON fields in scrubbedFields have their string values scrubbed; DefaultScrubbedFields covers session tokens, signatures, and exit messages.
+func NewRecorder(transport http.RoundTripper, scrubbedFields []string) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{
+		transport: transport,
+		scrubber:  newScrubber(scrubbedFields),
+		cassette: &Cassette{
+			Version:      cassetteVersion,
+			Interactions: []Interaction{},
+		},
+	}
+}
+
+// Creates a new recorder with the default transport and scrubbed fields that saves the cassette to the provided path when the test finishes
+func NewTestRecorder(tb testing.TB, path string) *Recorder {
+	recorder := NewRecorder(nil, DefaultScrubbedFields)
+	tb.Cleanup(func() {
+		err := recorder.Save(path)
+		if err != nil {
+			tb.Errorf("Error saving cassette: %v", err)
+		}
+	})
+	return recorder
+}
+
+// Send the request to the server and record it along with the response
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	// Read the request body so it can be recorded and still sent
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+		request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	// Send it
+	response, err := r.transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the response body so it can be recorded and still returned
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	// Record the interaction
+	interaction := Interaction{
+		Request: recordRequest(r.scrubber, request, requestBody),
+		Response: RecordedResponse{
+			StatusCode: response.StatusCode,
+			Body:       r.scrubber.scrubBody(responseBody),
+		},
+	}
+	if contentType := response.Header.Get("Content-Type"); contentType != "" {
+		interaction.Response.Header = http.Header{
+			"Content-Type": []string{contentType},
+		}
+	}
+	r.lock.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.lock.Unlock()
+	return response, nil
+}
+
+// Get the cassette with the interactions recorded so far
+func (r *Recorder) GetCassette() *Cassette {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return &Cassette{
+		Version:      r.cassette.Version,
+		Interactions: append([]Interaction{}, r.cassette.Interactions...),
+	}
+}
+
+// Save the interactions recorded so far to a file
+func (r *Recorder) Save(path string) error {
+	return r.GetCassette().Save(path)
+}
+
+// Create the scrubbed record of a request
+func recordRequest(scrubber *scrubber, request *http.Request, body []byte) RecordedRequest {
+	return RecordedRequest{
+		Method: request.Method,
+		Path:   request.URL.Path,
+		Query:  scrubber.scrubQuery(request.URL.Query()),
+		Body:   scrubber.scrubBody(body),
+	}
+}
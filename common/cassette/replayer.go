@@ -0,0 +1,181 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+// How replayed requests are matched to recorded interactions
+type MatchMode string
+
+const (
+	// Requests must arrive in the recorded order, and their method, route, query, and scrubbed body must match exactly
+	MatchMode_Strict MatchMode = "strict"
+
+	// Requests can arrive in any order. Their method and route must match, the query parameters can be in any order,
+	// and JSON bodies only need to be equivalent.
+	MatchMode_Fuzzy MatchMode = "fuzzy"
+)
+
+var (
+	// The request didn't match any of the remaining interactions in the cassette
+	ErrUnmatchedRequest error = errors.New("request doesn't match any recorded interaction")
+)
+
+// HTTP transport that serves recorded responses instead of sending requests to a server
+type Replayer struct {
+	cassette  *Cassette
+	mode      MatchMode
+	scrubber  *scrubber
+	used      []bool
+	unmatched []RecordedRequest
+	lock      sync.Mutex
+}
+
+// Creates a new replayer for the cassette. scrubbedFields should be the same fields the cassette was recorded with,
+// so incoming requests are scrubbed the same way before being matched.
+func NewReplayer(cassette *Cassette, mode MatchMode, scrubbedFields []string) *Replayer {
+	return &Replayer{
+		cassette:  cassette,
+		mode:      mode,
+		scrubber:  newScrubber(scrubbedFields),
+		used:      make([]bool, len(cassette.Interactions)),
+		unmatched: []RecordedRequest{},
+	}
+}
+
+// Creates a new replayer for the cassette at the provided path with the default scrubbed fields. The test fails if the cassette
+// can't be loaded, and when it finishes if any request didn't match a recorded interaction.
+func NewTestReplayer(tb testing.TB, path string, mode MatchMode) *Replayer {
+	cassette, err := Load(path)
+	if err != nil {
+		tb.Fatalf("Error loading cassette: %v", err)
+	}
+	replayer := NewReplayer(cassette, mode, DefaultScrubbedFields)
+	tb.Cleanup(func() {
+		for _, request := range replayer.GetUnmatchedRequests() {
+			tb.Errorf("Unmatched request to cassette [%s]: %s %s?%s %s", path, request.Method, request.Path, request.Query, request.Body)
+		}
+	})
+	return replayer
+}
+
+// Serve the recorded response for the request
+func (r *Replayer) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+	}
+	recorded := recordRequest(r.scrubber, request, body)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	index := r.findMatch(recorded)
+	if index == -1 {
+		r.unmatched = append(r.unmatched, recorded)
+		return nil, fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, recorded.Method, recorded.Path)
+	}
+	r.used[index] = true
+
+	recordedResponse := r.cassette.Interactions[index].Response
+	header := http.Header{}
+	for name, values := range recordedResponse.Header {
+		header[name] = append([]string{}, values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResponse.StatusCode, http.StatusText(recordedResponse.StatusCode)),
+		StatusCode:    recordedResponse.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(recordedResponse.Body))),
+		ContentLength: int64(len(recordedResponse.Body)),
+		Request:       request,
+	}, nil
+}
+
+// Get the requests that didn't match a recorded interaction
+func (r *Replayer) GetUnmatchedRequests() []RecordedRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]RecordedRequest{}, r.unmatched...)
+}
+
+// Get the number of recorded interactions that haven't been replayed yet
+func (r *Replayer) GetRemainingCount() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// Find the index of the interaction that matches the request, or -1 if there isn't one
+func (r *Replayer) findMatch(request RecordedRequest) int {
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] {
+			continue
+		}
+		if r.mode == MatchMode_Strict {
+			// Only the next interaction in order can match
+			if interaction.Request == request {
+				return i
+			}
+			return -1
+		}
+		if fuzzyMatch(interaction.Request, request) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Check if a request matches a recorded one, ignoring query parameter order and JSON formatting
+func fuzzyMatch(recorded RecordedRequest, request RecordedRequest) bool {
+	if recorded.Method != request.Method || recorded.Path != request.Path {
+		return false
+	}
+
+	recordedQuery, err := url.ParseQuery(recorded.Query)
+	if err != nil {
+		return false
+	}
+	requestQuery, err := url.ParseQuery(request.Query)
+	if err != nil {
+		return false
+	}
+	if len(recordedQuery) != 0 || len(requestQuery) != 0 {
+		if !reflect.DeepEqual(recordedQuery, requestQuery) {
+			return false
+		}
+	}
+
+	if recorded.Body == request.Body {
+		return true
+	}
+	var recordedBody any
+	var requestBody any
+	if json.Unmarshal([]byte(recorded.Body), &recordedBody) != nil || json.Unmarshal([]byte(request.Body), &requestBody) != nil {
+		return false
+	}
+	return reflect.DeepEqual(recordedBody, requestBody)
+}
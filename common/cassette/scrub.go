@@ -0,0 +1,103 @@
+package cassette
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// Value that scrubbed strings are replaced with, unless they're hex strings
+	ScrubbedValue string = "scrubbed"
+)
+
+// JSON fields whose string values are secrets and are scrubbed before being recorded or matched
+var DefaultScrubbedFields []string = []string{
+	"token",
+	"signature",
+	"exitMessage",
+	"exit_message",
+}
+
+// Scrubs secrets out of request and response bodies
+type scrubber struct {
+	fields map[string]bool
+}
+
+// Creates a new scrubber for the provided JSON fields
+func newScrubber(fields []string) *scrubber {
+	fieldMap := map[string]bool{}
+	for _, field := range fields {
+		fieldMap[field] = true
+	}
+	return &scrubber{
+		fields: fieldMap,
+	}
+}
+
+// Scrub the secret fields from a JSON body. Bodies that aren't JSON are returned as-is.
+func (s *scrubber) scrubBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	// Keep numbers as-is so large integers like wei balances don't lose precision
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	err := decoder.Decode(&value)
+	if err != nil {
+		return string(body)
+	}
+	scrubbed, err := json.Marshal(s.scrubValue(value))
+	if err != nil {
+		return string(body)
+	}
+	return string(scrubbed)
+}
+
+// Scrub the secret fields from a query string, sorting the parameters
+func (s *scrubber) scrubQuery(query url.Values) string {
+	scrubbed := url.Values{}
+	for name, values := range query {
+		for _, value := range values {
+			if s.fields[name] {
+				value = scrubString(value)
+			}
+			scrubbed.Add(name, value)
+		}
+	}
+	return scrubbed.Encode()
+}
+
+// Recursively scrub the secret fields from a deserialized JSON value
+func (s *scrubber) scrubValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			if str, isString := child.(string); isString && s.fields[key] {
+				typed[key] = scrubString(str)
+				continue
+			}
+			typed[key] = s.scrubValue(child)
+		}
+		return typed
+	case []any:
+		for i, child := range typed {
+			typed[i] = s.scrubValue(child)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// Replace a secret string. Hex strings are replaced with zeros of the same length so they still decode.
+func scrubString(value string) string {
+	if strings.HasPrefix(value, "0x") {
+		return "0x" + strings.Repeat("0", len(value)-2)
+	}
+	return ScrubbedValue
+}
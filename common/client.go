@@ -38,6 +38,15 @@ func NewCommonNodeSetClient(baseUrl string, timeout time.Duration) *CommonNodeSe
 	}
 }
 
+// Creates a new NodeSet client that sends its requests with the provided HTTP client, such as one with a custom transport
+// baseUrl: The base URL to use for the client, for example [https://nodeset.io/api]
+func NewCommonNodeSetClientWithHttpClient(baseUrl string, httpClient *http.Client) *CommonNodeSetClient {
+	return &CommonNodeSetClient{
+		baseUrl:    baseUrl,
+		httpClient: httpClient,
+	}
+}
+
 // Set the session token for the client after logging in
 func (c *CommonNodeSetClient) SetSessionToken(token string) {
 	c.sessionToken = token
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+)
+
+// Binding for a module's deployments route, implemented by the StakeWise and Constellation clients
+type DeploymentsClient interface {
+	// Gets the list of deployments available on the server
+	Deployments(ctx context.Context, logger *slog.Logger) (DeploymentsData, error)
+}
+
+// Provides the chain ID of the Execution client the node is connected to, such as an ethclient.Client
+type ChainIDProvider interface {
+	// Get the chain ID reported by eth_chainId
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// The deployment is for a different chain than the one the Execution client is connected to
+type ErrChainIDMismatch struct {
+	// The name of the deployment
+	Deployment string
+
+	// The chain ID of the deployment according to NodeSet
+	DeploymentChainID *big.Int
+
+	// The chain ID reported by the Execution client
+	ClientChainID *big.Int
+}
+
+func (e *ErrChainIDMismatch) Error() string {
+	return fmt.Sprintf("deployment [%s] is for chain %s but the Execution client is connected to chain %s", e.Deployment, e.DeploymentChainID, e.ClientChainID)
+}
+
+// Resolves a deployment by name and makes sure it's for the chain the Execution client is connected to.
+// Returns ErrInvalidDeployment if the server doesn't have the deployment, or an *ErrChainIDMismatch if the chain IDs differ.
+func ValidateDeployment(ctx context.Context, logger *slog.Logger, client DeploymentsClient, chain ChainIDProvider, deploymentName string) (Deployment, error) {
+	// Find the deployment
+	data, err := client.Deployments(ctx, logger)
+	if err != nil {
+		return Deployment{}, fmt.Errorf("error getting deployments: %w", err)
+	}
+	var deployment *Deployment
+	for _, candidate := range data.Deployments {
+		if candidate.Name == deploymentName {
+			deployment = &candidate
+			break
+		}
+	}
+	if deployment == nil {
+		return Deployment{}, fmt.Errorf("deployment [%s] not found: %w", deploymentName, ErrInvalidDeployment)
+	}
+	deploymentChainID, success := new(big.Int).SetString(deployment.ChainID, 10)
+	if !success {
+		return Deployment{}, fmt.Errorf("deployment [%s] has invalid chain ID [%s]", deploymentName, deployment.ChainID)
+	}
+
+	// Compare it with the client
+	clientChainID, err := chain.ChainID(ctx)
+	if err != nil {
+		return Deployment{}, fmt.Errorf("error getting chain ID from Execution client: %w", err)
+	}
+	if deploymentChainID.Cmp(clientChainID) != 0 {
+		return Deployment{}, &ErrChainIDMismatch{
+			Deployment:        deploymentName,
+			DeploymentChainID: deploymentChainID,
+			ClientChainID:     clientChainID,
+		}
+	}
+	SafeDebugLog(logger, "Validated deployment chain ID",
+		"deployment", deploymentName,
+		"chainID", clientChainID.String(),
+	)
+	return *deployment, nil
+}
@@ -0,0 +1,153 @@
+package exits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// Default number of exit messages to upload in a single request
+	DefaultBatchSize int = 10
+)
+
+// Binding for a module's validators routes, used to find the validators NodeSet is missing exit messages for and to upload them
+type ExitMessageClient interface {
+	// Get the pubkeys of the node's validators that NodeSet doesn't have an exit message for yet
+	GetValidatorsMissingExitMessages(ctx context.Context, logger *slog.Logger) ([]beacon.ValidatorPubkey, error)
+
+	// Upload encrypted exit messages for the provided validators
+	UploadExitMessages(ctx context.Context, logger *slog.Logger, exitData []common.EncryptedExitData) error
+}
+
+// Creates a signed voluntary exit message for the validator with the provided pubkey at the provided epoch
+type ExitSigner func(ctx context.Context, pubkey beacon.ValidatorPubkey, epoch uint64) (common.ExitMessage, error)
+
+// Gets the epoch to sign exit messages for, typically the current epoch on the Beacon chain
+type EpochProvider func(ctx context.Context) (uint64, error)
+
+// Reconciles the exit messages NodeSet has for a node's validators with the validators it's missing them for
+type ExitMessageReconciler struct {
+	client        ExitMessageClient
+	signer        ExitSigner
+	getEpoch      EpochProvider
+	encryptionKey string
+	batchSize     int
+}
+
+// Creates a new exit message reconciler.
+// encryptionKey is the age public key of the NodeSet service that the exit messages will be encrypted for.
+// If batchSize is 0 or less, DefaultBatchSize will be used.
+func NewExitMessageReconciler(client ExitMessageClient, signer ExitSigner, getEpoch EpochProvider, encryptionKey string, batchSize int) *ExitMessageReconciler {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &ExitMessageReconciler{
+		client:        client,
+		signer:        signer,
+		getEpoch:      getEpoch,
+		encryptionKey: encryptionKey,
+		batchSize:     batchSize,
+	}
+}
+
+// Finds the validators NodeSet is missing exit messages for, then signs, encrypts, and uploads exit messages for them.
+// Failures for individual validators are reported in the result rather than as an error; the returned error is only set
+// if the missing validators couldn't be retrieved or if the session became invalid during the upload.
+func (r *ExitMessageReconciler) Reconcile(ctx context.Context, logger *slog.Logger) (ReconcileResult, error) {
+	// Get the validators that need exit messages
+	pubkeys, err := r.client.GetValidatorsMissingExitMessages(ctx, logger)
+	if err != nil {
+		return ReconcileResult{}, fmt.Errorf("error getting validators missing exit messages: %w", err)
+	}
+	if len(pubkeys) == 0 {
+		return ReconcileResult{}, nil
+	}
+
+	// Get the epoch to sign for
+	epoch, err := r.getEpoch(ctx)
+	if err != nil {
+		return ReconcileResult{}, fmt.Errorf("error getting exit epoch: %w", err)
+	}
+	result := ReconcileResult{
+		Epoch:   epoch,
+		Results: make([]ExitUploadResult, 0, len(pubkeys)),
+	}
+	common.SafeDebugLog(logger, "Reconciling exit messages",
+		"count", len(pubkeys),
+		"epoch", epoch,
+	)
+
+	// Sign and encrypt the exit messages
+	pending := make([]common.EncryptedExitData, 0, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		exitMessage, err := r.signer(ctx, pubkey, epoch)
+		if err != nil {
+			result.Results = append(result.Results, ExitUploadResult{
+				Pubkey: pubkey,
+				Status: ExitUploadStatus_SigningFailed,
+				Error:  err,
+			})
+			continue
+		}
+		encryptedMessage, err := common.EncryptSignedExitMessage(exitMessage, r.encryptionKey)
+		if err != nil {
+			result.Results = append(result.Results, ExitUploadResult{
+				Pubkey: pubkey,
+				Status: ExitUploadStatus_EncryptionFailed,
+				Error:  err,
+			})
+			continue
+		}
+		pending = append(pending, common.EncryptedExitData{
+			Pubkey:      pubkey.HexWithPrefix(),
+			ExitMessage: encryptedMessage,
+		})
+	}
+
+	// Upload them in batches
+	for start := 0; start < len(pending); start += r.batchSize {
+		end := min(start+r.batchSize, len(pending))
+		err := r.uploadBatch(ctx, logger, pending[start:end], &result)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Uploads a batch of exit messages. If the batch is rejected, each message in it is uploaded individually so one bad
+// message doesn't prevent the rest from being uploaded.
+func (r *ExitMessageReconciler) uploadBatch(ctx context.Context, logger *slog.Logger, batch []common.EncryptedExitData, result *ReconcileResult) error {
+	err := r.client.UploadExitMessages(ctx, logger, batch)
+	if err == nil {
+		for _, data := range batch {
+			result.Results = append(result.Results, newExitUploadResult(data.Pubkey, ExitUploadStatus_Uploaded, nil))
+		}
+		return nil
+	}
+	if errors.Is(err, common.ErrInvalidSession) {
+		return err
+	}
+	if len(batch) == 1 {
+		result.Results = append(result.Results, newExitUploadResult(batch[0].Pubkey, ExitUploadStatus_UploadFailed, err))
+		return nil
+	}
+
+	// Retry each one individually
+	common.SafeDebugLog(logger, "Exit message batch upload failed, retrying individually",
+		"count", len(batch),
+		"error", err,
+	)
+	for _, data := range batch {
+		err := r.uploadBatch(ctx, logger, []common.EncryptedExitData{data}, result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package exits
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// The outcome of reconciling a single validator's exit message
+type ExitUploadStatus string
+
+const (
+	// The exit message was uploaded to NodeSet
+	ExitUploadStatus_Uploaded ExitUploadStatus = "UPLOADED"
+
+	// The signer couldn't create an exit message for the validator
+	ExitUploadStatus_SigningFailed ExitUploadStatus = "SIGNING_FAILED"
+
+	// The signed exit message couldn't be encrypted
+	ExitUploadStatus_EncryptionFailed ExitUploadStatus = "ENCRYPTION_FAILED"
+
+	// NodeSet rejected the exit message
+	ExitUploadStatus_UploadFailed ExitUploadStatus = "UPLOAD_FAILED"
+)
+
+// Result of reconciling a single validator's exit message
+type ExitUploadResult struct {
+	// The validator's pubkey
+	Pubkey beacon.ValidatorPubkey
+
+	// What happened to the validator's exit message
+	Status ExitUploadStatus
+
+	// The error that caused the failure, if the status isn't ExitUploadStatus_Uploaded
+	Error error
+}
+
+// Result of a full reconciliation pass
+type ReconcileResult struct {
+	// The epoch the exit messages were signed for
+	Epoch uint64
+
+	// Per-validator results
+	Results []ExitUploadResult
+}
+
+// Get the pubkeys of the validators that had their exit messages uploaded
+func (r ReconcileResult) Uploaded() []beacon.ValidatorPubkey {
+	pubkeys := []beacon.ValidatorPubkey{}
+	for _, result := range r.Results {
+		if result.Status == ExitUploadStatus_Uploaded {
+			pubkeys = append(pubkeys, result.Pubkey)
+		}
+	}
+	return pubkeys
+}
+
+// Get the results for validators that didn't have their exit messages uploaded
+func (r ReconcileResult) Failures() []ExitUploadResult {
+	failures := []ExitUploadResult{}
+	for _, result := range r.Results {
+		if result.Status != ExitUploadStatus_Uploaded {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Get an error that combines all of the per-validator failures, or nil if there weren't any
+func (r ReconcileResult) Err() error {
+	errs := []error{}
+	for _, failure := range r.Failures() {
+		errs = append(errs, fmt.Errorf("validator %s: %w", failure.Pubkey.HexWithPrefix(), failure.Error))
+	}
+	return errors.Join(errs...)
+}
+
+// Create a new result for a pubkey that's already been serialized
+func newExitUploadResult(pubkeyString string, status ExitUploadStatus, err error) ExitUploadResult {
+	// The pubkey came from a ValidatorPubkey so this can't fail
+	pubkey, _ := beacon.HexToValidatorPubkey(pubkeyString)
+	return ExitUploadResult{
+		Pubkey: pubkey,
+		Status: status,
+		Error:  err,
+	}
+}
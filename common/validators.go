@@ -0,0 +1,24 @@
+package common
+
+// Lifecycle status of a validator registered with NodeSet, as reported by the v3 API
+type ValidatorLifecycleStatus string
+
+const (
+	// The validator has been registered with NodeSet, but its deposit hasn't been made yet
+	ValidatorLifecycleStatus_Registered ValidatorLifecycleStatus = "REGISTERED"
+
+	// The validator's deposit has been made, but it isn't active on Beacon yet
+	ValidatorLifecycleStatus_Deposited ValidatorLifecycleStatus = "DEPOSITED"
+
+	// The validator is active on Beacon
+	ValidatorLifecycleStatus_Active ValidatorLifecycleStatus = "ACTIVE"
+
+	// The validator has had an exit processed on Beacon but hasn't reached its exit epoch yet
+	ValidatorLifecycleStatus_Exiting ValidatorLifecycleStatus = "EXITING"
+
+	// The validator has exited the Beacon chain
+	ValidatorLifecycleStatus_Exited ValidatorLifecycleStatus = "EXITED"
+
+	// The validator was slashed on Beacon
+	ValidatorLifecycleStatus_Slashed ValidatorLifecycleStatus = "SLASHED"
+)
@@ -0,0 +1,16 @@
+package api
+
+import (
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+type AdminDepositRootData struct {
+	// The Beacon deposit contract's current deposit root
+	DepositRoot ethcommon.Hash `json:"depositRoot"`
+
+	// Number of deposits made to the contract
+	DepositCount uint64 `json:"depositCount"`
+
+	// The current block number
+	BlockNumber uint64 `json:"blockNumber"`
+}
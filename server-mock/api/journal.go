@@ -0,0 +1,95 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+)
+
+// An API request recorded by the mock, along with its response
+type JournalEntry struct {
+	// Sequence number of the request, starting at 1 and never reused (even after the journal is cleared)
+	ID uint64 `json:"id"`
+
+	// The time the request was received
+	Time time.Time `json:"time"`
+
+	// The HTTP method
+	Method string `json:"method"`
+
+	// The template of the route that handled the request, such as /api/v3/modules/stakewise/{deployment}/{vault}/validators
+	Route string `json:"route"`
+
+	// The request path
+	Path string `json:"path"`
+
+	// The raw query string
+	Query string `json:"query,omitempty"`
+
+	// The path arguments parsed from the route
+	PathArgs map[string]string `json:"pathArgs,omitempty"`
+
+	// The node that owns the session used to authorize the request, if there was one
+	NodeAddress *ethcommon.Address `json:"nodeAddress,omitempty"`
+
+	// The request body, if it was valid JSON
+	Body json.RawMessage `json:"body,omitempty"`
+
+	// The HTTP status code of the response
+	StatusCode int `json:"statusCode"`
+
+	// The error key of the response, if there was one
+	Error string `json:"error,omitempty"`
+
+	// The message of the response
+	Message string `json:"message,omitempty"`
+}
+
+// Filter for a journal query. Empty fields match everything.
+type JournalFilter struct {
+	// Only include requests with this HTTP method
+	Method string `json:"method,omitempty"`
+
+	// Only include requests whose route template contains this string
+	Route string `json:"route,omitempty"`
+
+	// Only include requests authorized by this node
+	Node *ethcommon.Address `json:"node,omitempty"`
+
+	// Only include requests that were responded to with this status code
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Only include requests that were responded to with this error key
+	Error string `json:"error,omitempty"`
+}
+
+// Check if a recorded request matches the filter
+func (f JournalFilter) Matches(entry JournalEntry) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, entry.Method) {
+		return false
+	}
+	if f.Route != "" && !strings.Contains(entry.Route, f.Route) {
+		return false
+	}
+	if f.Node != nil && (entry.NodeAddress == nil || *entry.NodeAddress != *f.Node) {
+		return false
+	}
+	if f.StatusCode != 0 && f.StatusCode != entry.StatusCode {
+		return false
+	}
+	if f.Error != "" && f.Error != entry.Error {
+		return false
+	}
+	return true
+}
+
+// Response to a journal request
+type JournalData struct {
+	// The max number of requests the journal holds before dropping the oldest ones
+	Capacity int `json:"capacity"`
+
+	// The requests that matched the filter, oldest first
+	Entries []JournalEntry `json:"entries"`
+}
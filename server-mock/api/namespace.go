@@ -0,0 +1,7 @@
+package api
+
+// Response to a namespace list request
+type NamespacesData struct {
+	// The names of the namespaces, in alphabetical order
+	Namespaces []string `json:"namespaces"`
+}
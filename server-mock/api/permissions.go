@@ -0,0 +1,25 @@
+package api
+
+import (
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// The StakeWise module
+	PermissionModule_StakeWise string = "stakewise"
+
+	// The Constellation module
+	PermissionModule_Constellation string = "constellation"
+)
+
+// Something a user can be allowed or denied access to: a whole module, one of its deployments, or a single StakeWise vault
+type Permission struct {
+	// The module the permission belongs to
+	Module string `json:"module"`
+
+	// The deployment of the module, or empty for the whole module
+	Deployment string `json:"deployment,omitempty"`
+
+	// The StakeWise vault on the deployment, or nil for the whole deployment
+	Vault *ethcommon.Address `json:"vault,omitempty"`
+}
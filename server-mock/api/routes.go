@@ -4,20 +4,74 @@ const (
 	// API routes
 	DevPath string = "dev/"
 
+	// Execution client JSON-RPC route
+	EthRpcPath string = "eth"
+
+	// Probe routes, only served by the root of the server
+	HealthPath string = "health"
+	ReadyPath  string = "ready"
+
 	// Admin routes
-	AdminAddConstellationDeploymentPath       string = "add-constellation-deployment"
-	AdminAddStakeWiseDeploymentPath           string = "add-stakewise-deployment"
-	AdminAddStakeWiseVaultPath                string = "add-stakewise-vault"
-	AdminSnapshotPath                         string = "snapshot"
-	AdminRevertPath                           string = "revert"
-	AdminCycleSetPath                         string = "cycle-set"
-	AdminAddUserPath                          string = "add-user"
-	AdminWhitelistNodePath                    string = "whitelist-node"
-	AdminRegisterNodePath                     string = "register-node"
-	AdminAddVaultPath                         string = "add-vault"
-	AdminSetConstellationPrivateKeyPath       string = "constellation/private-key"
-	AdminIncrementWhitelistNoncePath          string = "constellation/increment-whitelist-nonce"
-	AdminIncrementSuperNodeNoncePath          string = "constellation/increment-supernode-nonce"
-	AdminSetEncryptionKeyPath                 string = "set-encryption-key"
-	AdminConstellationSetValidatorForMinipool string = "constellation/set-validator-for-minipool"
+	AdminAddConstellationDeploymentPath         string = "add-constellation-deployment"
+	AdminAddStakeWiseDeploymentPath             string = "add-stakewise-deployment"
+	AdminAddStakeWiseVaultPath                  string = "add-stakewise-vault"
+	AdminSnapshotPath                           string = "snapshot"
+	AdminRevertPath                             string = "revert"
+	AdminCycleSetPath                           string = "cycle-set"
+	AdminAddUserPath                            string = "add-user"
+	AdminWhitelistNodePath                      string = "whitelist-node"
+	AdminRegisterNodePath                       string = "register-node"
+	AdminAddVaultPath                           string = "add-vault"
+	AdminSetConstellationPrivateKeyPath         string = "constellation/private-key"
+	AdminIncrementWhitelistNoncePath            string = "constellation/increment-whitelist-nonce"
+	AdminIncrementSuperNodeNoncePath            string = "constellation/increment-supernode-nonce"
+	AdminSetEncryptionKeyPath                   string = "set-encryption-key"
+	AdminConstellationSetValidatorForMinipool   string = "constellation/set-validator-for-minipool"
+	AdminBeaconAdvanceClockPath                 string = "beacon/advance-clock"
+	AdminBeaconAddValidatorPath                 string = "beacon/add-validator"
+	AdminBeaconExitValidatorPath                string = "beacon/exit-validator"
+	AdminBeaconSlashValidatorPath               string = "beacon/slash-validator"
+	AdminEthDepositRootPath                     string = "eth/deposit-root"
+	AdminEthDepositPath                         string = "eth/deposit"
+	AdminSetConstellationChainBackendPath       string = "constellation/chain-backend"
+	AdminStatePath                              string = "state"
+	AdminJournalPath                            string = "journal"
+	AdminClearJournalPath                       string = "journal/clear"
+	AdminListSnapshotsPath                      string = "snapshots"
+	AdminDeleteSnapshotPath                     string = "snapshot/delete"
+	AdminExportSnapshotPath                     string = "snapshot/export"
+	AdminImportSnapshotPath                     string = "snapshot/import"
+	AdminDiffSnapshotsPath                      string = "snapshot/diff"
+	AdminRemoveUserPath                         string = "remove-user"
+	AdminRemoveNodePath                         string = "remove-node"
+	AdminDeregisterNodePath                     string = "deregister-node"
+	AdminRemoveStakeWiseDeploymentPath          string = "remove-stakewise-deployment"
+	AdminRemoveConstellationDeploymentPath      string = "remove-constellation-deployment"
+	AdminRemoveStakeWiseVaultPath               string = "remove-stakewise-vault"
+	AdminSetMaxValidatorsPerUserPath            string = "set-max-validators-per-user"
+	AdminStakeWiseClearExitMessagePath          string = "stakewise/clear-exit-message"
+	AdminRemoveConstellationWhitelistedNodePath string = "constellation/remove-whitelisted-node"
+	AdminConstellationClearExitMessagePath      string = "constellation/clear-exit-message"
+	AdminSetPermissionPath                      string = "set-permission"
+	AdminSetStakeWiseVaultBalancePath           string = "stakewise/set-vault-balance"
+	AdminSetMinipoolLimitPath                   string = "constellation/set-minipool-limit"
+	AdminSetRequireExitMessagesPath             string = "constellation/set-require-exit-messages"
+	AdminExpireSessionsPath                     string = "expire-sessions"
+	AdminSetStakeWiseCyclePolicyPath            string = "stakewise/set-cycle-policy"
+	AdminClearStakeWiseCyclePolicyPath          string = "stakewise/clear-cycle-policy"
+	AdminStakeWiseCyclePoliciesPath             string = "stakewise/cycle-policies"
+	AdminStartScenarioPath                      string = "scenario/start"
+	AdminStopScenarioPath                       string = "scenario/stop"
+	AdminScenarioStatusPath                     string = "scenario/status"
+
+	// Namespace admin routes, only served by the root of the server
+	AdminCreateNamespacePath string = "namespace/create"
+	AdminDeleteNamespacePath string = "namespace/delete"
+	AdminListNamespacesPath  string = "namespaces"
+
+	// Prefix of the routes for a namespace; everything the server serves is available under it, like [/ns/<name>/api]
+	NamespacePrefix string = "ns/"
+
+	// Header that routes a request to a namespace without changing its path
+	NamespaceHeader string = "X-NodeSet-Mock-Namespace"
 )
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// A duration that's written as a Go duration string like [5s] or [10m] in JSON
+type Duration time.Duration
+
+// Serialize the duration as a Go duration string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Deserialize the duration from a Go duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration [%s]: %w", string(text), err)
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// A script of admin actions the mock runs on its own, either on a schedule or in response to API requests
+type Scenario struct {
+	// The scenario's name, for status reports
+	Name string `json:"name"`
+
+	// The steps of the scenario, which all run independently
+	Steps []ScenarioStep `json:"steps"`
+}
+
+// A set of actions in a scenario and the trigger that runs them. Steps without On or Every run once, After the
+// scenario starts. Steps with Every run After the scenario starts (or Every, if After isn't set) and then repeat.
+// Steps with On run After each API request that matches the filter.
+type ScenarioStep struct {
+	// The step's name, for status reports
+	Name string `json:"name"`
+
+	// Run the step in response to API requests that match this filter
+	On *JournalFilter `json:"on,omitempty"`
+
+	// The delay before the step runs, from the start of the scenario or from the request that triggered it
+	After Duration `json:"after,omitempty"`
+
+	// The interval to repeat the step at
+	Every Duration `json:"every,omitempty"`
+
+	// The max number of times to run the step, or 0 for no limit. Steps without On or Every always run once.
+	Times int `json:"times,omitempty"`
+
+	// The actions to run, in order. If one fails, the rest are skipped until the step runs again.
+	Actions []ScenarioAction `json:"actions"`
+}
+
+// An admin route called by a scenario step
+type ScenarioAction struct {
+	// The admin route to call, relative to the admin routes, like [cycle-set]
+	Route string `json:"route"`
+
+	// The query parameters to call the route with. They're Go templates; for steps triggered by a request, the
+	// template data is the request's JournalEntry with Body decoded, so {{index .PathArgs "vault"}} is the vault
+	// the request was for.
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// The progress of a scenario step
+type ScenarioStepStatus struct {
+	// The step's name
+	Name string `json:"name"`
+
+	// The number of times the step has run
+	Runs int `json:"runs"`
+
+	// The number of runs that have been triggered by requests but haven't happened yet
+	Pending int `json:"pending"`
+
+	// The next time the step is scheduled to run, if it is
+	NextRun *time.Time `json:"nextRun,omitempty"`
+
+	// True if the step has run as many times as it ever will
+	Done bool `json:"done"`
+}
+
+// A record of an action a scenario ran
+type ScenarioExecution struct {
+	// The time the action ran
+	Time time.Time `json:"time"`
+
+	// The name of the step the action belongs to
+	Step string `json:"step"`
+
+	// The admin route the action called
+	Route string `json:"route"`
+
+	// The query parameters the route was called with, after filling in the templates
+	Params map[string]string `json:"params,omitempty"`
+
+	// The HTTP status code the route responded with
+	StatusCode int `json:"statusCode"`
+
+	// The message the route responded with, or the reason the action couldn't be run
+	Message string `json:"message,omitempty"`
+}
+
+// Response to a scenario status request
+type ScenarioStatusData struct {
+	// The name of the scenario, or empty if one was never started
+	Name string `json:"name"`
+
+	// True if the scenario is running
+	Running bool `json:"running"`
+
+	// The time the scenario started, if one was started
+	StartTime *time.Time `json:"startTime,omitempty"`
+
+	// The progress of each step
+	Steps []ScenarioStepStatus `json:"steps"`
+
+	// The actions the scenario has run, oldest first. Only the most recent ones are kept.
+	Executions []ScenarioExecution `json:"executions"`
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"time"
+)
+
+// Kinds of changes in a state diff
+type StateChangeKind string
+
+const (
+	// The entity or field exists in the newer state but not the older one
+	StateChangeKind_Added StateChangeKind = "added"
+
+	// The entity or field exists in the older state but not the newer one
+	StateChangeKind_Removed StateChangeKind = "removed"
+
+	// The field exists in both states but has a different value
+	StateChangeKind_Changed StateChangeKind = "changed"
+)
+
+// Info about a database snapshot
+type SnapshotInfo struct {
+	// The snapshot's name
+	Name string `json:"name"`
+
+	// The time the snapshot was taken or imported
+	Time time.Time `json:"time"`
+}
+
+// Response to a snapshot list request
+type SnapshotsData struct {
+	// The snapshots, oldest first
+	Snapshots []SnapshotInfo `json:"snapshots"`
+}
+
+// A single difference between two database states
+type StateChange struct {
+	// Path of the entity or field that changed, such as stakewise/<deployment>/vaults/<vault>/validators/<pubkey>/exitMessageUploaded
+	Path string `json:"path"`
+
+	// The kind of change
+	Kind StateChangeKind `json:"kind"`
+
+	// The value in the older state, if there was one
+	Old string `json:"old,omitempty"`
+
+	// The value in the newer state, if there is one
+	New string `json:"new,omitempty"`
+}
+
+// Structured diff between two database states
+type StateDiffData struct {
+	// Name of the older snapshot, or empty for the live database
+	From string `json:"from"`
+
+	// Name of the newer snapshot, or empty for the live database
+	To string `json:"to"`
+
+	// The changes, sorted by path. Fields of an added or removed entity aren't listed separately.
+	Changes []StateChange `json:"changes"`
+}
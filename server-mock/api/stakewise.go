@@ -0,0 +1,52 @@
+package api
+
+import (
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// A policy for cycling a StakeWise vault's deposit data sets automatically, the way nodeset.io does
+type StakeWiseCyclePolicy struct {
+	// How often to cycle a new set
+	Interval Duration `json:"interval"`
+
+	// The max number of each user's validators to include in a set
+	UserLimit int `json:"userLimit"`
+
+	// The minimum number of deposit data entries waiting to be included before a set is cycled
+	MinPending int `json:"minPending"`
+
+	// How long after a set is cycled to mark its validators as active
+	ActivationDelay Duration `json:"activationDelay"`
+}
+
+// The progress of a vault's cycle policy
+type StakeWiseCyclePolicyStatus struct {
+	// The vault's deployment
+	Deployment string `json:"deployment"`
+
+	// The vault's address
+	Vault ethcommon.Address `json:"vault"`
+
+	// The policy
+	Policy StakeWiseCyclePolicy `json:"policy"`
+
+	// The number of sets the policy has cycled
+	Cycles int `json:"cycles"`
+
+	// The time the policy last cycled a set, if it has
+	LastCycle *time.Time `json:"lastCycle,omitempty"`
+
+	// The next time the policy will check whether to cycle a set
+	NextCycle time.Time `json:"nextCycle"`
+
+	// The number of cycled sets whose validators haven't been marked as active yet
+	PendingActivations int `json:"pendingActivations"`
+}
+
+// Response to a cycle policy list request
+type StakeWiseCyclePoliciesData struct {
+	// The vaults' policies, sorted by deployment and vault
+	Policies []StakeWiseCyclePolicyStatus `json:"policies"`
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"math/big"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Filter for a state query. Empty fields match everything.
+type StateFilter struct {
+	// Only include this user and its nodes
+	User string `json:"user,omitempty"`
+
+	// Only include this node
+	Node *ethcommon.Address `json:"node,omitempty"`
+
+	// Only include this StakeWise vault
+	Vault *ethcommon.Address `json:"vault,omitempty"`
+
+	// Only include this validator
+	Pubkey *beacon.ValidatorPubkey `json:"pubkey,omitempty"`
+}
+
+// Full state of the mock's database
+type StateData struct {
+	Users         []UserState                    `json:"users"`
+	Sessions      []SessionState                 `json:"sessions"`
+	StakeWise     []StakeWiseDeploymentState     `json:"stakeWise"`
+	Constellation []ConstellationDeploymentState `json:"constellation"`
+	Eth           EthState                       `json:"eth"`
+	Beacon        BeaconState                    `json:"beacon"`
+}
+
+// A user account
+type UserState struct {
+	Email              string       `json:"email"`
+	Nodes              []NodeState  `json:"nodes"`
+	RevokedPermissions []Permission `json:"revokedPermissions"`
+}
+
+// A node belonging to a user
+type NodeState struct {
+	Address    ethcommon.Address `json:"address"`
+	Registered bool              `json:"registered"`
+}
+
+// An authorization session
+type SessionState struct {
+	Nonce       string            `json:"nonce"`
+	Token       string            `json:"token"`
+	NodeAddress ethcommon.Address `json:"nodeAddress"`
+	LoggedIn    bool              `json:"loggedIn"`
+}
+
+// A StakeWise deployment
+type StakeWiseDeploymentState struct {
+	ID      string                `json:"id"`
+	ChainID *big.Int              `json:"chainId"`
+	Vaults  []StakeWiseVaultState `json:"vaults"`
+}
+
+// A StakeWise vault
+type StakeWiseVaultState struct {
+	Name                      string                    `json:"name"`
+	Address                   ethcommon.Address         `json:"address"`
+	MaxValidatorsPerUser      int                       `json:"maxValidatorsPerUser"`
+	Balance                   *big.Int                  `json:"balance"`
+	LatestDepositDataSetIndex int                       `json:"latestDepositDataSetIndex"`
+	Validators                []StakeWiseValidatorState `json:"validators"`
+}
+
+// A validator in a StakeWise vault
+type StakeWiseValidatorState struct {
+	Pubkey              beacon.ValidatorPubkey          `json:"pubkey"`
+	NodeAddress         ethcommon.Address               `json:"nodeAddress"`
+	UploadedToStakeWise bool                            `json:"uploadedToStakeWise"`
+	DepositDataUsed     bool                            `json:"depositDataUsed"`
+	MarkedActive        bool                            `json:"markedActive"`
+	HasDepositEvent     bool                            `json:"hasDepositEvent"`
+	BeaconDepositRoot   ethcommon.Hash                  `json:"beaconDepositRoot"`
+	RegistrationTime    time.Time                       `json:"registrationTime"`
+	Status              common.ValidatorLifecycleStatus `json:"status,omitempty"`
+	ExitMessageUploaded bool                            `json:"exitMessageUploaded"`
+	SignedExit          *common.ExitMessage             `json:"signedExit,omitempty"`
+}
+
+// A Constellation deployment
+type ConstellationDeploymentState struct {
+	ID               string                        `json:"id"`
+	ChainID          *big.Int                      `json:"chainId"`
+	WhitelistAddress ethcommon.Address             `json:"whitelistAddress"`
+	SuperNodeAddress ethcommon.Address             `json:"superNodeAddress"`
+	Whitelist        []ConstellationWhitelistEntry `json:"whitelist"`
+	Nodes            []ConstellationNodeState      `json:"nodes"`
+
+	// Minipool rules
+	MinipoolLimit       int                       `json:"minipoolLimit"`
+	RequireExitMessages bool                      `json:"requireExitMessages"`
+	UserMinipoolLimits  map[string]int            `json:"userMinipoolLimits"`
+	NodeMinipoolLimits  map[ethcommon.Address]int `json:"nodeMinipoolLimits"`
+}
+
+// A user's node on the Constellation whitelist
+type ConstellationWhitelistEntry struct {
+	User        string            `json:"user"`
+	NodeAddress ethcommon.Address `json:"nodeAddress"`
+}
+
+// A node's Constellation info
+type ConstellationNodeState struct {
+	Address        ethcommon.Address            `json:"address"`
+	WhitelistNonce uint64                       `json:"whitelistNonce"`
+	SuperNodeNonce uint64                       `json:"superNodeNonce"`
+	Minipools      []ConstellationMinipoolState `json:"minipools"`
+}
+
+// A minipool created by a Constellation node
+type ConstellationMinipoolState struct {
+	Address           ethcommon.Address               `json:"address"`
+	Pubkey            *beacon.ValidatorPubkey         `json:"pubkey,omitempty"`
+	BeaconDepositRoot *ethcommon.Hash                 `json:"beaconDepositRoot,omitempty"`
+	RegistrationTime  *time.Time                      `json:"registrationTime,omitempty"`
+	Status            common.ValidatorLifecycleStatus `json:"status,omitempty"`
+	ExitMessage       *common.ExitMessage             `json:"exitMessage,omitempty"`
+}
+
+// The simulated Execution layer
+type EthState struct {
+	ChainID      *big.Int       `json:"chainId"`
+	BlockNumber  uint64         `json:"blockNumber"`
+	DepositCount uint64         `json:"depositCount"`
+	DepositRoot  ethcommon.Hash `json:"depositRoot"`
+}
+
+// The simulated Beacon chain
+type BeaconState struct {
+	GenesisTime  time.Time              `json:"genesisTime"`
+	CurrentSlot  uint64                 `json:"currentSlot"`
+	CurrentEpoch uint64                 `json:"currentEpoch"`
+	Validators   []BeaconValidatorState `json:"validators"`
+}
+
+// A validator on the simulated Beacon chain
+type BeaconValidatorState struct {
+	Pubkey          beacon.ValidatorPubkey `json:"pubkey"`
+	Index           uint64                 `json:"index"`
+	Status          string                 `json:"status"`
+	ActivationEpoch uint64                 `json:"activationEpoch"`
+	ExitEpoch       uint64                 `json:"exitEpoch"`
+	Slashed         bool                   `json:"slashed"`
+}
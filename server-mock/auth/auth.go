@@ -26,6 +26,7 @@ const (
 var (
 	ErrAuthHeader        error = errors.New("invalid auth header")
 	ErrMissingAuthHeader error = errors.New("missing auth header")
+	ErrInvalidSignature  error = errors.New("invalid signature")
 )
 
 // Creates a signature for node registration
@@ -45,10 +46,10 @@ func VerifyRegistrationSignature(email string, nodeAddress common.Address, signa
 	message := fmt.Sprintf(nodeRegistrationMessageFormat, email, nodeAddress.Hex())
 	address, err := getAddressFromSignature([]byte(message), signature)
 	if err != nil {
-		return fmt.Errorf("error verifying signature: %w", err)
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err.Error())
 	}
 	if address != nodeAddress {
-		return errors.New("signature does not match node address")
+		return fmt.Errorf("%w: signature does not match node address", ErrInvalidSignature)
 	}
 	return nil
 }
@@ -58,10 +59,10 @@ func VerifyLoginSignature(nonce string, nodeAddress common.Address, signature []
 	message := fmt.Sprintf(core.LoginMessageFormat, nonce, nodeAddress.Hex())
 	address, err := getAddressFromSignature([]byte(message), signature)
 	if err != nil {
-		return fmt.Errorf("error verifying signature: %w", err)
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err.Error())
 	}
 	if address != nodeAddress {
-		return errors.New("signature does not match node address")
+		return fmt.Errorf("%w: signature does not match node address", ErrInvalidSignature)
 	}
 	return nil
 }
@@ -94,6 +95,10 @@ func AddAuthorizationHeader(request *http.Request, sessionToken string) {
 
 // Gets the address of the private key used to sign a message from a signature
 func getAddressFromSignature(message []byte, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature has %d bytes but expected %d", len(signature), crypto.SignatureLength)
+	}
+
 	// Fix the ECDSA 'v' (see https://medium.com/mycrypto/the-magic-of-digital-signatures-on-ethereum-98fe184dc9c7#:~:text=The%20version%20number,2%E2%80%9D%20was%20introduced)
 	if signature[crypto.RecoveryIDOffset] >= 4 {
 		signature[crypto.RecoveryIDOffset] -= 27
@@ -12,18 +12,25 @@ import (
 	nsutil "github.com/nodeset-org/nodeset-client-go/utils"
 
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
 	"github.com/rocket-pool/node-manager-core/utils"
 	"github.com/stretchr/testify/require"
 )
 
+// Fixtures matching the testkit package, which can't be imported here since it depends on this package through the database
+const (
+	testPrivateKey      string = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" // The first Hardhat account
+	testVaultAddressHex string = "0x57ace215eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	testNetwork         string = "localtest"
+	testEmail           string = "user_0@test.com"
+)
+
 // =============
 // === Tests ===
 // =============
 
 func TestRecoverPubkey(t *testing.T) {
 	// Get a private key
-	privateKey, err := test.GetEthPrivateKey(0)
+	privateKey, err := crypto.HexToECDSA(testPrivateKey)
 	if err != nil {
 		t.Fatalf("error getting private key: %v", err)
 	}
@@ -47,7 +54,7 @@ func TestRecoverPubkey(t *testing.T) {
 
 func TestGoodRequest(t *testing.T) {
 	// Get a private key
-	privateKey, err := test.GetEthPrivateKey(0)
+	privateKey, err := crypto.HexToECDSA(testPrivateKey)
 	if err != nil {
 		t.Fatalf("error getting private key: %v", err)
 	}
@@ -57,10 +64,10 @@ func TestGoodRequest(t *testing.T) {
 	t.Logf("Constructed private key, pubkey = %s", pubkey.Hex())
 
 	// Create a request with the proper header
-	vault := utils.RemovePrefix(test.StakeWiseVaultAddressHex)
+	vault := utils.RemovePrefix(testVaultAddressHex)
 	params := map[string]string{
 		"vault":   vault,
-		"network": test.Network,
+		"network": testNetwork,
 	}
 	request, expectedToken, err := generateRequest(privateKey, http.MethodGet, nil, params, "deposit-data", "meta")
 	if err != nil {
@@ -79,7 +86,7 @@ func TestGoodRequest(t *testing.T) {
 
 func TestRegistration(t *testing.T) {
 	// Get a private key
-	privateKey, err := test.GetEthPrivateKey(0)
+	privateKey, err := crypto.HexToECDSA(testPrivateKey)
 	if err != nil {
 		t.Fatalf("error getting private key: %v", err)
 	}
@@ -89,7 +96,7 @@ func TestRegistration(t *testing.T) {
 	t.Logf("Constructed private key, pubkey = %s", pubkey.Hex())
 
 	// Sign a registration message
-	email := test.User0Email
+	email := testEmail
 	signature, err := GetSignatureForRegistration(email, pubkey, privateKey, apiv0.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	t.Logf("Signed registration message, signature = %x", signature)
@@ -102,7 +109,7 @@ func TestRegistration(t *testing.T) {
 
 func TestLogin(t *testing.T) {
 	// Get a private key
-	privateKey, err := test.GetEthPrivateKey(0)
+	privateKey, err := crypto.HexToECDSA(testPrivateKey)
 	if err != nil {
 		t.Fatalf("error getting private key: %v", err)
 	}
@@ -0,0 +1,558 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Client for the server mock's admin routes
+type AdminClient struct {
+	commonClient *common.CommonNodeSetClient
+}
+
+// Creates a new admin client
+// baseUrl: The base URL of the mock's admin routes, for example [http://localhost:8080/admin]
+func NewAdminClient(baseUrl string, timeout time.Duration) *AdminClient {
+	return &AdminClient{
+		commonClient: common.NewCommonNodeSetClient(baseUrl, timeout),
+	}
+}
+
+// Creates a new admin client that sends its requests with the provided HTTP client, such as one that trusts the
+// mock's self-signed certificate
+// baseUrl: The base URL of the mock's admin routes, for example [https://localhost:8080/admin]
+func NewAdminClientWithHttpClient(baseUrl string, httpClient *http.Client) *AdminClient {
+	return &AdminClient{
+		commonClient: common.NewCommonNodeSetClientWithHttpClient(baseUrl, httpClient),
+	}
+}
+
+// Get the full state of the mock's database
+func (c *AdminClient) GetState(ctx context.Context, logger *slog.Logger) (api.StateData, error) {
+	return c.QueryState(ctx, logger, api.StateFilter{})
+}
+
+// Get the state of the mock's database, restricted to the entities that match the filter
+func (c *AdminClient) QueryState(ctx context.Context, logger *slog.Logger, filter api.StateFilter) (api.StateData, error) {
+	params := map[string]string{}
+	if filter.User != "" {
+		params["user"] = filter.User
+	}
+	if filter.Node != nil {
+		params["node"] = filter.Node.Hex()
+	}
+	if filter.Vault != nil {
+		params["vault"] = filter.Vault.Hex()
+	}
+	if filter.Pubkey != nil {
+		params["pubkey"] = filter.Pubkey.HexWithPrefix()
+	}
+
+	code, response, err := common.SubmitRequest[api.StateData](c.commonClient, ctx, logger, false, http.MethodGet, nil, params, api.AdminStatePath)
+	if err != nil {
+		return api.StateData{}, fmt.Errorf("error requesting mock state: %w", err)
+	}
+	if code != http.StatusOK {
+		return api.StateData{}, fmt.Errorf("nodeset mock responded to state request with code %d: [%s]", code, response.Message)
+	}
+	return response.Data, nil
+}
+
+// Get the state of the mock's database for a single user
+func (c *AdminClient) GetUserState(ctx context.Context, logger *slog.Logger, email string) (api.StateData, error) {
+	return c.QueryState(ctx, logger, api.StateFilter{User: email})
+}
+
+// Get the state of the mock's database for a single node
+func (c *AdminClient) GetNodeState(ctx context.Context, logger *slog.Logger, node ethcommon.Address) (api.StateData, error) {
+	return c.QueryState(ctx, logger, api.StateFilter{Node: &node})
+}
+
+// Get the state of the mock's database for a single StakeWise vault
+func (c *AdminClient) GetVaultState(ctx context.Context, logger *slog.Logger, vault ethcommon.Address) (api.StateData, error) {
+	return c.QueryState(ctx, logger, api.StateFilter{Vault: &vault})
+}
+
+// Get the state of the mock's database for a single validator
+func (c *AdminClient) GetValidatorState(ctx context.Context, logger *slog.Logger, pubkey beacon.ValidatorPubkey) (api.StateData, error) {
+	return c.QueryState(ctx, logger, api.StateFilter{Pubkey: &pubkey})
+}
+
+// Get the API requests the mock has recorded that match the filter, oldest first
+func (c *AdminClient) GetRequests(ctx context.Context, logger *slog.Logger, filter api.JournalFilter) ([]api.JournalEntry, error) {
+	params := map[string]string{}
+	if filter.Method != "" {
+		params["method"] = filter.Method
+	}
+	if filter.Route != "" {
+		params["route"] = filter.Route
+	}
+	if filter.Node != nil {
+		params["node"] = filter.Node.Hex()
+	}
+	if filter.StatusCode != 0 {
+		params["status"] = strconv.Itoa(filter.StatusCode)
+	}
+	if filter.Error != "" {
+		params["error"] = filter.Error
+	}
+
+	code, response, err := common.SubmitRequest[api.JournalData](c.commonClient, ctx, logger, false, http.MethodGet, nil, params, api.AdminJournalPath)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting mock journal: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("nodeset mock responded to journal request with code %d: [%s]", code, response.Message)
+	}
+	return response.Data.Entries, nil
+}
+
+// Remove all of the API requests the mock has recorded
+func (c *AdminClient) ClearRequests(ctx context.Context, logger *slog.Logger) error {
+	return c.submitVoidRequest(ctx, logger, "journal clear", nil, api.AdminClearJournalPath)
+}
+
+// Get info about the mock's database snapshots, oldest first
+func (c *AdminClient) GetSnapshots(ctx context.Context, logger *slog.Logger) ([]api.SnapshotInfo, error) {
+	code, response, err := common.SubmitRequest[api.SnapshotsData](c.commonClient, ctx, logger, false, http.MethodGet, nil, nil, api.AdminListSnapshotsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting mock snapshots: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("nodeset mock responded to snapshot list request with code %d: [%s]", code, response.Message)
+	}
+	return response.Data.Snapshots, nil
+}
+
+// Delete one of the mock's database snapshots
+func (c *AdminClient) DeleteSnapshot(ctx context.Context, logger *slog.Logger, name string) error {
+	params := map[string]string{
+		"name": name,
+	}
+	return c.submitVoidRequest(ctx, logger, "snapshot delete", params, api.AdminDeleteSnapshotPath)
+}
+
+// Have the mock write one of its database snapshots to a file on its machine
+func (c *AdminClient) ExportSnapshot(ctx context.Context, logger *slog.Logger, name string, path string) error {
+	params := map[string]string{
+		"name": name,
+		"path": path,
+	}
+	return c.submitVoidRequest(ctx, logger, "snapshot export", params, api.AdminExportSnapshotPath)
+}
+
+// Have the mock load a database snapshot from a file on its machine
+func (c *AdminClient) ImportSnapshot(ctx context.Context, logger *slog.Logger, name string, path string) error {
+	params := map[string]string{
+		"name": name,
+		"path": path,
+	}
+	return c.submitVoidRequest(ctx, logger, "snapshot import", params, api.AdminImportSnapshotPath)
+}
+
+// Get the changes between two of the mock's database snapshots. An empty name refers to the live database.
+func (c *AdminClient) DiffSnapshots(ctx context.Context, logger *slog.Logger, from string, to string) (api.StateDiffData, error) {
+	params := map[string]string{}
+	if from != "" {
+		params["from"] = from
+	}
+	if to != "" {
+		params["to"] = to
+	}
+	code, response, err := common.SubmitRequest[api.StateDiffData](c.commonClient, ctx, logger, false, http.MethodGet, nil, params, api.AdminDiffSnapshotsPath)
+	if err != nil {
+		return api.StateDiffData{}, fmt.Errorf("error requesting mock snapshot diff: %w", err)
+	}
+	if code != http.StatusOK {
+		return api.StateDiffData{}, fmt.Errorf("nodeset mock responded to snapshot diff request with code %d: [%s]", code, response.Message)
+	}
+	return response.Data, nil
+}
+
+// Create a new namespace with an empty database on the mock. This only works on the root of the server, not from inside
+// another namespace.
+func (c *AdminClient) CreateNamespace(ctx context.Context, logger *slog.Logger, name string) error {
+	params := map[string]string{
+		"name": name,
+	}
+	return c.submitVoidRequest(ctx, logger, "namespace create", params, api.AdminCreateNamespacePath)
+}
+
+// Delete one of the mock's namespaces and everything in it
+func (c *AdminClient) DeleteNamespace(ctx context.Context, logger *slog.Logger, name string) error {
+	params := map[string]string{
+		"name": name,
+	}
+	return c.submitVoidRequest(ctx, logger, "namespace delete", params, api.AdminDeleteNamespacePath)
+}
+
+// Get the names of the mock's namespaces, in alphabetical order
+func (c *AdminClient) GetNamespaces(ctx context.Context, logger *slog.Logger) ([]string, error) {
+	code, response, err := common.SubmitRequest[api.NamespacesData](c.commonClient, ctx, logger, false, http.MethodGet, nil, nil, api.AdminListNamespacesPath)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting mock namespaces: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("nodeset mock responded to namespace list request with code %d: [%s]", code, response.Message)
+	}
+	return response.Data.Namespaces, nil
+}
+
+// Expire all of the mock's sessions, so every node has to log in again
+func (c *AdminClient) ExpireSessions(ctx context.Context, logger *slog.Logger) error {
+	return c.submitVoidRequest(ctx, logger, "expire sessions", nil, api.AdminExpireSessionsPath)
+}
+
+// Start running a scenario on the mock, replacing the one that's running
+func (c *AdminClient) StartScenario(ctx context.Context, logger *slog.Logger, scenario api.Scenario) error {
+	body, err := json.Marshal(scenario)
+	if err != nil {
+		return fmt.Errorf("error serializing scenario: %w", err)
+	}
+	code, response, err := common.SubmitRequest[struct{}](c.commonClient, ctx, logger, false, http.MethodPost, bytes.NewReader(body), nil, api.AdminStartScenarioPath)
+	if err != nil {
+		return fmt.Errorf("error submitting scenario start request: %w", err)
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("nodeset mock responded to scenario start request with code %d: [%s]", code, response.Message)
+	}
+	return nil
+}
+
+// Stop the scenario that's running on the mock
+func (c *AdminClient) StopScenario(ctx context.Context, logger *slog.Logger) error {
+	return c.submitVoidRequest(ctx, logger, "scenario stop", nil, api.AdminStopScenarioPath)
+}
+
+// Get the progress of the scenario that's running on the mock, or the last one that ran
+func (c *AdminClient) GetScenarioStatus(ctx context.Context, logger *slog.Logger) (api.ScenarioStatusData, error) {
+	code, response, err := common.SubmitRequest[api.ScenarioStatusData](c.commonClient, ctx, logger, false, http.MethodGet, nil, nil, api.AdminScenarioStatusPath)
+	if err != nil {
+		return api.ScenarioStatusData{}, fmt.Errorf("error requesting mock scenario status: %w", err)
+	}
+	if code != http.StatusOK {
+		return api.ScenarioStatusData{}, fmt.Errorf("nodeset mock responded to scenario status request with code %d: [%s]", code, response.Message)
+	}
+	return response.Data, nil
+}
+
+// Set the policy for cycling a StakeWise vault's deposit data sets automatically, replacing the one it already has
+func (c *AdminClient) SetStakeWiseCyclePolicy(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, policy api.StakeWiseCyclePolicy) error {
+	params := map[string]string{
+		"deployment":       deployment,
+		"vault":            vault.Hex(),
+		"interval":         time.Duration(policy.Interval).String(),
+		"user-limit":       strconv.Itoa(policy.UserLimit),
+		"min-pending":      strconv.Itoa(policy.MinPending),
+		"activation-delay": time.Duration(policy.ActivationDelay).String(),
+	}
+	return c.submitVoidRequest(ctx, logger, "set StakeWise cycle policy", params, api.AdminSetStakeWiseCyclePolicyPath)
+}
+
+// Stop cycling a StakeWise vault's deposit data sets automatically
+func (c *AdminClient) ClearStakeWiseCyclePolicy(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"vault":      vault.Hex(),
+	}
+	return c.submitVoidRequest(ctx, logger, "clear StakeWise cycle policy", params, api.AdminClearStakeWiseCyclePolicyPath)
+}
+
+// Get the progress of every vault's deposit data set cycle policy
+func (c *AdminClient) GetStakeWiseCyclePolicies(ctx context.Context, logger *slog.Logger) ([]api.StakeWiseCyclePolicyStatus, error) {
+	code, response, err := common.SubmitRequest[api.StakeWiseCyclePoliciesData](c.commonClient, ctx, logger, false, http.MethodGet, nil, nil, api.AdminStakeWiseCyclePoliciesPath)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting mock StakeWise cycle policies: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("nodeset mock responded to StakeWise cycle policy list request with code %d: [%s]", code, response.Message)
+	}
+	return response.Data.Policies, nil
+}
+
+// Submit a request to an admin route that doesn't return any data
+func (c *AdminClient) submitVoidRequest(ctx context.Context, logger *slog.Logger, name string, params map[string]string, path string) error {
+	code, response, err := common.SubmitRequest[struct{}](c.commonClient, ctx, logger, false, http.MethodGet, nil, params, path)
+	if err != nil {
+		return fmt.Errorf("error submitting %s request: %w", name, err)
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("nodeset mock responded to %s request with code %d: [%s]", name, code, response.Message)
+	}
+	return nil
+}
+
+// Add a StakeWise deployment for the provided chain
+func (c *AdminClient) AddStakeWiseDeployment(ctx context.Context, logger *slog.Logger, deployment string, chainID *big.Int) error {
+	params := map[string]string{
+		"id":    deployment,
+		"chain": chainID.String(),
+	}
+	return c.submitVoidRequest(ctx, logger, "add StakeWise deployment", params, api.AdminAddStakeWiseDeploymentPath)
+}
+
+// Add a StakeWise vault to a deployment
+func (c *AdminClient) AddStakeWiseVault(ctx context.Context, logger *slog.Logger, deployment string, name string, vault ethcommon.Address) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"name":       name,
+		"address":    vault.Hex(),
+	}
+	return c.submitVoidRequest(ctx, logger, "add StakeWise vault", params, api.AdminAddStakeWiseVaultPath)
+}
+
+// Add a Constellation deployment for the provided chain and contracts
+func (c *AdminClient) AddConstellationDeployment(ctx context.Context, logger *slog.Logger, deployment string, chainID *big.Int, whitelist ethcommon.Address, superNode ethcommon.Address) error {
+	params := map[string]string{
+		"id":        deployment,
+		"chain":     chainID.String(),
+		"whitelist": whitelist.Hex(),
+		"supernode": superNode.Hex(),
+	}
+	return c.submitVoidRequest(ctx, logger, "add Constellation deployment", params, api.AdminAddConstellationDeploymentPath)
+}
+
+// Set the private key a Constellation deployment signs whitelist and minipool deposit signatures with
+func (c *AdminClient) SetConstellationPrivateKey(ctx context.Context, logger *slog.Logger, deployment string, privateKey *ecdsa.PrivateKey) error {
+	request := api.AdminSetConstellationPrivateKeyRequest{
+		Deployment: deployment,
+		PrivateKey: hexutil.Encode(crypto.FromECDSA(privateKey)),
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error serializing set Constellation private key request: %w", err)
+	}
+	code, response, err := common.SubmitRequest[struct{}](c.commonClient, ctx, logger, false, http.MethodPost, bytes.NewReader(body), nil, api.AdminSetConstellationPrivateKeyPath)
+	if err != nil {
+		return fmt.Errorf("error submitting set Constellation private key request: %w", err)
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("nodeset mock responded to set Constellation private key request with code %d: [%s]", code, response.Message)
+	}
+	return nil
+}
+
+// Set the identity the mock decrypts uploaded exit messages with. Clients encrypt them for its recipient.
+func (c *AdminClient) SetEncryptionKey(ctx context.Context, logger *slog.Logger, identity *age.X25519Identity) error {
+	params := map[string]string{
+		"key": identity.String(),
+	}
+	return c.submitVoidRequest(ctx, logger, "set encryption key", params, api.AdminSetEncryptionKeyPath)
+}
+
+// Add a new user account
+func (c *AdminClient) AddUser(ctx context.Context, logger *slog.Logger, email string) error {
+	params := map[string]string{
+		"email": email,
+	}
+	return c.submitVoidRequest(ctx, logger, "add user", params, api.AdminAddUserPath)
+}
+
+// Whitelist a node on a user account so it can be registered
+func (c *AdminClient) WhitelistNode(ctx context.Context, logger *slog.Logger, email string, address ethcommon.Address) error {
+	params := map[string]string{
+		"email":   email,
+		"address": address.Hex(),
+	}
+	return c.submitVoidRequest(ctx, logger, "whitelist node", params, api.AdminWhitelistNodePath)
+}
+
+// Remove a user account, along with its nodes and their sessions
+func (c *AdminClient) RemoveUser(ctx context.Context, logger *slog.Logger, email string) error {
+	params := map[string]string{
+		"email": email,
+	}
+	return c.submitVoidRequest(ctx, logger, "remove user", params, api.AdminRemoveUserPath)
+}
+
+// Remove a node from a user account's whitelist
+func (c *AdminClient) RemoveNode(ctx context.Context, logger *slog.Logger, email string, address ethcommon.Address) error {
+	params := map[string]string{
+		"email":   email,
+		"address": address.Hex(),
+	}
+	return c.submitVoidRequest(ctx, logger, "remove node", params, api.AdminRemoveNodePath)
+}
+
+// Deregister a node, leaving it whitelisted on its user account
+func (c *AdminClient) DeregisterNode(ctx context.Context, logger *slog.Logger, address ethcommon.Address) error {
+	params := map[string]string{
+		"address": address.Hex(),
+	}
+	return c.submitVoidRequest(ctx, logger, "deregister node", params, api.AdminDeregisterNodePath)
+}
+
+// Remove a StakeWise deployment and all of its vaults
+func (c *AdminClient) RemoveStakeWiseDeployment(ctx context.Context, logger *slog.Logger, deployment string) error {
+	params := map[string]string{
+		"id": deployment,
+	}
+	return c.submitVoidRequest(ctx, logger, "remove StakeWise deployment", params, api.AdminRemoveStakeWiseDeploymentPath)
+}
+
+// Remove a Constellation deployment
+func (c *AdminClient) RemoveConstellationDeployment(ctx context.Context, logger *slog.Logger, deployment string) error {
+	params := map[string]string{
+		"id": deployment,
+	}
+	return c.submitVoidRequest(ctx, logger, "remove Constellation deployment", params, api.AdminRemoveConstellationDeploymentPath)
+}
+
+// Remove a StakeWise vault and all of its validators
+func (c *AdminClient) RemoveStakeWiseVault(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"address":    vault.Hex(),
+	}
+	return c.submitVoidRequest(ctx, logger, "remove StakeWise vault", params, api.AdminRemoveStakeWiseVaultPath)
+}
+
+// Set the max number of validators each user can have in a StakeWise vault
+func (c *AdminClient) SetMaxValidatorsPerUser(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, max int) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"vault":      vault.Hex(),
+		"max":        strconv.Itoa(max),
+	}
+	return c.submitVoidRequest(ctx, logger, "set max validators per user", params, api.AdminSetMaxValidatorsPerUserPath)
+}
+
+// Remove the exit message uploaded for a validator in a StakeWise vault
+func (c *AdminClient) ClearStakeWiseExitMessage(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, pubkey beacon.ValidatorPubkey) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"vault":      vault.Hex(),
+		"pubkey":     pubkey.HexWithPrefix(),
+	}
+	return c.submitVoidRequest(ctx, logger, "clear StakeWise exit message", params, api.AdminStakeWiseClearExitMessagePath)
+}
+
+// Remove a user's node from a Constellation deployment's whitelist
+func (c *AdminClient) RemoveConstellationWhitelistedNode(ctx context.Context, logger *slog.Logger, deployment string, email string) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"email":      email,
+	}
+	return c.submitVoidRequest(ctx, logger, "remove Constellation whitelisted node", params, api.AdminRemoveConstellationWhitelistedNodePath)
+}
+
+// Remove the exit message uploaded for a Constellation validator
+func (c *AdminClient) ClearConstellationExitMessage(ctx context.Context, logger *slog.Logger, deployment string, pubkey beacon.ValidatorPubkey) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"pubkey":     pubkey.HexWithPrefix(),
+	}
+	return c.submitVoidRequest(ctx, logger, "clear Constellation exit message", params, api.AdminConstellationClearExitMessagePath)
+}
+
+// Grant or revoke a user's permission to use a whole module
+func (c *AdminClient) SetModulePermission(ctx context.Context, logger *slog.Logger, email string, module string, permitted bool) error {
+	return c.SetPermission(ctx, logger, email, api.Permission{Module: module}, permitted)
+}
+
+// Grant or revoke a user's permission to use a deployment of a module
+func (c *AdminClient) SetDeploymentPermission(ctx context.Context, logger *slog.Logger, email string, module string, deployment string, permitted bool) error {
+	return c.SetPermission(ctx, logger, email, api.Permission{Module: module, Deployment: deployment}, permitted)
+}
+
+// Grant or revoke a user's permission to use a StakeWise vault
+func (c *AdminClient) SetVaultPermission(ctx context.Context, logger *slog.Logger, email string, deployment string, vault ethcommon.Address, permitted bool) error {
+	permission := api.Permission{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: deployment,
+		Vault:      &vault,
+	}
+	return c.SetPermission(ctx, logger, email, permission, permitted)
+}
+
+// Grant or revoke a user's permission to use a module, one of its deployments, or a StakeWise vault
+func (c *AdminClient) SetPermission(ctx context.Context, logger *slog.Logger, email string, permission api.Permission, permitted bool) error {
+	params := map[string]string{
+		"email":     email,
+		"module":    permission.Module,
+		"permitted": strconv.FormatBool(permitted),
+	}
+	if permission.Deployment != "" {
+		params["deployment"] = permission.Deployment
+	}
+	if permission.Vault != nil {
+		params["vault"] = permission.Vault.Hex()
+	}
+	return c.submitVoidRequest(ctx, logger, "set permission", params, api.AdminSetPermissionPath)
+}
+
+// Set the ETH balance, in wei, that a StakeWise vault has available for new validator deposits
+func (c *AdminClient) SetStakeWiseVaultBalance(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, balance *big.Int) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"vault":      vault.Hex(),
+		"balance":    balance.String(),
+	}
+	return c.submitVoidRequest(ctx, logger, "set StakeWise vault balance", params, api.AdminSetStakeWiseVaultBalancePath)
+}
+
+// Set the max number of minipools each node can create on a Constellation deployment
+func (c *AdminClient) SetMinipoolLimit(ctx context.Context, logger *slog.Logger, deployment string, limit int) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"limit":      strconv.Itoa(limit),
+	}
+	return c.submitVoidRequest(ctx, logger, "set minipool limit", params, api.AdminSetMinipoolLimitPath)
+}
+
+// Set the max number of minipools a user's nodes can each create on a Constellation deployment, or remove the user's limit if it's nil
+func (c *AdminClient) SetUserMinipoolLimit(ctx context.Context, logger *slog.Logger, deployment string, email string, limit *int) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"email":      email,
+	}
+	if limit != nil {
+		params["limit"] = strconv.Itoa(*limit)
+	}
+	return c.submitVoidRequest(ctx, logger, "set user minipool limit", params, api.AdminSetMinipoolLimitPath)
+}
+
+// Set the max number of minipools a node can create on a Constellation deployment, or remove the node's limit if it's nil
+func (c *AdminClient) SetNodeMinipoolLimit(ctx context.Context, logger *slog.Logger, deployment string, node ethcommon.Address, limit *int) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"node":       node.Hex(),
+	}
+	if limit != nil {
+		params["limit"] = strconv.Itoa(*limit)
+	}
+	return c.submitVoidRequest(ctx, logger, "set node minipool limit", params, api.AdminSetMinipoolLimitPath)
+}
+
+// Set whether Constellation nodes need signed exits for their previous minipools before they can create a new one
+func (c *AdminClient) SetRequireExitMessages(ctx context.Context, logger *slog.Logger, deployment string, required bool) error {
+	params := map[string]string{
+		"deployment": deployment,
+		"required":   strconv.FormatBool(required),
+	}
+	return c.submitVoidRequest(ctx, logger, "set exit message requirement", params, api.AdminSetRequireExitMessagesPath)
+}
+
+// Slash a validator on the simulated Beacon chain, starting its exit
+func (c *AdminClient) SlashBeaconValidator(ctx context.Context, logger *slog.Logger, pubkey beacon.ValidatorPubkey) error {
+	params := map[string]string{
+		"pubkey": pubkey.HexWithPrefix(),
+	}
+	return c.submitVoidRequest(ctx, logger, "slash Beacon validator", params, api.AdminBeaconSlashValidatorPath)
+}
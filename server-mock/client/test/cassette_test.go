@@ -0,0 +1,105 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/cassette"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	nsutil "github.com/nodeset-org/nodeset-client-go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure traffic can be recorded with secrets scrubbed and replayed without the server
+func TestCassetteRecordAndReplay(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	testkit.MarkValidatorsActive(database)
+	mgr.SetDatabase(database)
+	nodeKey := testkit.GetNodeKey(t, 1)
+	nodeAddress := testkit.GetNodeAddress(t, 1)
+	signer := func(message []byte) ([]byte, error) {
+		return nsutil.CreateSignature(message, nodeKey)
+	}
+
+	// Log in and get the node's validators, then make a request that fails
+	runSession := func(client *apiv3.NodeSetClient) (int, error) {
+		nonceData, err := client.Core.Nonce(context.Background(), logger)
+		if err != nil {
+			return 0, err
+		}
+		client.SetSessionToken(nonceData.Token)
+		_, err = client.Core.Login(context.Background(), logger, nonceData.Nonce, nodeAddress, signer)
+		if err != nil {
+			return 0, err
+		}
+		data, err := client.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+		if err != nil {
+			return 0, err
+		}
+		_, err = client.StakeWise.Validators_Get(context.Background(), logger, "bogus", testkit.StakeWiseVaultAddress)
+		return len(data.Validators), err
+	}
+
+	// Record the session
+	recorder := cassette.NewRecorder(nil, cassette.DefaultScrubbedFields)
+	client := apiv3.NewNodeSetClientWithHttpClient(fmt.Sprintf("http://localhost:%d/api", port), &http.Client{Transport: recorder, Timeout: timeout})
+	validatorCount, err := runSession(client)
+	require.ErrorIs(t, err, common.ErrInvalidDeployment)
+	require.Equal(t, 2, validatorCount)
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, recorder.Save(path))
+	t.Log("Recorded the session")
+
+	// Make sure the session token and login signature were scrubbed
+	recording, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, session := range mgr.GetDatabase().Core.GetSessions() {
+		require.False(t, strings.Contains(string(recording), session.Token))
+	}
+	loginRequest := recorder.GetCassette().Interactions[1].Request
+	require.Contains(t, loginRequest.Body, `"signature":"0x000000`)
+
+	// Replay it strictly without the server
+	replayer := cassette.NewTestReplayer(t, path, cassette.MatchMode_Strict)
+	client = apiv3.NewNodeSetClientWithHttpClient("http://offline/api", &http.Client{Transport: replayer})
+	validatorCount, err = runSession(client)
+	require.ErrorIs(t, err, common.ErrInvalidDeployment)
+	require.Equal(t, 2, validatorCount)
+	require.Zero(t, replayer.GetRemainingCount())
+	t.Log("Replayed the session")
+
+	// Requests that weren't recorded aren't served
+	loaded, err := cassette.Load(path)
+	require.NoError(t, err)
+	replayer = cassette.NewReplayer(loaded, cassette.MatchMode_Fuzzy, cassette.DefaultScrubbedFields)
+	client = apiv3.NewNodeSetClientWithHttpClient("http://offline/api", &http.Client{Transport: replayer})
+	client.SetSessionToken("token")
+	_, err = client.Constellation.Validators_Get(context.Background(), logger, testkit.Network)
+	require.ErrorIs(t, err, cassette.ErrUnmatchedRequest)
+	require.Len(t, replayer.GetUnmatchedRequests(), 1)
+
+	// Fuzzy matching allows requests out of order
+	_, err = client.StakeWise.Validators_Get(context.Background(), logger, "bogus", testkit.StakeWiseVaultAddress)
+	require.ErrorIs(t, err, common.ErrInvalidDeployment)
+	data, err := client.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	require.Len(t, data.Validators, 2)
+	t.Log("Fuzzy replay served requests out of order")
+}
@@ -0,0 +1,145 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure a cycle policy cycles sets while there's enough new deposit data, and activates their validators
+func TestStakeWiseCyclePolicy(t *testing.T) {
+	ctx := context.Background()
+	namespace := testkit.CreateNamespace(t, logger, fmt.Sprintf("http://localhost:%d", port))
+	nsManager, exists := s.GetNamespaceManager(namespace.Name)
+	require.True(t, exists)
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	nsManager.SetDatabase(database)
+	adminClient := namespace.NewAdminClient()
+	vault := database.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress)
+
+	require.Equal(t, 5, vault.GetPendingDepositDataCount())
+
+	// Cycle one validator per user at a time, which takes two sets for the provisioned deposit data
+	policy := api.StakeWiseCyclePolicy{
+		Interval:        api.Duration(50 * time.Millisecond),
+		UserLimit:       1,
+		MinPending:      1,
+		ActivationDelay: api.Duration(50 * time.Millisecond),
+	}
+	err := adminClient.SetStakeWiseCyclePolicy(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, policy)
+	require.NoError(t, err)
+
+	// Wait for both sets to be cycled and activated
+	var policies []api.StakeWiseCyclePolicyStatus
+	require.Eventually(t, func() bool {
+		policies, err = adminClient.GetStakeWiseCyclePolicies(ctx, logger)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		return policies[0].Cycles == 2 && policies[0].PendingActivations == 0
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Equal(t, testkit.Network, policies[0].Deployment)
+	require.Equal(t, testkit.StakeWiseVaultAddress, policies[0].Vault)
+	require.Equal(t, policy, policies[0].Policy)
+	require.NotNil(t, policies[0].LastCycle)
+	t.Log("Policy cycled both sets")
+
+	// Nothing should be cycled once the deposit data has all been used
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, adminClient.ClearStakeWiseCyclePolicy(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress))
+	policies, err = adminClient.GetStakeWiseCyclePolicies(ctx, logger)
+	require.NoError(t, err)
+	require.Empty(t, policies)
+	require.Equal(t, 2, vault.LatestDepositDataSetIndex)
+	require.Equal(t, 0, vault.GetPendingDepositDataCount())
+	count := 0
+	for _, validators := range vault.Validators {
+		for _, validator := range validators {
+			require.True(t, validator.DepositDataUsed)
+			require.True(t, validator.MarkedActive)
+			count++
+		}
+	}
+	require.Equal(t, 5, count)
+	t.Log("Validators were used and marked active")
+}
+
+// Make sure a policy waits until there's enough pending deposit data before cycling a set
+func TestStakeWiseCyclePolicyMinPending(t *testing.T) {
+	ctx := context.Background()
+	namespace := testkit.CreateNamespace(t, logger, fmt.Sprintf("http://localhost:%d", port))
+	nsManager, exists := s.GetNamespaceManager(namespace.Name)
+	require.True(t, exists)
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	nsManager.SetDatabase(database)
+	adminClient := namespace.NewAdminClient()
+	vault := database.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress)
+
+	require.Equal(t, 5, vault.GetPendingDepositDataCount())
+
+	// Require one more pending entry than the provisioned deposit data before cycling
+	err := adminClient.SetStakeWiseCyclePolicy(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, api.StakeWiseCyclePolicy{
+		Interval:   api.Duration(20 * time.Millisecond),
+		UserLimit:  2,
+		MinPending: 6,
+	})
+	require.NoError(t, err)
+	time.Sleep(150 * time.Millisecond)
+	policies, err := adminClient.GetStakeWiseCyclePolicies(ctx, logger)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	require.Equal(t, 0, policies[0].Cycles)
+	require.Nil(t, policies[0].LastCycle)
+	t.Log("Policy waited for more deposit data")
+
+	// Upload another validator's deposit data
+	nsClient := apiv2.NewNodeSetClient(namespace.GetApiUrl(), timeout)
+	nsClient.SetSessionToken(database.Core.GetSessions()[0].Token)
+	depositData := []beacon.ExtendedDepositData{
+		testkit.GenerateDepositData(t, 5, testkit.StakeWiseVaultAddress),
+	}
+	err = nsClient.StakeWise.DepositData_Post(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, depositData)
+	require.NoError(t, err)
+
+	// The set should be cycled now
+	require.Eventually(t, func() bool {
+		policies, err = adminClient.GetStakeWiseCyclePolicies(ctx, logger)
+		require.NoError(t, err)
+		return policies[0].Cycles == 1
+	}, 5*time.Second, 20*time.Millisecond)
+	require.NoError(t, adminClient.ClearStakeWiseCyclePolicy(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress))
+	require.Equal(t, 1, vault.LatestDepositDataSetIndex)
+	require.Equal(t, 0, vault.GetPendingDepositDataCount())
+}
+
+// Make sure invalid cycle policies are rejected
+func TestInvalidStakeWiseCyclePolicies(t *testing.T) {
+	ctx := context.Background()
+	namespace := testkit.CreateNamespace(t, logger, fmt.Sprintf("http://localhost:%d", port))
+	nsManager, exists := s.GetNamespaceManager(namespace.Name)
+	require.True(t, exists)
+	nsManager.SetDatabase(testkit.ProvisionFullDatabase(t, logger, false))
+	adminClient := namespace.NewAdminClient()
+	policy := api.StakeWiseCyclePolicy{
+		Interval:  api.Duration(time.Second),
+		UserLimit: 1,
+	}
+
+	err := adminClient.SetStakeWiseCyclePolicy(ctx, logger, "missing", testkit.StakeWiseVaultAddress, policy)
+	require.Error(t, err)
+	err = adminClient.SetStakeWiseCyclePolicy(ctx, logger, testkit.Network, ethcommon.HexToAddress("0x01"), policy)
+	require.Error(t, err)
+	err = adminClient.SetStakeWiseCyclePolicy(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, api.StakeWiseCyclePolicy{UserLimit: 1})
+	require.Error(t, err)
+	err = adminClient.SetStakeWiseCyclePolicy(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, api.StakeWiseCyclePolicy{Interval: api.Duration(time.Second)})
+	require.Error(t, err)
+	err = adminClient.ClearStakeWiseCyclePolicy(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.Error(t, err)
+}
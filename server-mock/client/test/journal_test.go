@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure API requests are recorded in the journal and can be queried and cleared
+func TestRequestJournal(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+	s.ClearRequests()
+
+	// Provision the database
+	db := mgr.GetDatabase()
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+
+	nodeKey, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	nodeAddress := crypto.PubkeyToAddress(nodeKey.PublicKey)
+	user, err := db.Core.AddUser(testkit.User0Email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(nodeAddress)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, nodeAddress, nodeKey, "address")
+	require.NoError(t, err)
+	require.NoError(t, node.Register(regSig, "address"))
+	session := db.Core.CreateSession()
+	loginSig, err := auth.GetSignatureForLogin(session.Nonce, nodeAddress, nodeKey)
+	require.NoError(t, err)
+	require.NoError(t, db.Core.Login(nodeAddress, session.Nonce, loginSig))
+
+	// Make a successful request and a failed one
+	nsClient := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	nsClient.SetSessionToken(session.Token)
+	_, err = nsClient.StakeWise.Vaults(context.Background(), logger, testkit.Network)
+	require.NoError(t, err)
+	_, err = nsClient.StakeWise.Vaults(context.Background(), logger, "bogus")
+	require.ErrorIs(t, err, common.ErrInvalidDeployment)
+
+	// Query the journal through the admin routes
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+	entries, err := adminClient.GetRequests(context.Background(), logger, api.JournalFilter{
+		Route: "vaults",
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, http.MethodGet, entries[0].Method)
+	require.Equal(t, testkit.Network, entries[0].PathArgs["deployment"])
+	require.Equal(t, http.StatusOK, entries[0].StatusCode)
+	require.NotNil(t, entries[0].NodeAddress)
+	require.Equal(t, nodeAddress, *entries[0].NodeAddress)
+	require.Equal(t, http.StatusBadRequest, entries[1].StatusCode)
+	require.Equal(t, common.InvalidDeploymentKey, entries[1].Error)
+	require.Greater(t, entries[1].ID, entries[0].ID)
+	t.Log("Journal recorded both requests")
+
+	// Filter by error key and method
+	entries, err = adminClient.GetRequests(context.Background(), logger, api.JournalFilter{
+		Error: common.InvalidDeploymentKey,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Empty(t, s.GetRequests(api.JournalFilter{Method: http.MethodPost}))
+	t.Log("Journal filters work")
+
+	// Shrink the journal so only the latest request is kept
+	require.Error(t, mgr.GetJournal().SetCapacity(0))
+	require.Error(t, mgr.GetJournal().SetCapacity(-1))
+	require.NoError(t, mgr.GetJournal().SetCapacity(1))
+	defer func() {
+		_ = mgr.GetJournal().SetCapacity(manager.DefaultJournalCapacity)
+	}()
+	require.Len(t, s.GetRequests(api.JournalFilter{}), 1)
+	require.Equal(t, common.InvalidDeploymentKey, s.GetRequests(api.JournalFilter{})[0].Error)
+	t.Log("Journal dropped the oldest request when it was over capacity")
+
+	// Clear the journal
+	err = adminClient.ClearRequests(context.Background(), logger)
+	require.NoError(t, err)
+	require.Empty(t, s.GetRequests(api.JournalFilter{}))
+	t.Log("Journal was cleared")
+}
@@ -0,0 +1,137 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure vaults can be retired and nodes can be deregistered
+func TestVaultRetirementAndDeregistration(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database and log in as node 0
+	db := testkit.ProvisionFullDatabase(t, logger, true)
+	testkit.MarkValidatorsActive(db)
+	mgr.SetDatabase(db)
+	nodeAddress := testkit.GetNodeAddress(t, 0)
+	session := db.Core.CreateSession()
+	require.NoError(t, db.Core.LoginWithoutSignature(nodeAddress, session.Nonce))
+	nsClient := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	nsClient.SetSessionToken(session.Token)
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+
+	// Change the vault's validator limit
+	err := adminClient.SetMaxValidatorsPerUser(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, 5)
+	require.NoError(t, err)
+	vault := db.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress)
+	require.Equal(t, 5, vault.MaxValidatorsPerUser)
+	t.Log("Changed the vault's validator limit")
+
+	// Upload an exit for the node's validator and then clear it
+	pubkey := beacon.ValidatorPubkey(testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress).PublicKey)
+	node, _ := db.Core.GetNode(nodeAddress)
+	require.NoError(t, vault.HandleSignedExitUpload(node, []common.ExitData{testkit.GenerateSignedExit(t, 0)}))
+	validators, err := nsClient.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	require.Len(t, validators.Validators, 1)
+	require.True(t, validators.Validators[0].ExitMessageUploaded)
+	err = adminClient.ClearStakeWiseExitMessage(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, pubkey)
+	require.NoError(t, err)
+	validators, err = nsClient.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	require.False(t, validators.Validators[0].ExitMessageUploaded)
+	t.Log("Cleared the validator's exit message")
+
+	// Retire the vault
+	err = adminClient.RemoveStakeWiseVault(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	_, err = nsClient.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.ErrorIs(t, err, common.ErrInvalidVault)
+	err = adminClient.RemoveStakeWiseVault(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.Error(t, err)
+	t.Log("Retired the vault")
+
+	// Deregister the node, which should end its session
+	err = adminClient.DeregisterNode(context.Background(), logger, nodeAddress)
+	require.NoError(t, err)
+	_, err = nsClient.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.ErrorIs(t, err, common.ErrInvalidSession)
+	require.False(t, node.IsRegistered())
+	require.NotNil(t, db.Core.GetUser(testkit.User1Email).GetNode(nodeAddress))
+	t.Log("Deregistered the node and ended its session")
+}
+
+// Make sure users and their nodes can be removed
+func TestUserAndNodeRemoval(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database and whitelist user 3's first node for Constellation
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	deployment := db.Constellation.GetDeployment(testkit.Network)
+	adminKey, err := testkit.GetEthPrivateKey(9)
+	require.NoError(t, err)
+	deployment.SetAdminPrivateKey(adminKey)
+	node2Address := testkit.GetNodeAddress(t, 2)
+	node3Address := testkit.GetNodeAddress(t, 3)
+	_, err = deployment.GetWhitelistSignature(node2Address)
+	require.NoError(t, err)
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+
+	// Remove the whitelisted node from the user, which drops its Constellation whitelist entry too
+	err = adminClient.RemoveNode(context.Background(), logger, testkit.User3Email, node2Address)
+	require.NoError(t, err)
+	require.Nil(t, db.Core.GetUser(testkit.User3Email).GetNode(node2Address))
+	require.Nil(t, deployment.GetWhitelistedAddressForUser(testkit.User3Email))
+	err = adminClient.RemoveNode(context.Background(), logger, testkit.User3Email, node2Address)
+	require.Error(t, err)
+	t.Log("Removed node 2 from user 3")
+
+	// Whitelist the other node on Constellation and then remove that entry directly
+	_, err = deployment.GetWhitelistSignature(node3Address)
+	require.NoError(t, err)
+	err = adminClient.RemoveConstellationWhitelistedNode(context.Background(), logger, testkit.Network, testkit.User3Email)
+	require.NoError(t, err)
+	require.Nil(t, deployment.GetWhitelistedAddressForUser(testkit.User3Email))
+	t.Log("Removed node 3 from the Constellation whitelist")
+
+	// Remove the user; its remaining node's session should be gone
+	session := db.Core.CreateSession()
+	require.NoError(t, db.Core.LoginWithoutSignature(node3Address, session.Nonce))
+	err = adminClient.RemoveUser(context.Background(), logger, testkit.User3Email)
+	require.NoError(t, err)
+	require.Nil(t, db.Core.GetUser(testkit.User3Email))
+	require.Nil(t, db.Core.GetSessionByToken(session.Token))
+	err = adminClient.RemoveUser(context.Background(), logger, testkit.User3Email)
+	require.Error(t, err)
+	t.Log("Removed user 3")
+
+	// Remove the deployments
+	require.NoError(t, adminClient.RemoveConstellationDeployment(context.Background(), logger, testkit.Network))
+	require.NoError(t, adminClient.RemoveStakeWiseDeployment(context.Background(), logger, testkit.Network))
+	require.Nil(t, db.Constellation.GetDeployment(testkit.Network))
+	require.Nil(t, db.StakeWise.GetDeployment(testkit.Network))
+	t.Log("Removed the deployments")
+}
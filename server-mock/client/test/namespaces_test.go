@@ -0,0 +1,113 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure namespaces on a shared server are isolated from each other and from the root of the server
+func TestNamespaceIsolation(t *testing.T) {
+	serverUrl := fmt.Sprintf("http://localhost:%d", port)
+	first := testkit.CreateNamespace(t, logger, serverUrl)
+	second := testkit.CreateNamespace(t, logger, serverUrl)
+	require.NotEqual(t, first.Name, second.Name)
+
+	// Add a different user to each namespace
+	ctx := context.Background()
+	firstAdmin := first.NewAdminClient()
+	secondAdmin := second.NewAdminClient()
+	require.NoError(t, firstAdmin.AddUser(ctx, logger, "first@test.com"))
+	require.NoError(t, secondAdmin.AddUser(ctx, logger, "second@test.com"))
+
+	// Each namespace should only have its own user, and the root should have neither
+	firstState, err := firstAdmin.GetState(ctx, logger)
+	require.NoError(t, err)
+	require.Len(t, firstState.Users, 1)
+	require.Equal(t, "first@test.com", firstState.Users[0].Email)
+	secondState, err := secondAdmin.GetState(ctx, logger)
+	require.NoError(t, err)
+	require.Len(t, secondState.Users, 1)
+	require.Equal(t, "second@test.com", secondState.Users[0].Email)
+	require.Nil(t, mgr.GetDatabase().Core.GetUser("first@test.com"))
+	require.Nil(t, mgr.GetDatabase().Core.GetUser("second@test.com"))
+	t.Log("Each namespace has its own database")
+
+	// API requests should only be journaled by the namespace that served them
+	_, err = first.NewApiClient("").Core.Nonce(ctx, logger)
+	require.NoError(t, err)
+	firstRequests, err := firstAdmin.GetRequests(ctx, logger, api.JournalFilter{})
+	require.NoError(t, err)
+	require.Len(t, firstRequests, 1)
+	secondRequests, err := secondAdmin.GetRequests(ctx, logger, api.JournalFilter{})
+	require.NoError(t, err)
+	require.Empty(t, secondRequests)
+	t.Log("Each namespace has its own journal")
+
+	// The namespace manager should be the one serving the namespace's routes
+	secondManager, exists := s.GetNamespaceManager(second.Name)
+	require.True(t, exists)
+	require.NotNil(t, secondManager.GetDatabase().Core.GetUser("second@test.com"))
+
+	// The root should list both namespaces
+	rootAdmin := client.NewAdminClient(serverUrl+"/admin", timeout)
+	names, err := rootAdmin.GetNamespaces(ctx, logger)
+	require.NoError(t, err)
+	require.Contains(t, names, first.Name)
+	require.Contains(t, names, second.Name)
+}
+
+// Make sure requests can be routed to a namespace with the namespace header instead of the path prefix
+func TestNamespaceHeader(t *testing.T) {
+	serverUrl := fmt.Sprintf("http://localhost:%d", port)
+	namespace := testkit.CreateNamespace(t, logger, serverUrl)
+	require.NoError(t, namespace.NewAdminClient().AddUser(context.Background(), logger, "header@test.com"))
+
+	// Get the user's state from the root path with the header
+	request, err := http.NewRequest(http.MethodGet, serverUrl+"/admin/"+api.AdminStatePath+"?user=header@test.com", nil)
+	require.NoError(t, err)
+	request.Header.Set(api.NamespaceHeader, namespace.Name)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	// Unknown namespaces should be rejected
+	request.Header.Set(api.NamespaceHeader, "missing")
+	missingResponse, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer missingResponse.Body.Close()
+	require.Equal(t, http.StatusNotFound, missingResponse.StatusCode)
+}
+
+// Make sure namespaces can't be created with bad names, nested, or used after they're deleted
+func TestNamespaceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	serverUrl := fmt.Sprintf("http://localhost:%d", port)
+	rootAdmin := client.NewAdminClient(serverUrl+"/admin", timeout)
+
+	// Invalid and duplicate names should be rejected
+	require.Error(t, rootAdmin.CreateNamespace(ctx, logger, ""))
+	require.Error(t, rootAdmin.CreateNamespace(ctx, logger, "bad/name"))
+	require.NoError(t, rootAdmin.CreateNamespace(ctx, logger, "lifecycle"))
+	require.Error(t, rootAdmin.CreateNamespace(ctx, logger, "lifecycle"))
+
+	// Namespaces can only be created at the root
+	nsAdmin := client.NewAdminClient(serverUrl+"/"+api.NamespacePrefix+"lifecycle/admin", timeout)
+	require.Error(t, nsAdmin.CreateNamespace(ctx, logger, "nested"))
+
+	// Deleting the namespace should make its routes unavailable
+	require.NoError(t, nsAdmin.AddUser(ctx, logger, "lifecycle@test.com"))
+	require.NoError(t, rootAdmin.DeleteNamespace(ctx, logger, "lifecycle"))
+	require.Error(t, nsAdmin.AddUser(ctx, logger, "lifecycle@test.com"))
+	require.Error(t, rootAdmin.DeleteNamespace(ctx, logger, "lifecycle"))
+	names, err := rootAdmin.GetNamespaces(ctx, logger)
+	require.NoError(t, err)
+	require.NotContains(t, names, "lifecycle")
+}
@@ -0,0 +1,147 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure revoked permissions are enforced by the v2 and v3 routes
+func TestPermissions(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database and log in as node 0
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	nodeAddress := testkit.GetNodeAddress(t, 0)
+	session := db.Core.CreateSession()
+	require.NoError(t, db.Core.LoginWithoutSignature(nodeAddress, session.Nonce))
+	v2Client := apiv2.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	v2Client.SetSessionToken(session.Token)
+	v3Client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	v3Client.SetSessionToken(session.Token)
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+
+	// Revoke the vault
+	err := adminClient.SetVaultPermission(context.Background(), logger, testkit.User1Email, testkit.Network, testkit.StakeWiseVaultAddress, false)
+	require.NoError(t, err)
+	_, err = v2Client.StakeWise.DepositDataMeta(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	_, err = v3Client.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	_, err = v3Client.Constellation.Whitelist_Get(context.Background(), logger, testkit.Network)
+	require.NoError(t, err)
+	state, err := adminClient.GetUserState(context.Background(), logger, testkit.User1Email)
+	require.NoError(t, err)
+	require.Len(t, state.Users, 1)
+	require.Equal(t, []api.Permission{{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: testkit.Network,
+		Vault:      &testkit.StakeWiseVaultAddress,
+	}}, state.Users[0].RevokedPermissions)
+	t.Log("Vault access was revoked")
+
+	// Restore it
+	err = adminClient.SetVaultPermission(context.Background(), logger, testkit.User1Email, testkit.Network, testkit.StakeWiseVaultAddress, true)
+	require.NoError(t, err)
+	_, err = v3Client.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	t.Log("Vault access was restored")
+
+	// Revoke the Constellation deployment and then the whole module
+	err = adminClient.SetDeploymentPermission(context.Background(), logger, testkit.User1Email, api.PermissionModule_Constellation, testkit.Network, false)
+	require.NoError(t, err)
+	_, err = v2Client.Constellation.Whitelist_Get(context.Background(), logger, testkit.Network)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	err = adminClient.SetDeploymentPermission(context.Background(), logger, testkit.User1Email, api.PermissionModule_Constellation, testkit.Network, true)
+	require.NoError(t, err)
+	err = adminClient.SetModulePermission(context.Background(), logger, testkit.User1Email, api.PermissionModule_Constellation, false)
+	require.NoError(t, err)
+	_, err = v3Client.Constellation.Whitelist_Get(context.Background(), logger, testkit.Network)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	t.Log("Constellation access was revoked")
+
+	// Make sure bad requests are rejected
+	err = adminClient.SetModulePermission(context.Background(), logger, testkit.User1Email, "unknown", false)
+	require.Error(t, err)
+	err = adminClient.SetModulePermission(context.Background(), logger, "nobody@nodeset.io", api.PermissionModule_StakeWise, false)
+	require.Error(t, err)
+}
+
+// Make sure the deployment and vault lists respect revoked permissions
+func TestListingPermissions(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database and log in as node 0
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	nodeAddress := testkit.GetNodeAddress(t, 0)
+	session := db.Core.CreateSession()
+	require.NoError(t, db.Core.LoginWithoutSignature(nodeAddress, session.Nonce))
+	v2Client := apiv2.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	v2Client.SetSessionToken(session.Token)
+	v3Client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	v3Client.SetSessionToken(session.Token)
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+
+	// Revoke the vault
+	err := adminClient.SetVaultPermission(context.Background(), logger, testkit.User1Email, testkit.Network, testkit.StakeWiseVaultAddress, false)
+	require.NoError(t, err)
+	vaults, err := v2Client.StakeWise.Vaults(context.Background(), logger, testkit.Network)
+	require.NoError(t, err)
+	require.Empty(t, vaults.Vaults)
+	t.Log("Revoked vault was left out")
+
+	// Revoke the deployments
+	err = adminClient.SetDeploymentPermission(context.Background(), logger, testkit.User1Email, api.PermissionModule_StakeWise, testkit.Network, false)
+	require.NoError(t, err)
+	err = adminClient.SetDeploymentPermission(context.Background(), logger, testkit.User1Email, api.PermissionModule_Constellation, testkit.Network, false)
+	require.NoError(t, err)
+	_, err = v2Client.StakeWise.Vaults(context.Background(), logger, testkit.Network)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	swDeployments, err := v2Client.StakeWise.Deployments(context.Background(), logger)
+	require.NoError(t, err)
+	require.Empty(t, swDeployments.Deployments)
+	v2Deployments, err := v2Client.Constellation.Deployments(context.Background(), logger)
+	require.NoError(t, err)
+	require.Empty(t, v2Deployments.Deployments)
+	v3Deployments, err := v3Client.Constellation.Deployments(context.Background(), logger)
+	require.NoError(t, err)
+	require.Empty(t, v3Deployments.Deployments)
+	t.Log("Revoked deployments were left out")
+
+	// Revoke the modules
+	err = adminClient.SetModulePermission(context.Background(), logger, testkit.User1Email, api.PermissionModule_StakeWise, false)
+	require.NoError(t, err)
+	err = adminClient.SetModulePermission(context.Background(), logger, testkit.User1Email, api.PermissionModule_Constellation, false)
+	require.NoError(t, err)
+	_, err = v2Client.StakeWise.Deployments(context.Background(), logger)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	_, err = v2Client.Constellation.Deployments(context.Background(), logger)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	_, err = v3Client.Constellation.Deployments(context.Background(), logger)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	t.Log("Revoked modules were rejected")
+}
@@ -0,0 +1,256 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure scenarios run their timed and repeating steps, and report their progress
+func TestScenarioTimedSteps(t *testing.T) {
+	ctx := context.Background()
+	namespace := testkit.CreateNamespace(t, logger, fmt.Sprintf("http://localhost:%d", port))
+	nsManager, exists := s.GetNamespaceManager(namespace.Name)
+	require.True(t, exists)
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	nsManager.SetDatabase(database)
+	adminClient := namespace.NewAdminClient()
+
+	// Add a user once and expire the sessions three times
+	err := adminClient.StartScenario(ctx, logger, api.Scenario{
+		Name: "timed",
+		Steps: []api.ScenarioStep{
+			{
+				Name:  "add user",
+				After: api.Duration(50 * time.Millisecond),
+				Actions: []api.ScenarioAction{
+					{Route: api.AdminAddUserPath, Params: map[string]string{"email": "timed@test.com"}},
+				},
+			},
+			{
+				Name:  "expire sessions",
+				Every: api.Duration(50 * time.Millisecond),
+				Times: 3,
+				Actions: []api.ScenarioAction{
+					{Route: api.AdminExpireSessionsPath},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Wait for it to finish
+	var status api.ScenarioStatusData
+	require.Eventually(t, func() bool {
+		status, err = adminClient.GetScenarioStatus(ctx, logger)
+		require.NoError(t, err)
+		return !status.Running
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Equal(t, "timed", status.Name)
+	require.Equal(t, []api.ScenarioStepStatus{
+		{Name: "add user", Runs: 1, Done: true},
+		{Name: "expire sessions", Runs: 3, Done: true},
+	}, status.Steps)
+	require.Len(t, status.Executions, 4)
+	for _, execution := range status.Executions {
+		require.Equal(t, http.StatusOK, execution.StatusCode, execution.Message)
+	}
+	t.Log("Scenario ran every step and finished")
+
+	// The actions should have changed the namespace's database
+	require.NotNil(t, database.Core.GetUser("timed@test.com"))
+	require.Empty(t, database.Core.GetSessions())
+}
+
+// Make sure steps triggered by API requests run with the request's details
+func TestScenarioTriggeredSteps(t *testing.T) {
+	ctx := context.Background()
+	namespace := testkit.CreateNamespace(t, logger, fmt.Sprintf("http://localhost:%d", port))
+	nsManager, exists := s.GetNamespaceManager(namespace.Name)
+	require.True(t, exists)
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	nsManager.SetDatabase(database)
+	adminClient := namespace.NewAdminClient()
+	vault := database.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress)
+	require.Equal(t, 0, vault.LatestDepositDataSetIndex)
+
+	// Cycle the set for the vault a node uploads deposit data to
+	err := adminClient.StartScenario(ctx, logger, api.Scenario{
+		Name: "triggered",
+		Steps: []api.ScenarioStep{
+			{
+				Name: "cycle after upload",
+				On: &api.JournalFilter{
+					Method:     http.MethodPost,
+					Route:      "deposit-data",
+					StatusCode: http.StatusOK,
+				},
+				After: api.Duration(50 * time.Millisecond),
+				Actions: []api.ScenarioAction{
+					{
+						Route: api.AdminCycleSetPath,
+						Params: map[string]string{
+							"deployment": `{{index .PathArgs "deployment"}}`,
+							"vault":      `{{index .PathArgs "vault"}}`,
+							"user-limit": "1",
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Nothing should happen before the upload
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 0, vault.LatestDepositDataSetIndex)
+
+	// Upload deposit data
+	nsClient := apiv2.NewNodeSetClient(namespace.GetApiUrl(), timeout)
+	nsClient.SetSessionToken(database.Core.GetSessions()[0].Token)
+	depositData := []beacon.ExtendedDepositData{
+		testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress),
+	}
+	err = nsClient.StakeWise.DepositData_Post(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, depositData)
+	require.NoError(t, err)
+
+	// The set should be cycled for the vault from the request
+	require.Eventually(t, func() bool {
+		status, err := adminClient.GetScenarioStatus(ctx, logger)
+		require.NoError(t, err)
+		return status.Steps[0].Runs == 1
+	}, 5*time.Second, 20*time.Millisecond)
+	status, err := adminClient.GetScenarioStatus(ctx, logger)
+	require.NoError(t, err)
+	require.True(t, status.Running)
+	require.Len(t, status.Executions, 1)
+	require.Equal(t, http.StatusOK, status.Executions[0].StatusCode, status.Executions[0].Message)
+	require.Equal(t, testkit.StakeWiseVaultAddress.Hex(), status.Executions[0].Params["vault"])
+	require.Equal(t, 1, vault.LatestDepositDataSetIndex)
+	t.Log("Upload triggered a set cycle")
+
+	// Stopping it should keep the progress
+	require.NoError(t, adminClient.StopScenario(ctx, logger))
+	status, err = adminClient.GetScenarioStatus(ctx, logger)
+	require.NoError(t, err)
+	require.False(t, status.Running)
+	require.Equal(t, 1, status.Steps[0].Runs)
+}
+
+// Make sure scenario actions and API requests are serialized when they change the database at the same time
+func TestScenarioConcurrentRequests(t *testing.T) {
+	ctx := context.Background()
+	namespace := testkit.CreateNamespace(t, logger, fmt.Sprintf("http://localhost:%d", port))
+	nsManager, exists := s.GetNamespaceManager(namespace.Name)
+	require.True(t, exists)
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	nsManager.SetDatabase(database)
+	adminClient := namespace.NewAdminClient()
+
+	// Actions should wait for the manager's lock, like request handlers do
+	nsManager.Lock()
+	err := adminClient.StartScenario(ctx, logger, api.Scenario{
+		Name: "locked",
+		Steps: []api.ScenarioStep{
+			{
+				Name: "add user",
+				Actions: []api.ScenarioAction{
+					{Route: api.AdminAddUserPath, Params: map[string]string{"email": "locked@test.com"}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		nsManager.Unlock()
+		require.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	user := database.Core.GetUser("locked@test.com")
+	nsManager.Unlock()
+	require.Nil(t, user)
+	require.Eventually(t, func() bool {
+		status, err := adminClient.GetScenarioStatus(ctx, logger)
+		require.NoError(t, err)
+		return !status.Running
+	}, 5*time.Second, 20*time.Millisecond)
+	require.NotNil(t, database.Core.GetUser("locked@test.com"))
+	t.Log("Scenario action waited for the lock")
+
+	// Cycle sets repeatedly in the background
+	err = adminClient.StartScenario(ctx, logger, api.Scenario{
+		Name: "concurrent",
+		Steps: []api.ScenarioStep{
+			{
+				Name:  "cycle",
+				Every: api.Duration(5 * time.Millisecond),
+				Times: 20,
+				Actions: []api.ScenarioAction{
+					{
+						Route: api.AdminCycleSetPath,
+						Params: map[string]string{
+							"deployment": testkit.Network,
+							"vault":      testkit.StakeWiseVaultAddress.Hex(),
+							"user-limit": "1",
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Upload and read deposit data until it finishes
+	nsClient := apiv2.NewNodeSetClient(namespace.GetApiUrl(), timeout)
+	nsClient.SetSessionToken(database.Core.GetSessions()[0].Token)
+	for i := uint(5); ; i++ {
+		status, err := adminClient.GetScenarioStatus(ctx, logger)
+		require.NoError(t, err)
+		if !status.Running {
+			break
+		}
+		depositData := []beacon.ExtendedDepositData{
+			testkit.GenerateDepositData(t, i, testkit.StakeWiseVaultAddress),
+		}
+		err = nsClient.StakeWise.DepositData_Post(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, depositData)
+		require.NoError(t, err)
+		_, err = nsClient.StakeWise.DepositDataMeta(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+		require.NoError(t, err)
+	}
+	status, err := adminClient.GetScenarioStatus(ctx, logger)
+	require.NoError(t, err)
+	require.Equal(t, 20, status.Steps[0].Runs)
+	for _, execution := range status.Executions {
+		require.Equal(t, http.StatusOK, execution.StatusCode, execution.Message)
+	}
+}
+
+// Make sure invalid scenarios are rejected
+func TestInvalidScenarios(t *testing.T) {
+	ctx := context.Background()
+	namespace := testkit.CreateNamespace(t, logger, fmt.Sprintf("http://localhost:%d", port))
+	adminClient := namespace.NewAdminClient()
+	action := api.ScenarioAction{Route: api.AdminExpireSessionsPath}
+
+	scenarios := map[string]api.Scenario{
+		"no steps":       {Name: "empty"},
+		"no actions":     {Steps: []api.ScenarioStep{{Name: "empty"}}},
+		"unknown route":  {Steps: []api.ScenarioStep{{Actions: []api.ScenarioAction{{Route: "missing"}}}}},
+		"scenario route": {Steps: []api.ScenarioStep{{Actions: []api.ScenarioAction{{Route: api.AdminStopScenarioPath}}}}},
+		"on and every":   {Steps: []api.ScenarioStep{{On: &api.JournalFilter{}, Every: api.Duration(time.Second), Actions: []api.ScenarioAction{action}}}},
+		"invalid params": {Steps: []api.ScenarioStep{{Actions: []api.ScenarioAction{{Route: api.AdminAddUserPath, Params: map[string]string{"email": "{{"}}}}}},
+		"negative times": {Steps: []api.ScenarioStep{{Every: api.Duration(time.Second), Times: -1, Actions: []api.ScenarioAction{action}}}},
+		"negative after": {Steps: []api.ScenarioStep{{After: api.Duration(-time.Second), Actions: []api.ScenarioAction{action}}}},
+	}
+	for name, scenario := range scenarios {
+		err := adminClient.StartScenario(ctx, logger, scenario)
+		require.Error(t, err, name)
+	}
+}
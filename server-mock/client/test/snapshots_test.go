@@ -0,0 +1,96 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure snapshots can be listed, diffed, exported, imported, and deleted
+func TestSnapshotManagement(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+		_ = mgr.DeleteSnapshot("test")
+	}()
+
+	// Provision the database and take a baseline
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	mgr.TakeSnapshot("baseline")
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+
+	// List the snapshots
+	snapshots, err := adminClient.GetSnapshots(context.Background(), logger)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	require.Equal(t, "test", snapshots[0].Name)
+	require.Equal(t, "baseline", snapshots[1].Name)
+	require.False(t, snapshots[1].Time.Before(snapshots[0].Time))
+	t.Log("Listed snapshots")
+
+	// Change the live database
+	_, err = db.Core.AddUser("new@test.com")
+	require.NoError(t, err)
+	user := db.Core.GetUser(testkit.User3Email)
+	var nodeAddress string
+	for address := range user.GetNodes() {
+		nodeAddress = address.Hex()
+		db.Constellation.GetDeployment(testkit.Network).IncrementWhitelistNonce(address)
+		break
+	}
+
+	// Diff the baseline against the live database
+	diff, err := adminClient.DiffSnapshots(context.Background(), logger, "baseline", "")
+	require.NoError(t, err)
+	require.Equal(t, []api.StateChange{
+		{
+			Path: "constellation/" + testkit.Network + "/nodes/" + nodeAddress + "/whitelistNonce",
+			Kind: api.StateChangeKind_Changed,
+			Old:  "0",
+			New:  "1",
+		},
+		{
+			Path: "users/new@test.com",
+			Kind: api.StateChangeKind_Added,
+		},
+	}, diff.Changes)
+	t.Log("Diff showed the new user and the nonce change")
+
+	// Export the baseline, delete it, and import it again
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	err = adminClient.ExportSnapshot(context.Background(), logger, "baseline", path)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	err = adminClient.DeleteSnapshot(context.Background(), logger, "baseline")
+	require.NoError(t, err)
+	_, err = adminClient.DiffSnapshots(context.Background(), logger, "baseline", "")
+	require.Error(t, err)
+	err = adminClient.ImportSnapshot(context.Background(), logger, "imported", path)
+	require.NoError(t, err)
+	t.Log("Exported, deleted, and imported the baseline")
+
+	// The imported snapshot should match the original baseline
+	diff, err = adminClient.DiffSnapshots(context.Background(), logger, "imported", "")
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 2)
+	require.NoError(t, mgr.RevertToSnapshot("imported"))
+	diff, err = adminClient.DiffSnapshots(context.Background(), logger, "imported", "")
+	require.NoError(t, err)
+	require.Empty(t, diff.Changes)
+	require.NoError(t, mgr.DeleteSnapshot("imported"))
+	t.Log("Imported snapshot matches the baseline")
+}
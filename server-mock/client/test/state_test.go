@@ -0,0 +1,129 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure the full state of the database can be retrieved
+func TestGetState(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	db := testkit.ProvisionFullDatabase(t, logger, true)
+	mgr.SetDatabase(db)
+
+	// Get the state
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+	state, err := adminClient.GetState(context.Background(), logger)
+	require.NoError(t, err)
+
+	// Check the users and nodes
+	require.Len(t, state.Users, 4)
+	require.Equal(t, testkit.User0Email, state.Users[0].Email)
+	require.Empty(t, state.Users[0].Nodes)
+	require.Len(t, state.Users[3].Nodes, 2)
+	for _, node := range state.Users[3].Nodes {
+		require.True(t, node.Registered)
+	}
+
+	// Check the StakeWise vault
+	require.Len(t, state.StakeWise, 1)
+	require.Equal(t, testkit.Network, state.StakeWise[0].ID)
+	require.Len(t, state.StakeWise[0].Vaults, 1)
+	vault := state.StakeWise[0].Vaults[0]
+	require.Equal(t, testkit.StakeWiseVaultAddress, vault.Address)
+	require.Equal(t, 1, vault.LatestDepositDataSetIndex)
+	require.Len(t, vault.Validators, 5)
+	uploadedCount := 0
+	for _, validator := range vault.Validators {
+		if validator.UploadedToStakeWise {
+			uploadedCount++
+		}
+	}
+	require.Equal(t, 3, uploadedCount)
+
+	// Check the Constellation deployment and the chains
+	require.Len(t, state.Constellation, 1)
+	require.Equal(t, testkit.WhitelistAddress, state.Constellation[0].WhitelistAddress)
+	require.Len(t, state.Constellation[0].Nodes, 4)
+	require.Equal(t, db.Eth.GetDepositRoot(), state.Eth.DepositRoot)
+	require.Equal(t, uint64(0), state.Eth.DepositCount)
+	require.Empty(t, state.Beacon.Validators)
+	t.Log("Received the full database state")
+}
+
+// Make sure the state can be filtered by user, node, vault, and pubkey
+func TestQueryState(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	adminClient := client.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+
+	// Filter by user
+	state, err := adminClient.GetUserState(context.Background(), logger, testkit.User3Email)
+	require.NoError(t, err)
+	require.Len(t, state.Users, 1)
+	require.Len(t, state.Users[0].Nodes, 2)
+	require.Len(t, state.StakeWise[0].Vaults[0].Validators, 2)
+	require.Len(t, state.Constellation[0].Nodes, 2)
+	t.Log("Filtered the state by user")
+
+	// Filter by node
+	node1 := state.Users[0].Nodes[1].Address
+	state, err = adminClient.GetNodeState(context.Background(), logger, node1)
+	require.NoError(t, err)
+	require.Len(t, state.Users, 1)
+	require.Len(t, state.Users[0].Nodes, 1)
+	require.Equal(t, node1, state.Users[0].Nodes[0].Address)
+	require.Len(t, state.StakeWise[0].Vaults[0].Validators, 1)
+	require.Equal(t, node1, state.StakeWise[0].Vaults[0].Validators[0].NodeAddress)
+	t.Log("Filtered the state by node")
+
+	// Filter by pubkey
+	pubkey := beacon.ValidatorPubkey(testkit.GenerateDepositData(t, 1, testkit.StakeWiseVaultAddress).PublicKey)
+	state, err = adminClient.GetValidatorState(context.Background(), logger, pubkey)
+	require.NoError(t, err)
+	require.Len(t, state.StakeWise[0].Vaults[0].Validators, 1)
+	require.Equal(t, pubkey, state.StakeWise[0].Vaults[0].Validators[0].Pubkey)
+	t.Log("Filtered the state by pubkey")
+
+	// Filter by a vault that doesn't exist
+	state, err = adminClient.GetVaultState(context.Background(), logger, ethcommon.HexToAddress("0x01"))
+	require.NoError(t, err)
+	require.Empty(t, state.StakeWise[0].Vaults)
+	t.Log("Filtering by an unknown vault returned no vaults")
+
+	// Filter by several fields at once
+	state, err = adminClient.QueryState(context.Background(), logger, api.StateFilter{
+		User:   testkit.User1Email,
+		Pubkey: &pubkey,
+	})
+	require.NoError(t, err)
+	require.Empty(t, state.StakeWise[0].Vaults[0].Validators)
+	t.Log("Combined filters matched nothing as expected")
+}
@@ -0,0 +1,168 @@
+// Package conformance is an API conformance suite for NodeSet servers. It walks the happy path and the documented error keys
+// of every v2 and v3 route with the client bindings and reports each place where the server's behavior diverges from what
+// the bindings expect. It can run against any server URL: the mock in CI, or a staging server by hand.
+package conformance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+)
+
+// The result of a single check
+type Status string
+
+const (
+	// The server behaved the way the client bindings expect
+	Status_Pass Status = "PASS"
+
+	// The server diverged from what the client bindings expect
+	Status_Fail Status = "FAIL"
+
+	// The check couldn't run against this server, usually because it needs admin capabilities
+	Status_Skip Status = "SKIP"
+)
+
+// Administrative capabilities the suite uses to set up its own server state. The mock's admin client implements it.
+// Servers without an admin API, like staging, don't have them; checks that need them or that change server state are skipped.
+type Admin interface {
+	// Add a StakeWise deployment for the provided chain
+	AddStakeWiseDeployment(ctx context.Context, logger *slog.Logger, deployment string, chainID *big.Int) error
+
+	// Add a StakeWise vault to a deployment
+	AddStakeWiseVault(ctx context.Context, logger *slog.Logger, deployment string, name string, vault ethcommon.Address) error
+
+	// Set the ETH balance, in wei, that a StakeWise vault has available for new validator deposits
+	SetStakeWiseVaultBalance(ctx context.Context, logger *slog.Logger, deployment string, vault ethcommon.Address, balance *big.Int) error
+
+	// Add a Constellation deployment for the provided chain and contracts
+	AddConstellationDeployment(ctx context.Context, logger *slog.Logger, deployment string, chainID *big.Int, whitelist ethcommon.Address, superNode ethcommon.Address) error
+
+	// Set the private key a Constellation deployment signs whitelist and minipool deposit signatures with
+	SetConstellationPrivateKey(ctx context.Context, logger *slog.Logger, deployment string, privateKey *ecdsa.PrivateKey) error
+
+	// Set the identity the server decrypts uploaded exit messages with
+	SetEncryptionKey(ctx context.Context, logger *slog.Logger, identity *age.X25519Identity) error
+
+	// Add a new user account
+	AddUser(ctx context.Context, logger *slog.Logger, email string) error
+
+	// Whitelist a node on a user account so it can be registered
+	WhitelistNode(ctx context.Context, logger *slog.Logger, email string, address ethcommon.Address) error
+}
+
+// Settings for a conformance run
+type Config struct {
+	// The base URL of the server's API routes, for example [http://localhost:50512/api]
+	ApiUrl string
+
+	// The timeout for each request
+	Timeout time.Duration
+
+	// The server's admin capabilities, or nil if it doesn't have any. When they're provided, the suite creates its own user,
+	// nodes, and deployments and the remaining fields are ignored.
+	Admin Admin
+
+	// The email of the user account that owns NodeKey
+	Email string
+
+	// The key of a node that's already registered on the user account
+	NodeKey *ecdsa.PrivateKey
+
+	// The StakeWise deployment the user can use
+	StakeWiseDeployment string
+
+	// The StakeWise vault on the deployment the user can use
+	StakeWiseVault ethcommon.Address
+
+	// The Constellation deployment the user can use
+	ConstellationDeployment string
+
+	// Provides the Beacon deposit contract's current deposit root for StakeWise validator registration, or nil to skip it
+	DepositRootProvider v3stakewise.DepositRootProvider
+}
+
+// The outcome of a single check
+type Result struct {
+	// The route and behavior being checked, for example [v3/stakewise/validators POST: invalid_deposit_root]
+	Name string
+
+	// Whether the server passed the check
+	Status Status
+
+	// Why the check failed or was skipped
+	Message string
+}
+
+// The outcome of a conformance run
+type Report struct {
+	Results []Result
+}
+
+// Get the checks the server failed
+func (r *Report) GetFailures() []Result {
+	failures := []Result{}
+	for _, result := range r.Results {
+		if result.Status == Status_Fail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Get the number of checks with the provided status
+func (r *Report) GetCount(status Status) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Check if the server passed every check that ran
+func (r *Report) Passed() bool {
+	return r.GetCount(Status_Fail) == 0
+}
+
+// Write a human-readable summary of the report
+func (r *Report) Write(writer io.Writer) error {
+	for _, result := range r.Results {
+		line := fmt.Sprintf("%s  %s", result.Status, result.Name)
+		if result.Message != "" {
+			line += ": " + result.Message
+		}
+		_, err := fmt.Fprintln(writer, line)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(writer, "\n%d passed, %d failed, %d skipped\n", r.GetCount(Status_Pass), r.GetCount(Status_Fail), r.GetCount(Status_Skip))
+	return err
+}
+
+// Run the suite against a server. An error is only returned if the suite can't get started, such as when provisioning its
+// state with the admin capabilities fails; divergences from the client bindings are reported as failed checks.
+func Run(ctx context.Context, logger *slog.Logger, config Config) (*Report, error) {
+	s, err := newSuite(ctx, logger, config)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range []string{version_V2, version_V3} {
+		s.runCoreChecks(version)
+	}
+	s.runV2StakeWiseChecks()
+	s.runV3StakeWiseChecks()
+	s.runV2ConstellationChecks()
+	s.runV3ConstellationChecks()
+	return s.report, nil
+}
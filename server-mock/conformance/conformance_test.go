@@ -0,0 +1,71 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/conformance"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// The mock's admin client provides everything the suite needs to set up its own state
+var _ conformance.Admin = (*client.AdminClient)(nil)
+
+// Make sure the mock conforms to the client bindings when the suite can set up its own state
+func TestMockConformance(t *testing.T) {
+	logger := slog.Default()
+	server := testkit.StartServer(t, logger)
+	ctx := context.Background()
+
+	depositRootProvider, err := v3stakewise.NewRpcDepositRootProvider(ctx, fmt.Sprintf("http://localhost:%d/eth", server.GetPort()), testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	report, err := conformance.Run(ctx, logger, conformance.Config{
+		ApiUrl:              server.GetApiUrl(),
+		Timeout:             testkit.DefaultTimeout,
+		Admin:               server.NewAdminClient(),
+		DepositRootProvider: depositRootProvider,
+	})
+	require.NoError(t, err)
+	logReport(t, report)
+
+	require.True(t, report.Passed())
+	require.Zero(t, report.GetCount(conformance.Status_Skip))
+}
+
+// Make sure the suite only runs the read-only checks against a server it can't set up
+func TestMockConformanceWithoutAdmin(t *testing.T) {
+	logger := slog.Default()
+	server := testkit.StartServer(t, logger)
+	server.Provision(t, true)
+
+	// Node 0 is registered to user 1
+	report, err := conformance.Run(context.Background(), logger, conformance.Config{
+		ApiUrl:                  server.GetApiUrl(),
+		Timeout:                 testkit.DefaultTimeout,
+		Email:                   testkit.User1Email,
+		NodeKey:                 testkit.GetNodeKey(t, 0),
+		StakeWiseDeployment:     testkit.Network,
+		StakeWiseVault:          testkit.StakeWiseVaultAddress,
+		ConstellationDeployment: testkit.Network,
+	})
+	require.NoError(t, err)
+	logReport(t, report)
+
+	require.True(t, report.Passed())
+	require.NotZero(t, report.GetCount(conformance.Status_Pass))
+	require.NotZero(t, report.GetCount(conformance.Status_Skip))
+}
+
+// Log a report's results
+func logReport(t *testing.T, report *conformance.Report) {
+	buffer := &bytes.Buffer{}
+	err := report.Write(buffer)
+	require.NoError(t, err)
+	t.Log("\n" + buffer.String())
+}
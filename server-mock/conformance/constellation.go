@@ -0,0 +1,198 @@
+package conformance
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/nodeset-org/nodeset-client-go/common"
+)
+
+// Check the v2 Constellation routes
+func (s *suite) runV2ConstellationChecks() {
+	prefix := version_V2 + "/constellation/"
+	client := s.v2Client.Constellation
+
+	s.checkWithSession(version_V2, prefix+"deployments GET", false, func() error {
+		data, err := client.Deployments(s.ctx, s.logger)
+		if err != nil {
+			return err
+		}
+		return checkDeployments(data, s.constellationDeployment)
+	})
+
+	// Whitelist
+	s.checkWithSession(version_V2, prefix+"whitelist POST", true, func() error {
+		data, err := client.Whitelist_Post(s.ctx, s.logger, s.constellationDeployment)
+		if err != nil {
+			return err
+		}
+		if data.Signature == "" {
+			return fmt.Errorf("response is missing the whitelist signature")
+		}
+		return nil
+	})
+	s.checkWithSession(version_V2, prefix+"whitelist GET", false, func() error {
+		data, err := client.Whitelist_Get(s.ctx, s.logger, s.constellationDeployment)
+		if err != nil {
+			return err
+		}
+		return s.checkWhitelist(data.Whitelisted, data.Address.Hex())
+	})
+	s.checkWithSession(version_V2, prefix+"whitelist GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Whitelist_Get(s.ctx, s.logger, invalidDeployment)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+
+	// Minipool deposit signatures
+	s.checkWithSession(version_V2, prefix+"minipool/deposit-signature POST", true, func() error {
+		minipoolAddress, err := newAddress()
+		if err != nil {
+			return err
+		}
+		salt, err := newSalt()
+		if err != nil {
+			return err
+		}
+		data, err := client.MinipoolDepositSignature(s.ctx, s.logger, s.constellationDeployment, minipoolAddress, salt)
+		if err != nil {
+			return err
+		}
+		if data.Signature == "" {
+			return fmt.Errorf("response is missing the minipool deposit signature")
+		}
+		return nil
+	})
+	s.checkWithSession(version_V2, prefix+"minipool/deposit-signature POST: "+common.InvalidDeploymentKey, false, func() error {
+		salt, err := newSalt()
+		if err != nil {
+			return err
+		}
+		_, err = client.MinipoolDepositSignature(s.ctx, s.logger, invalidDeployment, invalidVault, salt)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+
+	// Validators
+	s.checkWithSession(version_V2, prefix+"validators GET", false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, s.constellationDeployment)
+		return err
+	})
+	s.checkWithSession(version_V2, prefix+"validators GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, invalidDeployment)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+	s.checkWithSession(version_V2, prefix+"validators PATCH", false, func() error {
+		return client.Validators_Patch(s.ctx, s.logger, s.constellationDeployment, []common.EncryptedExitData{})
+	})
+	s.checkWithSession(version_V2, prefix+"validators PATCH: "+common.InvalidDeploymentKey, false, func() error {
+		err := client.Validators_Patch(s.ctx, s.logger, invalidDeployment, []common.EncryptedExitData{})
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+}
+
+// Check the v3 Constellation routes
+func (s *suite) runV3ConstellationChecks() {
+	prefix := version_V3 + "/constellation/"
+	client := s.v3Client.Constellation
+
+	s.checkWithSession(version_V3, prefix+"deployments GET", false, func() error {
+		data, err := client.Deployments(s.ctx, s.logger)
+		if err != nil {
+			return err
+		}
+		return checkDeployments(data, s.constellationDeployment)
+	})
+
+	// Whitelist
+	s.checkWithSession(version_V3, prefix+"whitelist POST", true, func() error {
+		data, err := client.Whitelist_Post(s.ctx, s.logger, s.constellationDeployment)
+		if err != nil {
+			return err
+		}
+		if data.Signature == "" {
+			return fmt.Errorf("response is missing the whitelist signature")
+		}
+		return nil
+	})
+	s.checkWithSession(version_V3, prefix+"whitelist GET", false, func() error {
+		data, err := client.Whitelist_Get(s.ctx, s.logger, s.constellationDeployment)
+		if err != nil {
+			return err
+		}
+		return s.checkWhitelist(data.Whitelisted, data.Address.Hex())
+	})
+	s.checkWithSession(version_V3, prefix+"whitelist GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Whitelist_Get(s.ctx, s.logger, invalidDeployment)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+
+	// Minipool deposit signatures
+	s.checkWithSession(version_V3, prefix+"minipool/deposit-signature POST", true, func() error {
+		minipoolAddress, err := newAddress()
+		if err != nil {
+			return err
+		}
+		salt, err := newSalt()
+		if err != nil {
+			return err
+		}
+		data, err := client.MinipoolDepositSignature(s.ctx, s.logger, s.constellationDeployment, minipoolAddress, salt)
+		if err != nil {
+			return err
+		}
+		if data.Signature == "" {
+			return fmt.Errorf("response is missing the minipool deposit signature")
+		}
+		return nil
+	})
+	s.checkWithSession(version_V3, prefix+"minipool/deposit-signature POST: "+common.InvalidDeploymentKey, false, func() error {
+		salt, err := newSalt()
+		if err != nil {
+			return err
+		}
+		_, err = client.MinipoolDepositSignature(s.ctx, s.logger, invalidDeployment, invalidVault, salt)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+
+	// Validators
+	s.checkWithSession(version_V3, prefix+"validators GET", false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, s.constellationDeployment)
+		return err
+	})
+	s.checkWithSession(version_V3, prefix+"validators GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, invalidDeployment)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+	s.checkWithSession(version_V3, prefix+"validators PATCH", false, func() error {
+		return client.Validators_Patch(s.ctx, s.logger, s.constellationDeployment, []common.EncryptedExitData{})
+	})
+	s.checkWithSession(version_V3, prefix+"validators PATCH: "+common.InvalidDeploymentKey, false, func() error {
+		err := client.Validators_Patch(s.ctx, s.logger, invalidDeployment, []common.EncryptedExitData{})
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+}
+
+// Make sure a whitelist response matches what the suite expects. When the suite requested the whitelist signature itself,
+// the node has to be the user's whitelisted node.
+func (s *suite) checkWhitelist(whitelisted bool, address string) error {
+	if s.config.Admin == nil {
+		return nil
+	}
+	if !whitelisted {
+		return fmt.Errorf("the user isn't reported as having a whitelisted node")
+	}
+	if address != s.nodeAddress.Hex() {
+		return fmt.Errorf("the user's whitelisted node is [%s] but expected [%s]", address, s.nodeAddress.Hex())
+	}
+	return nil
+}
+
+// Generate a random minipool salt
+func newSalt() (*big.Int, error) {
+	bytes := make([]byte, 32)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error generating salt: %w", err)
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
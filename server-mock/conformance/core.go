@@ -0,0 +1,129 @@
+package conformance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/core"
+)
+
+// Core routes, which are the same in every API version
+type coreClient interface {
+	NodeAddress(ctx context.Context, logger *slog.Logger, email string, nodeWallet ethcommon.Address, signer func([]byte) ([]byte, error)) error
+	Nonce(ctx context.Context, logger *slog.Logger) (core.NonceData, error)
+	Login(ctx context.Context, logger *slog.Logger, nonce string, address ethcommon.Address, signer func([]byte) ([]byte, error)) (core.LoginData, error)
+}
+
+// Check the core routes for an API version, logging the node in along the way
+func (s *suite) runCoreChecks(version string) {
+	prefix := version + "/core/"
+
+	// Nonce
+	s.check(prefix+"nonce GET", false, func() error {
+		client, _ := s.newCoreClient(version)
+		data, err := client.Nonce(s.ctx, s.logger)
+		if err != nil {
+			return err
+		}
+		if data.Nonce == "" || data.Token == "" {
+			return fmt.Errorf("response is missing the nonce or session token")
+		}
+		return nil
+	})
+
+	// Node registration, with a node that's only whitelisted for this check
+	var registeredKey *ecdsa.PrivateKey
+	s.check(prefix+"node-address POST", true, func() error {
+		var err error
+		registeredKey, err = s.whitelistNewNode()
+		if err != nil {
+			return err
+		}
+		client, _ := s.newCoreClient(version)
+		return client.NodeAddress(s.ctx, s.logger, s.email, getAddress(registeredKey), getSigner(registeredKey))
+	})
+	s.check(prefix+"node-address POST: "+core.AddressAlreadyAuthorizedKey, true, func() error {
+		if registeredKey == nil {
+			return skip("the node couldn't be registered")
+		}
+		client, _ := s.newCoreClient(version)
+		err := client.NodeAddress(s.ctx, s.logger, s.email, getAddress(registeredKey), getSigner(registeredKey))
+		return expectError(err, core.ErrAlreadyRegistered)
+	})
+	s.check(prefix+"node-address POST: "+core.AddressMissingWhitelistKey, false, func() error {
+		if s.email == "" {
+			return skip("no user email was provided")
+		}
+		key, address, err := newNodeKey()
+		if err != nil {
+			return err
+		}
+		client, _ := s.newCoreClient(version)
+		err = client.NodeAddress(s.ctx, s.logger, s.email, address, getSigner(key))
+		return expectError(err, core.ErrNotWhitelisted)
+	})
+	s.check(prefix+"node-address POST: "+common.InvalidSignatureKey, true, func() error {
+		key, err := s.whitelistNewNode()
+		if err != nil {
+			return err
+		}
+		otherKey, _, err := newNodeKey()
+		if err != nil {
+			return err
+		}
+		client, _ := s.newCoreClient(version)
+		err = client.NodeAddress(s.ctx, s.logger, s.email, getAddress(key), getSigner(otherKey))
+		return expectError(err, common.ErrInvalidSignature)
+	})
+
+	// Login
+	s.check(prefix+"login POST", false, func() error {
+		client, setSessionToken := s.getCoreClient(version)
+		err := s.login(client, setSessionToken, s.nodeKey)
+		if err != nil {
+			return err
+		}
+		s.sessions[version] = true
+		return nil
+	})
+	s.check(prefix+"login POST: "+core.UnregisteredAddressKey, false, func() error {
+		key, _, err := newNodeKey()
+		if err != nil {
+			return err
+		}
+		client, setSessionToken := s.newCoreClient(version)
+		err = s.login(client, setSessionToken, key)
+		return expectError(err, core.ErrUnregisteredNode)
+	})
+	s.check(prefix+"login POST: "+common.InvalidSignatureKey, false, func() error {
+		otherKey, _, err := newNodeKey()
+		if err != nil {
+			return err
+		}
+		client, setSessionToken := s.newCoreClient(version)
+		nonceData, err := client.Nonce(s.ctx, s.logger)
+		if err != nil {
+			return fmt.Errorf("error getting nonce: %w", err)
+		}
+		setSessionToken(nonceData.Token)
+		_, err = client.Login(s.ctx, s.logger, nonceData.Nonce, s.nodeAddress, getSigner(otherKey))
+		return expectError(err, common.ErrInvalidSignature)
+	})
+}
+
+// Whitelist a new node on the user account without registering it
+func (s *suite) whitelistNewNode() (*ecdsa.PrivateKey, error) {
+	key, address, err := newNodeKey()
+	if err != nil {
+		return nil, err
+	}
+	err = s.config.Admin.WhitelistNode(s.ctx, s.logger, s.email, address)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
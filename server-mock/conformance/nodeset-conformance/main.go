@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/conformance"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	Version string = "1.0.0"
+)
+
+// Run
+func main() {
+	// Initialise application
+	app := cli.NewApp()
+
+	// Set application info
+	app.Name = "nodeset-conformance"
+	app.Usage = "Checks that a nodeset.io server behaves the way the client bindings expect"
+	app.Version = Version
+	app.Authors = []*cli.Author{
+		{
+			Name:  "NodeSet",
+			Email: "info@nodeset.io",
+		},
+	}
+	app.Copyright = "(C) 2024 NodeSet LLC"
+
+	apiUrlFlag := &cli.StringFlag{
+		Name:     "api-url",
+		Aliases:  []string{"u"},
+		Usage:    "The base URL of the server's API routes, such as http://localhost:50512/api",
+		Required: true,
+	}
+	adminUrlFlag := &cli.StringFlag{
+		Name:    "admin-url",
+		Aliases: []string{"a"},
+		Usage:   "The base URL of the server's admin routes, such as http://localhost:50512/admin. When provided, the suite creates its own user, nodes, and deployments and runs every check.",
+	}
+	timeoutFlag := &cli.DurationFlag{
+		Name:    "timeout",
+		Aliases: []string{"t"},
+		Usage:   "The timeout for each request",
+		Value:   10 * time.Second,
+	}
+	emailFlag := &cli.StringFlag{
+		Name:  "email",
+		Usage: "The email of the user account that owns the node, if the server doesn't have admin routes",
+	}
+	nodeKeyFlag := &cli.StringFlag{
+		Name:  "node-key",
+		Usage: "The hex-encoded private key of a registered node, if the server doesn't have admin routes",
+	}
+	stakeWiseDeploymentFlag := &cli.StringFlag{
+		Name:  "stakewise-deployment",
+		Usage: "The StakeWise deployment to check, if the server doesn't have admin routes",
+	}
+	stakeWiseVaultFlag := &cli.StringFlag{
+		Name:  "stakewise-vault",
+		Usage: "The address of the StakeWise vault to check, if the server doesn't have admin routes",
+	}
+	constellationDeploymentFlag := &cli.StringFlag{
+		Name:  "constellation-deployment",
+		Usage: "The Constellation deployment to check, if the server doesn't have admin routes",
+	}
+	rpcUrlFlag := &cli.StringFlag{
+		Name:  "rpc-url",
+		Usage: "The URL of an Execution client to get the Beacon deposit root from, for registering validators. For the mock, use its /eth route.",
+	}
+	depositContractFlag := &cli.StringFlag{
+		Name:  "deposit-contract",
+		Usage: "The address of the Beacon deposit contract on the Execution client's chain",
+		Value: "0x00000000219ab540356cBB839Cbe05303d7705Fa",
+	}
+	verboseFlag := &cli.BoolFlag{
+		Name:  "verbose",
+		Usage: "Log every request the suite makes",
+	}
+
+	app.Flags = []cli.Flag{
+		apiUrlFlag,
+		adminUrlFlag,
+		timeoutFlag,
+		emailFlag,
+		nodeKeyFlag,
+		stakeWiseDeploymentFlag,
+		stakeWiseVaultFlag,
+		constellationDeploymentFlag,
+		rpcUrlFlag,
+		depositContractFlag,
+		verboseFlag,
+	}
+	app.Action = func(c *cli.Context) error {
+		level := slog.LevelWarn
+		if c.Bool(verboseFlag.Name) {
+			level = slog.LevelDebug
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+		ctx := context.Background()
+
+		// Build the config
+		timeout := c.Duration(timeoutFlag.Name)
+		config := conformance.Config{
+			ApiUrl:                  c.String(apiUrlFlag.Name),
+			Timeout:                 timeout,
+			Email:                   c.String(emailFlag.Name),
+			StakeWiseDeployment:     c.String(stakeWiseDeploymentFlag.Name),
+			ConstellationDeployment: c.String(constellationDeploymentFlag.Name),
+		}
+		if adminUrl := c.String(adminUrlFlag.Name); adminUrl != "" {
+			config.Admin = client.NewAdminClient(adminUrl, timeout)
+		}
+		if nodeKey := c.String(nodeKeyFlag.Name); nodeKey != "" {
+			key, err := parseNodeKey(nodeKey)
+			if err != nil {
+				return err
+			}
+			config.NodeKey = key
+		}
+		if vault := c.String(stakeWiseVaultFlag.Name); vault != "" {
+			if !ethcommon.IsHexAddress(vault) {
+				return fmt.Errorf("invalid StakeWise vault address [%s]", vault)
+			}
+			config.StakeWiseVault = ethcommon.HexToAddress(vault)
+		}
+		if rpcUrl := c.String(rpcUrlFlag.Name); rpcUrl != "" {
+			depositContract := c.String(depositContractFlag.Name)
+			if !ethcommon.IsHexAddress(depositContract) {
+				return fmt.Errorf("invalid deposit contract address [%s]", depositContract)
+			}
+			provider, err := v3stakewise.NewRpcDepositRootProvider(ctx, rpcUrl, ethcommon.HexToAddress(depositContract))
+			if err != nil {
+				return err
+			}
+			config.DepositRootProvider = provider
+		}
+
+		// Run the suite
+		report, err := conformance.Run(ctx, logger, config)
+		if err != nil {
+			return err
+		}
+		err = report.Write(os.Stdout)
+		if err != nil {
+			return fmt.Errorf("error writing report: %w", err)
+		}
+		if !report.Passed() {
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	// Run application
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Parse a hex-encoded node private key
+func parseNodeKey(value string) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid node key: %w", err)
+	}
+	return key, nil
+}
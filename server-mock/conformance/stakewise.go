@@ -0,0 +1,284 @@
+package conformance
+
+import (
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+const (
+	// Session token that no server should accept
+	invalidSessionToken string = "conformance-invalid-session"
+
+	// Deployment that no server should recognize
+	invalidDeployment string = "conformance-invalid-deployment"
+)
+
+var (
+	// StakeWise vault that no server should recognize
+	invalidVault ethcommon.Address = ethcommon.HexToAddress("0x00000000000000000000000000000000000000ff")
+)
+
+// Check the v2 StakeWise routes
+func (s *suite) runV2StakeWiseChecks() {
+	prefix := version_V2 + "/stakewise/"
+	client := s.v2Client.StakeWise
+
+	// Deployments and vaults
+	s.checkWithSession(version_V2, prefix+"deployments GET", false, func() error {
+		data, err := client.Deployments(s.ctx, s.logger)
+		if err != nil {
+			return err
+		}
+		return checkDeployments(data, s.stakeWiseDeployment)
+	})
+	s.checkWithSession(version_V2, prefix+"vaults GET", false, func() error {
+		data, err := client.Vaults(s.ctx, s.logger, s.stakeWiseDeployment)
+		if err != nil {
+			return err
+		}
+		for _, vault := range data.Vaults {
+			if vault == s.stakeWiseVault {
+				return nil
+			}
+		}
+		return fmt.Errorf("vault [%s] is missing from the response", s.stakeWiseVault.Hex())
+	})
+	s.checkWithSession(version_V2, prefix+"vaults GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Vaults(s.ctx, s.logger, invalidDeployment)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+	s.check(prefix+"vaults GET: "+common.InvalidSessionKey, false, func() error {
+		invalidClient := apiv2.NewNodeSetClient(s.config.ApiUrl, s.config.Timeout)
+		invalidClient.SetSessionToken(invalidSessionToken)
+		_, err := invalidClient.StakeWise.Vaults(s.ctx, s.logger, s.stakeWiseDeployment)
+		return expectError(err, common.ErrInvalidSession)
+	})
+
+	// Deposit data
+	s.checkWithSession(version_V2, prefix+"deposit-data/meta GET", false, func() error {
+		_, err := client.DepositDataMeta(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault)
+		return err
+	})
+	s.checkWithSession(version_V2, prefix+"deposit-data/meta GET: "+common.InvalidVaultKey, false, func() error {
+		_, err := client.DepositDataMeta(s.ctx, s.logger, s.stakeWiseDeployment, invalidVault)
+		return expectError(err, common.ErrInvalidVault)
+	})
+	s.checkWithSession(version_V2, prefix+"deposit-data GET", false, func() error {
+		_, err := client.DepositData_Get(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault)
+		return err
+	})
+	var uploadedPubkey beacon.ValidatorPubkey
+	var uploadedKey *types.BLSPrivateKey
+	s.checkWithSession(version_V2, prefix+"deposit-data POST", true, func() error {
+		key, depositData, err := s.newDepositData()
+		if err != nil {
+			return err
+		}
+		err = client.DepositData_Post(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault, []beacon.ExtendedDepositData{depositData})
+		if err != nil {
+			return err
+		}
+		uploadedKey = key
+		uploadedPubkey = beacon.ValidatorPubkey(depositData.PublicKey)
+		return nil
+	})
+
+	// Validators
+	s.checkWithSession(version_V2, prefix+"validators GET", false, func() error {
+		data, err := client.Validators_Get(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault)
+		if err != nil {
+			return err
+		}
+		if uploadedKey == nil {
+			return nil
+		}
+		for _, validator := range data.Validators {
+			if validator.Pubkey == uploadedPubkey {
+				return nil
+			}
+		}
+		return fmt.Errorf("uploaded validator [%s] is missing from the response", uploadedPubkey.HexWithPrefix())
+	})
+	s.checkWithSession(version_V2, prefix+"validators GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, invalidDeployment, s.stakeWiseVault)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+	s.checkWithSession(version_V2, prefix+"validators GET: "+common.InvalidVaultKey, false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, s.stakeWiseDeployment, invalidVault)
+		return expectError(err, common.ErrInvalidVault)
+	})
+	s.checkWithSession(version_V2, prefix+"validators PATCH", true, func() error {
+		if uploadedKey == nil {
+			return skip("no deposit data was uploaded")
+		}
+		exitData, err := s.newEncryptedExit(uploadedKey)
+		if err != nil {
+			return err
+		}
+		err = client.Validators_Patch(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault, []common.EncryptedExitData{exitData})
+		if err != nil {
+			return err
+		}
+		data, err := client.Validators_Get(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault)
+		if err != nil {
+			return err
+		}
+		for _, validator := range data.Validators {
+			if validator.Pubkey == uploadedPubkey && validator.ExitMessageUploaded {
+				return nil
+			}
+		}
+		return fmt.Errorf("validator [%s] isn't reported as having an exit message", uploadedPubkey.HexWithPrefix())
+	})
+}
+
+// Check the v3 StakeWise routes
+func (s *suite) runV3StakeWiseChecks() {
+	prefix := version_V3 + "/stakewise/"
+	client := s.v3Client.StakeWise
+
+	// Deployments and vaults
+	s.checkWithSession(version_V3, prefix+"deployments GET", false, func() error {
+		data, err := client.Deployments(s.ctx, s.logger)
+		if err != nil {
+			return err
+		}
+		return checkDeployments(data, s.stakeWiseDeployment)
+	})
+	s.checkWithSession(version_V3, prefix+"vaults GET", false, func() error {
+		data, err := client.Vaults(s.ctx, s.logger, s.stakeWiseDeployment)
+		if err != nil {
+			return err
+		}
+		for _, vault := range data.Vaults {
+			if vault.Address == s.stakeWiseVault {
+				return nil
+			}
+		}
+		return fmt.Errorf("vault [%s] is missing from the response", s.stakeWiseVault.Hex())
+	})
+	s.checkWithSession(version_V3, prefix+"vaults GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Vaults(s.ctx, s.logger, invalidDeployment)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+	s.check(prefix+"vaults GET: "+common.InvalidSessionKey, false, func() error {
+		invalidClient := apiv3.NewNodeSetClient(s.config.ApiUrl, s.config.Timeout)
+		invalidClient.SetSessionToken(invalidSessionToken)
+		_, err := invalidClient.StakeWise.Vaults(s.ctx, s.logger, s.stakeWiseDeployment)
+		return expectError(err, common.ErrInvalidSession)
+	})
+
+	// Validator metadata
+	s.checkWithSession(version_V3, prefix+"validators/meta GET", false, func() error {
+		data, err := client.ValidatorMeta_Get(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault)
+		if err != nil {
+			return err
+		}
+		if data.Available > data.Max {
+			return fmt.Errorf("available validator count %d is more than the max of %d", data.Available, data.Max)
+		}
+		return nil
+	})
+	s.checkWithSession(version_V3, prefix+"validators/meta GET: "+common.InvalidVaultKey, false, func() error {
+		_, err := client.ValidatorMeta_Get(s.ctx, s.logger, s.stakeWiseDeployment, invalidVault)
+		return expectError(err, common.ErrInvalidVault)
+	})
+
+	// Validator registration
+	var registeredPubkey *beacon.ValidatorPubkey
+	s.checkWithSession(version_V3, prefix+"validators POST", true, func() error {
+		if s.config.DepositRootProvider == nil {
+			return skip("no deposit root provider was configured")
+		}
+		details, pubkey, err := s.newRegistrationDetails()
+		if err != nil {
+			return err
+		}
+		depositRoot, err := s.config.DepositRootProvider.GetDepositRoot(s.ctx)
+		if err != nil {
+			return fmt.Errorf("error getting deposit root: %w", err)
+		}
+		data, err := client.Validators_Post(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault, details, depositRoot)
+		if err != nil {
+			return err
+		}
+		if data.Signature == "" {
+			return fmt.Errorf("response is missing the validator manager signature")
+		}
+		registeredPubkey = &pubkey
+		return nil
+	})
+	s.checkWithSession(version_V3, prefix+"validators POST: stale deposit root", true, func() error {
+		details, _, err := s.newRegistrationDetails()
+		if err != nil {
+			return err
+		}
+		_, err = client.Validators_Post(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault, details, ethcommon.HexToHash("0xbad"))
+		return expectError(err, v3stakewise.ErrDepositRootAlreadyAssigned)
+	})
+
+	// Validators
+	s.checkWithSession(version_V3, prefix+"validators GET", false, func() error {
+		data, err := client.Validators_Get(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault)
+		if err != nil {
+			return err
+		}
+		if registeredPubkey == nil {
+			return nil
+		}
+		for _, validator := range data.Validators {
+			if validator.Pubkey != *registeredPubkey {
+				continue
+			}
+			if !validator.ExitMessageUploaded {
+				return fmt.Errorf("registered validator [%s] isn't reported as having an exit message", registeredPubkey.HexWithPrefix())
+			}
+			return nil
+		}
+		return fmt.Errorf("registered validator [%s] is missing from the response", registeredPubkey.HexWithPrefix())
+	})
+	s.checkWithSession(version_V3, prefix+"validators GET: "+common.InvalidDeploymentKey, false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, invalidDeployment, s.stakeWiseVault)
+		return expectError(err, common.ErrInvalidDeployment)
+	})
+	s.checkWithSession(version_V3, prefix+"validators GET: "+common.InvalidVaultKey, false, func() error {
+		_, err := client.Validators_Get(s.ctx, s.logger, s.stakeWiseDeployment, invalidVault)
+		return expectError(err, common.ErrInvalidVault)
+	})
+}
+
+// Generate the details for registering a new validator with the v3 StakeWise routes
+func (s *suite) newRegistrationDetails() ([]v3stakewise.ValidatorRegistrationDetails, beacon.ValidatorPubkey, error) {
+	key, depositData, err := s.newDepositData()
+	if err != nil {
+		return nil, beacon.ValidatorPubkey{}, err
+	}
+	exitData, err := s.newEncryptedExit(key)
+	if err != nil {
+		return nil, beacon.ValidatorPubkey{}, err
+	}
+	details := []v3stakewise.ValidatorRegistrationDetails{
+		{
+			DepositData: depositData,
+			ExitMessage: exitData.ExitMessage,
+		},
+	}
+	return details, beacon.ValidatorPubkey(depositData.PublicKey), nil
+}
+
+// Make sure a deployments response includes the provided deployment
+func checkDeployments(data common.DeploymentsData, deployment string) error {
+	for _, candidate := range data.Deployments {
+		if candidate.Name == deployment {
+			return nil
+		}
+	}
+	return fmt.Errorf("deployment [%s] is missing from the response", deployment)
+}
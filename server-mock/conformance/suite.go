@@ -0,0 +1,323 @@
+package conformance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	nsutil "github.com/nodeset-org/nodeset-client-go/utils"
+)
+
+const (
+	// API versions the suite checks
+	version_V2 string = "v2"
+	version_V3 string = "v3"
+
+	// Name of the StakeWise vault the suite creates with admin capabilities
+	provisionedVaultName string = "Conformance Vault"
+)
+
+var (
+	// Chain ID of the deployments the suite creates with admin capabilities
+	provisionedChainID *big.Int = big.NewInt(31337)
+
+	// Balance of the StakeWise vault the suite creates with admin capabilities, enough for a few validators
+	provisionedVaultBalance *big.Int = new(big.Int).Mul(big.NewInt(320), big.NewInt(1e18))
+)
+
+// Returned by a check that can't run against the server
+type skipError struct {
+	reason string
+}
+
+func (e skipError) Error() string {
+	return e.reason
+}
+
+// Skip the running check
+func skip(format string, args ...any) error {
+	return skipError{reason: fmt.Sprintf(format, args...)}
+}
+
+// State for a conformance run
+type suite struct {
+	ctx    context.Context
+	logger *slog.Logger
+	config Config
+	report *Report
+
+	// The user, node, and deployments the checks use
+	email                   string
+	nodeKey                 *ecdsa.PrivateKey
+	nodeAddress             ethcommon.Address
+	stakeWiseDeployment     string
+	stakeWiseVault          ethcommon.Address
+	constellationDeployment string
+
+	// The recipient exit messages are encrypted for, which is only known when the suite set the server's key itself
+	encryptionRecipient string
+
+	// Clients for the node, and whether they've logged in
+	v2Client *apiv2.NodeSetClient
+	v3Client *apiv3.NodeSetClient
+	sessions map[string]bool
+}
+
+// Creates a new suite, provisioning its state with the admin capabilities if they're available
+func newSuite(ctx context.Context, logger *slog.Logger, config Config) (*suite, error) {
+	s := &suite{
+		ctx:      ctx,
+		logger:   logger,
+		config:   config,
+		report:   &Report{Results: []Result{}},
+		v2Client: apiv2.NewNodeSetClient(config.ApiUrl, config.Timeout),
+		v3Client: apiv3.NewNodeSetClient(config.ApiUrl, config.Timeout),
+		sessions: map[string]bool{},
+	}
+	if config.Admin != nil {
+		err := s.provision()
+		if err != nil {
+			return nil, fmt.Errorf("error provisioning conformance state: %w", err)
+		}
+		return s, nil
+	}
+
+	// Use the existing state from the config
+	if config.NodeKey == nil {
+		return nil, fmt.Errorf("a registered node key is required when the server doesn't have admin capabilities")
+	}
+	if config.StakeWiseDeployment == "" || config.ConstellationDeployment == "" {
+		return nil, fmt.Errorf("StakeWise and Constellation deployments are required when the server doesn't have admin capabilities")
+	}
+	s.email = config.Email
+	s.nodeKey = config.NodeKey
+	s.nodeAddress = getAddress(config.NodeKey)
+	s.stakeWiseDeployment = config.StakeWiseDeployment
+	s.stakeWiseVault = config.StakeWiseVault
+	s.constellationDeployment = config.ConstellationDeployment
+	return s, nil
+}
+
+// Create a user with a registered node and deployments for it to use. Everything gets a unique name so runs against the
+// same server don't interfere with each other.
+func (s *suite) provision() error {
+	admin := s.config.Admin
+	runID, err := newRunID()
+	if err != nil {
+		return err
+	}
+	s.email = fmt.Sprintf("conformance-%s@nodeset.io", runID)
+	s.stakeWiseDeployment = "conformance-" + runID
+	s.constellationDeployment = "conformance-" + runID
+	s.nodeKey, s.nodeAddress, err = newNodeKey()
+	if err != nil {
+		return err
+	}
+
+	// Set up StakeWise
+	s.stakeWiseVault, err = newAddress()
+	if err != nil {
+		return err
+	}
+	err = admin.AddStakeWiseDeployment(s.ctx, s.logger, s.stakeWiseDeployment, provisionedChainID)
+	if err != nil {
+		return err
+	}
+	err = admin.AddStakeWiseVault(s.ctx, s.logger, s.stakeWiseDeployment, provisionedVaultName, s.stakeWiseVault)
+	if err != nil {
+		return err
+	}
+	err = admin.SetStakeWiseVaultBalance(s.ctx, s.logger, s.stakeWiseDeployment, s.stakeWiseVault, provisionedVaultBalance)
+	if err != nil {
+		return err
+	}
+
+	// Set up Constellation
+	whitelist, err := newAddress()
+	if err != nil {
+		return err
+	}
+	superNode, err := newAddress()
+	if err != nil {
+		return err
+	}
+	err = admin.AddConstellationDeployment(s.ctx, s.logger, s.constellationDeployment, provisionedChainID, whitelist, superNode)
+	if err != nil {
+		return err
+	}
+	constellationKey, _, err := newNodeKey()
+	if err != nil {
+		return err
+	}
+	err = admin.SetConstellationPrivateKey(s.ctx, s.logger, s.constellationDeployment, constellationKey)
+	if err != nil {
+		return err
+	}
+
+	// Set the exit message encryption key
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return fmt.Errorf("error generating encryption key: %w", err)
+	}
+	err = admin.SetEncryptionKey(s.ctx, s.logger, identity)
+	if err != nil {
+		return err
+	}
+	s.encryptionRecipient = identity.Recipient().String()
+
+	// Create the user and register its node
+	err = admin.AddUser(s.ctx, s.logger, s.email)
+	if err != nil {
+		return err
+	}
+	err = admin.WhitelistNode(s.ctx, s.logger, s.email, s.nodeAddress)
+	if err != nil {
+		return err
+	}
+	err = s.v3Client.Core.NodeAddress(s.ctx, s.logger, s.email, s.nodeAddress, getSigner(s.nodeKey))
+	if err != nil {
+		return fmt.Errorf("error registering node [%s]: %w", s.nodeAddress.Hex(), err)
+	}
+	s.logger.Info("Provisioned conformance state",
+		"email", s.email,
+		"node", s.nodeAddress.Hex(),
+		"deployment", s.stakeWiseDeployment,
+	)
+	return nil
+}
+
+// Run a check and record its result. Checks that change the server's state are skipped without admin capabilities, since the
+// suite can't clean up after them.
+func (s *suite) check(name string, changesState bool, run func() error) {
+	if changesState && s.config.Admin == nil {
+		s.record(name, Status_Skip, "changes server state, so it needs admin capabilities")
+		return
+	}
+	err := run()
+	var skipErr skipError
+	switch {
+	case err == nil:
+		s.record(name, Status_Pass, "")
+	case errors.As(err, &skipErr):
+		s.record(name, Status_Skip, skipErr.reason)
+	default:
+		s.record(name, Status_Fail, err.Error())
+	}
+}
+
+// Run a check that needs the node to be logged in with the provided API version
+func (s *suite) checkWithSession(version string, name string, changesState bool, run func() error) {
+	s.check(name, changesState, func() error {
+		if !s.sessions[version] {
+			return skip("the node couldn't log in with the %s API", version)
+		}
+		return run()
+	})
+}
+
+// Record the result of a check
+func (s *suite) record(name string, status Status, message string) {
+	s.report.Results = append(s.report.Results, Result{
+		Name:    name,
+		Status:  status,
+		Message: message,
+	})
+	s.logger.Debug("Conformance check finished", "name", name, "status", status, "message", message)
+}
+
+// Make sure a request failed with the expected error
+func expectError(err error, expected error) error {
+	if errors.Is(err, expected) {
+		return nil
+	}
+	if err == nil {
+		return fmt.Errorf("expected [%v] but the request succeeded", expected)
+	}
+	return fmt.Errorf("expected [%v] but got [%v]", expected, err)
+}
+
+// Log a client in with a node key, setting its session token
+func (s *suite) login(coreClient coreClient, setSessionToken func(token string), key *ecdsa.PrivateKey) error {
+	nonceData, err := coreClient.Nonce(s.ctx, s.logger)
+	if err != nil {
+		return fmt.Errorf("error getting nonce: %w", err)
+	}
+	setSessionToken(nonceData.Token)
+	loginData, err := coreClient.Login(s.ctx, s.logger, nonceData.Nonce, getAddress(key), getSigner(key))
+	if err != nil {
+		return err
+	}
+	if loginData.Token != "" {
+		setSessionToken(loginData.Token)
+	}
+	return nil
+}
+
+// Get the node's client for the provided API version
+func (s *suite) getCoreClient(version string) (coreClient, func(token string)) {
+	if version == version_V2 {
+		return s.v2Client.Core, s.v2Client.SetSessionToken
+	}
+	return s.v3Client.Core, s.v3Client.SetSessionToken
+}
+
+// Create a separate client for the provided API version, for requests that shouldn't affect the node's session
+func (s *suite) newCoreClient(version string) (coreClient, func(token string)) {
+	if version == version_V2 {
+		client := apiv2.NewNodeSetClient(s.config.ApiUrl, s.config.Timeout)
+		return client.Core, client.SetSessionToken
+	}
+	client := apiv3.NewNodeSetClient(s.config.ApiUrl, s.config.Timeout)
+	return client.Core, client.SetSessionToken
+}
+
+// Get a signer for the provided key
+func getSigner(key *ecdsa.PrivateKey) func([]byte) ([]byte, error) {
+	return func(message []byte) ([]byte, error) {
+		return nsutil.CreateSignature(message, key)
+	}
+}
+
+// Get the address of a node key
+func getAddress(key *ecdsa.PrivateKey) ethcommon.Address {
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// Generate a new node key and its address
+func newNodeKey() (*ecdsa.PrivateKey, ethcommon.Address, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, ethcommon.Address{}, fmt.Errorf("error generating node key: %w", err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey), nil
+}
+
+// Generate a random address
+func newAddress() (ethcommon.Address, error) {
+	var address ethcommon.Address
+	_, err := rand.Read(address[:])
+	if err != nil {
+		return ethcommon.Address{}, fmt.Errorf("error generating address: %w", err)
+	}
+	return address, nil
+}
+
+// Generate a random ID for the run
+func newRunID() (string, error) {
+	bytes := make([]byte, 4)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", fmt.Errorf("error generating run ID: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
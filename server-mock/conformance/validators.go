@@ -0,0 +1,93 @@
+package conformance
+
+import (
+	"fmt"
+
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+const (
+	// Amount of the deposits the suite generates, in gwei
+	depositAmount uint64 = 32e9
+
+	// Validator index and epoch of the exit messages the suite generates. The validators are never deposited, so they don't
+	// have a real index and any epoch is accepted.
+	exitValidatorIndex string = "0"
+	exitEpoch          uint64 = 0
+)
+
+var (
+	// Fork version the suite's deposit data and exit messages are signed for. Servers only check signatures for validators
+	// that are on the Beacon chain, so a placeholder is fine.
+	forkVersion []byte = make([]byte, 4)
+
+	// Genesis validators root the suite's exit messages are signed for
+	genesisValidatorsRoot []byte = make([]byte, 32)
+)
+
+// Generate a new validator key
+func newValidatorKey() (*types.BLSPrivateKey, error) {
+	err := validator.InitializeBls()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing BLS: %w", err)
+	}
+	key, err := types.GenerateBLSPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("error generating validator key: %w", err)
+	}
+	return key, nil
+}
+
+// Generate deposit data for a new validator in the StakeWise vault
+func (s *suite) newDepositData() (*types.BLSPrivateKey, beacon.ExtendedDepositData, error) {
+	key, err := newValidatorKey()
+	if err != nil {
+		return nil, beacon.ExtendedDepositData{}, err
+	}
+	depositData, err := validator.GetDepositData(
+		s.logger,
+		key,
+		validator.GetWithdrawalCredsFromAddress(s.stakeWiseVault),
+		forkVersion,
+		depositAmount,
+		s.stakeWiseDeployment,
+	)
+	if err != nil {
+		return nil, beacon.ExtendedDepositData{}, fmt.Errorf("error generating deposit data: %w", err)
+	}
+	return key, depositData, nil
+}
+
+// Generate an exit message for the validator, encrypted for the server
+func (s *suite) newEncryptedExit(key *types.BLSPrivateKey) (common.EncryptedExitData, error) {
+	if s.encryptionRecipient == "" {
+		return common.EncryptedExitData{}, skip("the server's exit message encryption key isn't known")
+	}
+	domain, err := types.ComputeDomain(types.DomainVoluntaryExit, forkVersion, genesisValidatorsRoot)
+	if err != nil {
+		return common.EncryptedExitData{}, fmt.Errorf("error computing exit domain: %w", err)
+	}
+	signature, err := validator.GetSignedExitMessage(key, exitValidatorIndex, exitEpoch, domain)
+	if err != nil {
+		return common.EncryptedExitData{}, fmt.Errorf("error signing exit message: %w", err)
+	}
+	exitMessage := common.ExitMessage{
+		Message: common.ExitMessageDetails{
+			Epoch:          fmt.Sprint(exitEpoch),
+			ValidatorIndex: exitValidatorIndex,
+		},
+		Signature: signature.HexWithPrefix(),
+	}
+	encryptedMessage, err := common.EncryptSignedExitMessage(exitMessage, s.encryptionRecipient)
+	if err != nil {
+		return common.EncryptedExitData{}, fmt.Errorf("error encrypting exit message: %w", err)
+	}
+	pubkey := beacon.ValidatorPubkey(key.PublicKey().Marshal())
+	return common.EncryptedExitData{
+		Pubkey:      pubkey.HexWithPrefix(),
+		ExitMessage: encryptedMessage,
+	}, nil
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// Default timeout for calls made by the RPC backend
+	DefaultRpcBackendTimeout time.Duration = 10 * time.Second
+
+	// ABI for the contract functions the RPC backend reads
+	constellationBackendAbiString string = `[
+		{"type":"function","name":"nonces","stateMutability":"view","inputs":[{"name":"","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"getMinipoolPubkey","stateMutability":"view","inputs":[{"name":"_minipoolAddress","type":"address"}],"outputs":[{"name":"","type":"bytes"}]}
+	]`
+)
+
+var (
+	// The backend reads its state from a chain and can't modify it directly
+	ErrReadOnlyBackend error = errors.New("the Constellation chain backend is read-only; submit a transaction to the chain instead")
+
+	// The backend's connection was closed, usually because the deployment switched to another backend
+	ErrBackendClosed error = errors.New("the Constellation chain backend has been closed")
+
+	// Parsed ABI for the RPC backend
+	constellationBackendAbi abi.ABI
+)
+
+func init() {
+	var err error
+	constellationBackendAbi, err = abi.JSON(strings.NewReader(constellationBackendAbiString))
+	if err != nil {
+		panic(fmt.Sprintf("error parsing Constellation backend ABI: %s", err.Error()))
+	}
+}
+
+// Source of the Constellation state that lives on the Execution layer: signature nonces and the validators assigned to minipools
+type ConstellationChainBackend interface {
+	// Get the Whitelist contract's signature nonce for the address
+	GetWhitelistNonce(address ethcommon.Address) (uint64, error)
+
+	// Increment the Whitelist contract's signature nonce for the address
+	IncrementWhitelistNonce(address ethcommon.Address) error
+
+	// Get the SuperNodeAccount contract's signature nonce for the address
+	GetSuperNodeNonce(address ethcommon.Address) (uint64, error)
+
+	// Increment the SuperNodeAccount contract's signature nonce for the address
+	IncrementSuperNodeNonce(address ethcommon.Address) error
+
+	// Get the validator pubkey for the minipool. Returns false if the minipool doesn't have one.
+	GetMinipoolPubkey(minipool ethcommon.Address) (beacon.ValidatorPubkey, bool, error)
+
+	// Set the validator pubkey for the minipool
+	SetMinipoolPubkey(minipool ethcommon.Address, pubkey beacon.ValidatorPubkey) error
+
+	// Clone the backend for a database snapshot
+	Clone() ConstellationChainBackend
+}
+
+// ==============
+// === Memory ===
+// ==============
+
+// Constellation chain backend that keeps its state in memory; this is the default
+type MemoryConstellationBackend struct {
+	whitelistNonces map[ethcommon.Address]uint64
+	superNodeNonces map[ethcommon.Address]uint64
+	minipoolPubkeys map[ethcommon.Address]beacon.ValidatorPubkey
+}
+
+// Create a new in-memory Constellation chain backend
+func NewMemoryConstellationBackend() *MemoryConstellationBackend {
+	return &MemoryConstellationBackend{
+		whitelistNonces: map[ethcommon.Address]uint64{},
+		superNodeNonces: map[ethcommon.Address]uint64{},
+		minipoolPubkeys: map[ethcommon.Address]beacon.ValidatorPubkey{},
+	}
+}
+
+// Get the Whitelist contract's signature nonce for the address
+func (b *MemoryConstellationBackend) GetWhitelistNonce(address ethcommon.Address) (uint64, error) {
+	return b.whitelistNonces[address], nil
+}
+
+// Increment the Whitelist contract's signature nonce for the address
+func (b *MemoryConstellationBackend) IncrementWhitelistNonce(address ethcommon.Address) error {
+	b.whitelistNonces[address]++
+	return nil
+}
+
+// Get the SuperNodeAccount contract's signature nonce for the address
+func (b *MemoryConstellationBackend) GetSuperNodeNonce(address ethcommon.Address) (uint64, error) {
+	return b.superNodeNonces[address], nil
+}
+
+// Increment the SuperNodeAccount contract's signature nonce for the address
+func (b *MemoryConstellationBackend) IncrementSuperNodeNonce(address ethcommon.Address) error {
+	b.superNodeNonces[address]++
+	return nil
+}
+
+// Get the validator pubkey for the minipool. Returns false if the minipool doesn't have one.
+func (b *MemoryConstellationBackend) GetMinipoolPubkey(minipool ethcommon.Address) (beacon.ValidatorPubkey, bool, error) {
+	pubkey, exists := b.minipoolPubkeys[minipool]
+	return pubkey, exists, nil
+}
+
+// Set the validator pubkey for the minipool
+func (b *MemoryConstellationBackend) SetMinipoolPubkey(minipool ethcommon.Address, pubkey beacon.ValidatorPubkey) error {
+	b.minipoolPubkeys[minipool] = pubkey
+	return nil
+}
+
+// Clone the backend for a database snapshot
+func (b *MemoryConstellationBackend) Clone() ConstellationChainBackend {
+	clone := NewMemoryConstellationBackend()
+	for address, nonce := range b.whitelistNonces {
+		clone.whitelistNonces[address] = nonce
+	}
+	for address, nonce := range b.superNodeNonces {
+		clone.superNodeNonces[address] = nonce
+	}
+	for minipool, pubkey := range b.minipoolPubkeys {
+		clone.minipoolPubkeys[minipool] = pubkey
+	}
+	return clone
+}
+
+// ===========
+// === RPC ===
+// ===========
+
+// Constellation chain backend that reads its state from contracts deployed on a local dev chain (such as anvil or hardhat)
+// over JSON-RPC. The chain owns the state, so the backend is read-only and reverting a database snapshot doesn't revert it.
+type RpcConstellationBackend struct {
+	// Timeout for each call to the chain
+	Timeout time.Duration
+
+	client                 *ethclient.Client
+	whitelistAddress       ethcommon.Address
+	superNodeAddress       ethcommon.Address
+	minipoolManagerAddress ethcommon.Address
+	closed                 bool
+}
+
+// Create a new Constellation chain backend for the Execution client at the provided URL, reading nonces from the
+// Whitelist and SuperNodeAccount contracts and minipool pubkeys from Rocket Pool's RocketMinipoolManager contract
+func NewRpcConstellationBackend(ctx context.Context, rpcUrl string, whitelistAddress ethcommon.Address, superNodeAddress ethcommon.Address, minipoolManagerAddress ethcommon.Address) (*RpcConstellationBackend, error) {
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to Execution client at [%s]: %w", rpcUrl, err)
+	}
+	return &RpcConstellationBackend{
+		Timeout:                DefaultRpcBackendTimeout,
+		client:                 client,
+		whitelistAddress:       whitelistAddress,
+		superNodeAddress:       superNodeAddress,
+		minipoolManagerAddress: minipoolManagerAddress,
+	}, nil
+}
+
+// Get the Whitelist contract's signature nonce for the address
+func (b *RpcConstellationBackend) GetWhitelistNonce(address ethcommon.Address) (uint64, error) {
+	var nonce *big.Int
+	err := b.call(b.whitelistAddress, "nonces", &nonce, address)
+	if err != nil {
+		return 0, fmt.Errorf("error getting whitelist nonce for [%s]: %w", address.Hex(), err)
+	}
+	return nonce.Uint64(), nil
+}
+
+// Nonces are incremented by transactions on the chain, so this always returns ErrReadOnlyBackend
+func (b *RpcConstellationBackend) IncrementWhitelistNonce(address ethcommon.Address) error {
+	return ErrReadOnlyBackend
+}
+
+// Get the SuperNodeAccount contract's signature nonce for the address
+func (b *RpcConstellationBackend) GetSuperNodeNonce(address ethcommon.Address) (uint64, error) {
+	var nonce *big.Int
+	err := b.call(b.superNodeAddress, "nonces", &nonce, address)
+	if err != nil {
+		return 0, fmt.Errorf("error getting supernode nonce for [%s]: %w", address.Hex(), err)
+	}
+	return nonce.Uint64(), nil
+}
+
+// Nonces are incremented by transactions on the chain, so this always returns ErrReadOnlyBackend
+func (b *RpcConstellationBackend) IncrementSuperNodeNonce(address ethcommon.Address) error {
+	return ErrReadOnlyBackend
+}
+
+// Get the validator pubkey for the minipool. Returns false if the minipool doesn't have one.
+func (b *RpcConstellationBackend) GetMinipoolPubkey(minipool ethcommon.Address) (beacon.ValidatorPubkey, bool, error) {
+	var pubkeyBytes []byte
+	err := b.call(b.minipoolManagerAddress, "getMinipoolPubkey", &pubkeyBytes, minipool)
+	if err != nil {
+		return beacon.ValidatorPubkey{}, false, fmt.Errorf("error getting pubkey for minipool [%s]: %w", minipool.Hex(), err)
+	}
+	if len(pubkeyBytes) == 0 {
+		return beacon.ValidatorPubkey{}, false, nil
+	}
+	if len(pubkeyBytes) != beacon.ValidatorPubkeyLength {
+		return beacon.ValidatorPubkey{}, false, fmt.Errorf("pubkey for minipool [%s] has invalid length %d", minipool.Hex(), len(pubkeyBytes))
+	}
+	return beacon.ValidatorPubkey(pubkeyBytes), true, nil
+}
+
+// Minipool pubkeys are set by transactions on the chain, so this always returns ErrReadOnlyBackend
+func (b *RpcConstellationBackend) SetMinipoolPubkey(minipool ethcommon.Address, pubkey beacon.ValidatorPubkey) error {
+	return ErrReadOnlyBackend
+}
+
+// The chain owns the state, so clones share the same backend
+func (b *RpcConstellationBackend) Clone() ConstellationChainBackend {
+	return b
+}
+
+// Close the connection to the Execution client. Calls made after this return ErrBackendClosed.
+func (b *RpcConstellationBackend) Close() {
+	b.closed = true
+	b.client.Close()
+}
+
+// Call a view function on a contract and unpack its single return value into the provided pointer
+func (b *RpcConstellationBackend) call(contract ethcommon.Address, method string, result any, args ...any) error {
+	if b.closed {
+		return ErrBackendClosed
+	}
+	data, err := constellationBackendAbi.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("error packing %s call: %w", method, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.Timeout)
+	defer cancel()
+	response, err := b.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", method, err)
+	}
+	err = constellationBackendAbi.UnpackIntoInterface(result, method, response)
+	if err != nil {
+		return fmt.Errorf("error unpacking %s response: %w", method, err)
+	}
+	return nil
+}
@@ -17,9 +17,23 @@ import (
 	nsutils "github.com/rocket-pool/node-manager-core/utils"
 )
 
+const (
+	// The max number of minipools a node can create on a new deployment
+	DefaultMinipoolLimit int = 100
+)
+
 var (
 	// Signed exit was already uploaded
 	ErrSignedExitAlreadyUploaded error = fmt.Errorf("exit already uploaded")
+
+	// The node has created as many minipools as it's allowed to
+	ErrMinipoolLimitReached error = fmt.Errorf("minipool limit reached")
+
+	// One of the node's previous minipools doesn't have a signed exit message yet
+	ErrMissingExitMessage error = fmt.Errorf("missing signed exit message for a previous minipool")
+
+	// The minipool address has already been used by another minipool
+	ErrMinipoolAddressAlreadyRegistered error = fmt.Errorf("minipool address already registered")
 )
 
 // Deployment for Constellation info
@@ -36,21 +50,32 @@ type ConstellationDeployment struct {
 	// Address of the SuperNodeAccount contract
 	SuperNodeAddress ethcommon.Address
 
+	// The max number of minipools each node can create, unless its user or the node itself has its own limit
+	MinipoolLimit int
+
+	// Whether nodes need to upload signed exits for their previous minipools before they can create a new one
+	RequireExitMessages bool
+
 	// Private key for the ADMIN_ROLE account
 	adminPrivateKey *ecdsa.PrivateKey
 
+	// Minipool limits for specific users, keyed by email
+	userMinipoolLimits map[string]int
+
+	// Minipool limits for specific nodes, which take precedence over user limits
+	nodeMinipoolLimits map[ethcommon.Address]int
+
 	// Map of the whitelisted nodes for each user account
 	whitelistedNodeMap map[string]ethcommon.Address
 
 	// Map of nodes to minipools
 	minipools map[ethcommon.Address][]ethcommon.Address
 
-	// Map of minipools to validators - TEMP until proper reading from an EL
+	// Map of minipools to validators
 	validators map[ethcommon.Address]*ConstellationValidatorInfo
 
-	// Maps for signature nonces - TEMP until proper reading from an EL
-	whitelistNonces map[ethcommon.Address]uint64
-	superNodeNonces map[ethcommon.Address]uint64
+	// Source of the signature nonces and minipool validators from the Execution layer
+	chainBackend ConstellationChainBackend
 
 	// Database handle
 	db *Database
@@ -63,11 +88,13 @@ func newConstellationDeployment(db *Database, id string, chainID *big.Int, white
 		ChainID:            chainID,
 		WhitelistAddress:   whitelistAddress,
 		SuperNodeAddress:   superNodeAddress,
+		MinipoolLimit:      DefaultMinipoolLimit,
+		userMinipoolLimits: map[string]int{},
+		nodeMinipoolLimits: map[ethcommon.Address]int{},
 		whitelistedNodeMap: map[string]ethcommon.Address{},
 		minipools:          map[ethcommon.Address][]ethcommon.Address{},
 		validators:         map[ethcommon.Address]*ConstellationValidatorInfo{},
-		whitelistNonces:    map[ethcommon.Address]uint64{},
-		superNodeNonces:    map[ethcommon.Address]uint64{},
+		chainBackend:       NewMemoryConstellationBackend(),
 		db:                 db,
 	}
 }
@@ -75,11 +102,14 @@ func newConstellationDeployment(db *Database, id string, chainID *big.Int, white
 // Clone the deployment
 func (d *ConstellationDeployment) clone(dbClone *Database) *ConstellationDeployment {
 	clone := newConstellationDeployment(dbClone, d.ID, d.ChainID, d.WhitelistAddress, d.SuperNodeAddress)
-	for address, nonce := range d.whitelistNonces {
-		clone.whitelistNonces[address] = nonce
+	clone.chainBackend = d.chainBackend.Clone()
+	clone.MinipoolLimit = d.MinipoolLimit
+	clone.RequireExitMessages = d.RequireExitMessages
+	for email, limit := range d.userMinipoolLimits {
+		clone.userMinipoolLimits[email] = limit
 	}
-	for address, nonce := range d.superNodeNonces {
-		clone.superNodeNonces[address] = nonce
+	for address, limit := range d.nodeMinipoolLimits {
+		clone.nodeMinipoolLimits[address] = limit
 	}
 	for email, address := range d.whitelistedNodeMap {
 		clone.whitelistedNodeMap[email] = address
@@ -106,24 +136,107 @@ func (d *ConstellationDeployment) SetAdminPrivateKey(privateKey *ecdsa.PrivateKe
 	d.adminPrivateKey = privateKey
 }
 
-// Get the whitelist nonce for the given address
+// Get the backend used to read state from the Execution layer
+func (d *ConstellationDeployment) GetChainBackend() ConstellationChainBackend {
+	return d.chainBackend
+}
+
+// Set the backend used to read state from the Execution layer, closing the previous one if it holds a connection.
+// Snapshots taken while the previous backend was in use share it, so it can't be used after reverting to them.
+func (d *ConstellationDeployment) SetChainBackend(backend ConstellationChainBackend) {
+	if previous, ok := d.chainBackend.(*RpcConstellationBackend); ok && ConstellationChainBackend(previous) != backend {
+		previous.Close()
+	}
+	d.chainBackend = backend
+}
+
+// Get the whitelist nonce for the given address.
+// Errors from the chain backend are logged and reported as a nonce of 0; use TryGetWhitelistNonce to handle them.
 func (d *ConstellationDeployment) GetWhitelistNonce(address ethcommon.Address) uint64 {
-	return d.whitelistNonces[address]
+	nonce, err := d.TryGetWhitelistNonce(address)
+	if err != nil {
+		d.db.logger.Warn("Error getting whitelist nonce", "address", address.Hex(), "err", err)
+	}
+	return nonce
 }
 
-// Increment the whitelist nonce for the given address
+// Get the whitelist nonce for the given address, returning any error from the chain backend
+func (d *ConstellationDeployment) TryGetWhitelistNonce(address ethcommon.Address) (uint64, error) {
+	return d.chainBackend.GetWhitelistNonce(address)
+}
+
+// Increment the whitelist nonce for the given address.
+// Errors from the chain backend are logged; use TryIncrementWhitelistNonce to handle them.
 func (d *ConstellationDeployment) IncrementWhitelistNonce(address ethcommon.Address) {
-	d.whitelistNonces[address]++
+	err := d.TryIncrementWhitelistNonce(address)
+	if err != nil {
+		d.db.logger.Warn("Error incrementing whitelist nonce", "address", address.Hex(), "err", err)
+	}
 }
 
-// Get the SuperNodeAccount nonce for the given address
+// Increment the whitelist nonce for the given address, returning any error from the chain backend
+func (d *ConstellationDeployment) TryIncrementWhitelistNonce(address ethcommon.Address) error {
+	return d.chainBackend.IncrementWhitelistNonce(address)
+}
+
+// Get the SuperNodeAccount nonce for the given address.
+// Errors from the chain backend are logged and reported as a nonce of 0; use TryGetSuperNodeNonce to handle them.
 func (d *ConstellationDeployment) GetSuperNodeNonce(address ethcommon.Address) uint64 {
-	return d.superNodeNonces[address]
+	nonce, err := d.TryGetSuperNodeNonce(address)
+	if err != nil {
+		d.db.logger.Warn("Error getting supernode nonce", "address", address.Hex(), "err", err)
+	}
+	return nonce
 }
 
-// Increment the SuperNodeAccount nonce for the given address
+// Get the SuperNodeAccount nonce for the given address, returning any error from the chain backend
+func (d *ConstellationDeployment) TryGetSuperNodeNonce(address ethcommon.Address) (uint64, error) {
+	return d.chainBackend.GetSuperNodeNonce(address)
+}
+
+// Increment the SuperNodeAccount nonce for the given address.
+// Errors from the chain backend are logged; use TryIncrementSuperNodeNonce to handle them.
 func (d *ConstellationDeployment) IncrementSuperNodeNonce(address ethcommon.Address) {
-	d.superNodeNonces[address]++
+	err := d.TryIncrementSuperNodeNonce(address)
+	if err != nil {
+		d.db.logger.Warn("Error incrementing supernode nonce", "address", address.Hex(), "err", err)
+	}
+}
+
+// Increment the SuperNodeAccount nonce for the given address, returning any error from the chain backend
+func (d *ConstellationDeployment) TryIncrementSuperNodeNonce(address ethcommon.Address) error {
+	return d.chainBackend.IncrementSuperNodeNonce(address)
+}
+
+// Set the max number of minipools the user's nodes can each create
+func (d *ConstellationDeployment) SetUserMinipoolLimit(userEmail string, limit int) {
+	d.userMinipoolLimits[userEmail] = limit
+}
+
+// Remove the user's minipool limit so its nodes use the deployment's limit again
+func (d *ConstellationDeployment) RemoveUserMinipoolLimit(userEmail string) {
+	delete(d.userMinipoolLimits, userEmail)
+}
+
+// Set the max number of minipools the node can create
+func (d *ConstellationDeployment) SetNodeMinipoolLimit(nodeAddress ethcommon.Address, limit int) {
+	d.nodeMinipoolLimits[nodeAddress] = limit
+}
+
+// Remove the node's minipool limit so it uses its user's or the deployment's limit again
+func (d *ConstellationDeployment) RemoveNodeMinipoolLimit(nodeAddress ethcommon.Address) {
+	delete(d.nodeMinipoolLimits, nodeAddress)
+}
+
+// Get the max number of minipools the node can create, using the first limit set for the node, its user, or the deployment
+func (d *ConstellationDeployment) GetMinipoolLimit(node *Node) int {
+	if limit, exists := d.nodeMinipoolLimits[node.Address]; exists {
+		return limit
+	}
+	if limit, exists := d.userMinipoolLimits[node.user.Email]; exists {
+		return limit
+	}
+	return d.MinipoolLimit
 }
 
 // Get the whitelisted address for the given user
@@ -135,6 +248,26 @@ func (d *ConstellationDeployment) GetWhitelistedAddressForUser(userEmail string)
 	return &address
 }
 
+// Remove the user's node from the Constellation whitelist so it has to request a new whitelist signature
+func (d *ConstellationDeployment) RemoveWhitelistedUser(userEmail string) error {
+	if _, exists := d.whitelistedNodeMap[userEmail]; !exists {
+		return fmt.Errorf("user [%s] doesn't have a whitelisted node", userEmail)
+	}
+	delete(d.whitelistedNodeMap, userEmail)
+	return nil
+}
+
+// Remove the exit message that was uploaded for a validator
+func (d *ConstellationDeployment) ClearExitMessage(pubkey beacon.ValidatorPubkey) error {
+	for _, validator := range d.validators {
+		if validator.Pubkey == pubkey {
+			validator.SetExitMessage(nil)
+			return nil
+		}
+	}
+	return fmt.Errorf("validator [%s] not found", pubkey.HexWithPrefix())
+}
+
 // Call this to get a signature for adding the node to the Constellation whitelist
 func (d *ConstellationDeployment) GetWhitelistSignature(nodeAddress ethcommon.Address) ([]byte, error) {
 	if d.adminPrivateKey == nil {
@@ -154,8 +287,12 @@ func (d *ConstellationDeployment) GetWhitelistSignature(nodeAddress ethcommon.Ad
 	chainIdBytes := [32]byte{}
 	d.ChainID.FillBytes(chainIdBytes[:])
 
+	whitelistNonce, err := d.chainBackend.GetWhitelistNonce(nodeAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error getting whitelist nonce: %w", err)
+	}
 	nonceBytes := [32]byte{}
-	nonce := big.NewInt(int64(d.whitelistNonces[nodeAddress]))
+	nonce := big.NewInt(int64(whitelistNonce))
 	nonce.FillBytes(nonceBytes[:])
 
 	sigTypeBytes := [32]byte{} // Always 0 for the mock
@@ -201,8 +338,15 @@ func (d *ConstellationDeployment) GetMinipoolDepositSignature(nodeAddress ethcom
 
 	saltKeccak := crypto.Keccak256(saltBytes[:], nodeAddress[:])
 
+	nonce, err := d.chainBackend.GetSuperNodeNonce(nodeAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error getting supernode nonce: %w", err)
+	}
+	err = d.checkMinipoolRules(node, minipoolAddress, nonce)
+	if err != nil {
+		return nil, err
+	}
 	nonceBytes := [32]byte{}
-	nonce := d.superNodeNonces[nodeAddress]
 	nonceBig := big.NewInt(int64(nonce))
 	nonceBig.FillBytes(nonceBytes[:])
 
@@ -235,33 +379,145 @@ func (d *ConstellationDeployment) GetMinipoolDepositSignature(nodeAddress ethcom
 	return signature, nil
 }
 
-// Set the validator pubkey for the minipool - TEMP until reading from an EL
+// Make sure the node can create a minipool with the provided address for the provided SuperNodeAccount nonce.
+// A nonce the node already has a minipool for replaces that minipool, so it doesn't count towards the limit.
+func (d *ConstellationDeployment) checkMinipoolRules(node *Node, minipoolAddress ethcommon.Address, nonce uint64) error {
+	nodeMinipools := d.minipools[node.Address]
+	for nodeAddress, minipools := range d.minipools {
+		for i, minipool := range minipools {
+			if minipool != minipoolAddress || (nodeAddress == node.Address && uint64(i) == nonce) {
+				continue
+			}
+			return ErrMinipoolAddressAlreadyRegistered
+		}
+	}
+
+	if nonce >= uint64(len(nodeMinipools)) && len(nodeMinipools) >= d.GetMinipoolLimit(node) {
+		return ErrMinipoolLimitReached
+	}
+
+	if !d.RequireExitMessages {
+		return nil
+	}
+	for i, minipool := range nodeMinipools {
+		if uint64(i) == nonce {
+			continue
+		}
+		validator, err := d.getValidatorForMinipool(minipool)
+		if err != nil {
+			return fmt.Errorf("error getting validator for minipool [%s]: %w", minipool.Hex(), err)
+		}
+		if validator != nil && d.RequiresExitMessage(validator) {
+			return ErrMissingExitMessage
+		}
+	}
+	return nil
+}
+
+// Set the validator pubkey for the minipool.
+// Errors from the chain backend are logged; use TrySetValidatorInfoForMinipool to handle them.
 func (d *ConstellationDeployment) SetValidatorInfoForMinipool(minipoolAddress ethcommon.Address, pubkey beacon.ValidatorPubkey) {
-	d.validators[minipoolAddress] = newConstellationValidatorInfo(pubkey)
+	err := d.TrySetValidatorInfoForMinipool(minipoolAddress, pubkey)
+	if err != nil {
+		d.db.logger.Warn("Error setting validator for minipool", "minipool", minipoolAddress.Hex(), "pubkey", pubkey.Hex(), "err", err)
+	}
 }
 
-// Get the validators for the node
+// Set the validator pubkey for the minipool, registering it at the current deposit root and slot time.
+// Returns any error from the chain backend.
+func (d *ConstellationDeployment) TrySetValidatorInfoForMinipool(minipoolAddress ethcommon.Address, pubkey beacon.ValidatorPubkey) error {
+	err := d.chainBackend.SetMinipoolPubkey(minipoolAddress, pubkey)
+	if err != nil {
+		return err
+	}
+	validator := newConstellationValidatorInfo(pubkey)
+	validator.BeaconDepositRoot = d.db.Eth.GetDepositRoot()
+	validator.RegistrationTime = d.db.Beacon.GetSlotTime(d.db.Beacon.GetCurrentSlot())
+	d.validators[minipoolAddress] = validator
+	return nil
+}
+
+// Get the validator info for the minipool, or nil if it doesn't have a validator yet.
+// Validators that were assigned on the chain but haven't been recorded get a fresh info that isn't stored;
+// it's only stored once something is recorded for it, like an exit message.
+func (d *ConstellationDeployment) getValidatorForMinipool(minipoolAddress ethcommon.Address) (*ConstellationValidatorInfo, error) {
+	pubkey, exists, err := d.chainBackend.GetMinipoolPubkey(minipoolAddress)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	validator, exists := d.validators[minipoolAddress]
+	if !exists || validator.Pubkey != pubkey {
+		return newConstellationValidatorInfo(pubkey), nil
+	}
+	return validator, nil
+}
+
+// Get the validators for the node.
+// Errors from the chain backend are logged and the node is reported as having no validators; use TryGetValidatorsForNode to handle them.
 func (d *ConstellationDeployment) GetValidatorsForNode(node *Node) []*ConstellationValidatorInfo {
-	minipools := d.minipools[node.Address]
-	validatorInfos := []*ConstellationValidatorInfo{}
-	for _, minipool := range minipools {
-		validator, exists := d.validators[minipool]
-		if !exists {
+	validators, err := d.TryGetValidatorsForNode(node)
+	if err != nil {
+		d.db.logger.Warn("Error getting validators for node", "node", node.Address.Hex(), "err", err)
+		return []*ConstellationValidatorInfo{}
+	}
+	return validators
+}
+
+// Get the validators for the node, returning any error from the chain backend
+func (d *ConstellationDeployment) TryGetValidatorsForNode(node *Node) ([]*ConstellationValidatorInfo, error) {
+	validators := []*ConstellationValidatorInfo{}
+	for _, minipool := range d.minipools[node.Address] {
+		validator, err := d.getValidatorForMinipool(minipool)
+		if err != nil {
+			return nil, err
+		}
+		if validator == nil {
 			continue
 		}
-		validatorInfos = append(validatorInfos, validator)
+		validators = append(validators, validator)
 	}
-	return validatorInfos
+	return validators, nil
 }
 
-// Get the validator for a node with the given pubkey
-func (d *ConstellationDeployment) GetValidator(node *Node, pubkey beacon.ValidatorPubkey) *ConstellationValidatorInfo {
-	minipools := d.minipools[node.Address]
-	for _, minipool := range minipools {
-		validator, exists := d.validators[minipool]
-		if !exists {
+// Set the exit message for the validator of one of the node's minipools, recording the validator if it was assigned on the chain
+func (d *ConstellationDeployment) setExitMessage(node *Node, pubkey beacon.ValidatorPubkey, exitMessage common.ExitMessage) error {
+	for _, minipool := range d.minipools[node.Address] {
+		validator, err := d.getValidatorForMinipool(minipool)
+		if err != nil {
+			return err
+		}
+		if validator == nil || validator.Pubkey != pubkey {
 			continue
 		}
+		if validator.GetExitMessage() != nil {
+			return ErrSignedExitAlreadyUploaded
+		}
+		err = d.db.Beacon.ValidateExitMessage(pubkey, exitMessage)
+		if err != nil {
+			return err
+		}
+		validator.SetExitMessage(&exitMessage)
+		d.validators[minipool] = validator
+		return nil
+	}
+	return fmt.Errorf("node [%s] doesn't have validator [%s]", node.Address.Hex(), pubkey.Hex())
+}
+
+// Check if a validator still needs an exit message; validators that have already exited on Beacon don't
+func (d *ConstellationDeployment) RequiresExitMessage(validator *ConstellationValidatorInfo) bool {
+	if validator.GetExitMessage() != nil {
+		return false
+	}
+	beaconValidator := d.db.Beacon.GetValidator(validator.Pubkey)
+	return beaconValidator == nil || beaconValidator.Status != BeaconValidatorStatus_Exited
+}
+
+// Get the validator for a node with the given pubkey
+func (d *ConstellationDeployment) GetValidator(node *Node, pubkey beacon.ValidatorPubkey) *ConstellationValidatorInfo {
+	for _, validator := range d.GetValidatorsForNode(node) {
 		if validator.Pubkey == pubkey {
 			return validator
 		}
@@ -272,32 +528,17 @@ func (d *ConstellationDeployment) GetValidator(node *Node, pubkey beacon.Validat
 // Handle a new collection of signed exits from a node for Constellation
 func (d *ConstellationDeployment) HandleSignedExitUpload(node *Node, data []common.ExitData) error {
 	// Add the signed exits
-	minipools := d.minipools[node.Address]
 	for _, signedExit := range data {
 		pubkey, err := beacon.HexToValidatorPubkey(signedExit.Pubkey)
 		if err != nil {
 			return fmt.Errorf("error parsing validator pubkey [%s]: %w", signedExit.Pubkey, err)
 		}
 
-		// Get the validator
-		found := false
-		for _, minipoolAddress := range minipools {
-			validator := d.validators[minipoolAddress]
-			if validator == nil || validator.Pubkey != pubkey {
-				continue
-			}
-			found = true
-			exitMsg := validator.GetExitMessage()
-			if exitMsg != nil {
-				return ErrSignedExitAlreadyUploaded
-			}
-			validator.SetExitMessage(&signedExit.ExitMessage)
-			break
-		}
-		if !found {
-			return fmt.Errorf("node [%s] doesn't have validator [%s]", node.Address.Hex(), pubkey.Hex())
+		// Set the exit message
+		err = d.setExitMessage(node, pubkey, signedExit.ExitMessage)
+		if err != nil {
+			return err
 		}
-
 	}
 	return nil
 }
@@ -305,7 +546,6 @@ func (d *ConstellationDeployment) HandleSignedExitUpload(node *Node, data []comm
 // Handle a new collection of encrypted signed exits from a node for Constellation
 func (d *ConstellationDeployment) HandleEncryptedSignedExitUpload(node *Node, data []common.EncryptedExitData) error {
 	// Add the signed exits
-	minipools := d.minipools[node.Address]
 	for _, signedExit := range data {
 		pubkey, err := beacon.HexToValidatorPubkey(signedExit.Pubkey)
 		if err != nil {
@@ -338,25 +578,11 @@ func (d *ConstellationDeployment) HandleEncryptedSignedExitUpload(node *Node, da
 			return fmt.Errorf("error parsing decrypted exit message: %w", err)
 		}
 
-		// Get the validator
-		found := false
-		for _, minipoolAddress := range minipools {
-			validator := d.validators[minipoolAddress]
-			if validator == nil || validator.Pubkey != pubkey {
-				continue
-			}
-			found = true
-			exitMsg := validator.GetExitMessage()
-			if exitMsg != nil {
-				return ErrSignedExitAlreadyUploaded
-			}
-			validator.SetExitMessage(&exitMessage)
-			break
-		}
-		if !found {
-			return fmt.Errorf("node [%s] doesn't have validator [%s]", node.Address.Hex(), pubkey.Hex())
+		// Set the exit message
+		err = d.setExitMessage(node, pubkey, exitMessage)
+		if err != nil {
+			return err
 		}
-
 	}
 	return nil
 }
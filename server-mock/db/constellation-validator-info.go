@@ -1,6 +1,9 @@
 package db
 
 import (
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/rocket-pool/node-manager-core/beacon"
 )
@@ -9,6 +12,12 @@ import (
 type ConstellationValidatorInfo struct {
 	Pubkey beacon.ValidatorPubkey
 
+	// The Beacon deposit root at the time the validator was assigned to its minipool
+	BeaconDepositRoot ethcommon.Hash
+
+	// The time the validator was assigned to its minipool
+	RegistrationTime time.Time
+
 	exitMessage *common.ExitMessage
 }
 
@@ -19,18 +28,23 @@ func newConstellationValidatorInfo(pubkey beacon.ValidatorPubkey) *Constellation
 	}
 }
 
-// Clone the StakeWise validator info
+// Clone the Constellation validator info
 func (v *ConstellationValidatorInfo) clone() *ConstellationValidatorInfo {
-	return &ConstellationValidatorInfo{
-		Pubkey: v.Pubkey,
-		exitMessage: &common.ExitMessage{
+	clone := &ConstellationValidatorInfo{
+		Pubkey:            v.Pubkey,
+		BeaconDepositRoot: v.BeaconDepositRoot,
+		RegistrationTime:  v.RegistrationTime,
+	}
+	if v.exitMessage != nil {
+		clone.exitMessage = &common.ExitMessage{
 			Signature: v.exitMessage.Signature,
 			Message: common.ExitMessageDetails{
 				Epoch:          v.exitMessage.Message.Epoch,
 				ValidatorIndex: v.exitMessage.Message.ValidatorIndex,
 			},
-		},
+		}
 	}
+	return clone
 }
 
 // Get the exit message for the validator
@@ -42,3 +56,9 @@ func (v *ConstellationValidatorInfo) GetExitMessage() *common.ExitMessage {
 func (v *ConstellationValidatorInfo) SetExitMessage(exitMessage *common.ExitMessage) {
 	v.exitMessage = exitMessage
 }
+
+// Get the validator's v3 lifecycle status, using its state on the simulated Beacon chain if it has been seen there
+func (v *ConstellationValidatorInfo) GetStatusV3(beaconDB *Database_Beacon) common.ValidatorLifecycleStatus {
+	// Deposits always put the validator on Beacon, so any validator that isn't there yet hasn't been deposited
+	return beaconDB.getLifecycleStatus(v.Pubkey, false)
+}
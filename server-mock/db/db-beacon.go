@@ -0,0 +1,308 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// Default number of seconds in a slot
+	DefaultSecondsPerSlot uint64 = 12
+
+	// Default number of slots in an epoch
+	DefaultSlotsPerEpoch uint64 = 32
+
+	// Default number of epochs between a validator being seen on Beacon and it becoming active
+	DefaultActivationDelayEpochs uint64 = 4
+
+	// Default number of epochs between a validator's exit being processed and it being exited
+	DefaultExitDelayEpochs uint64 = 4
+)
+
+var (
+	// The exit message isn't valid for the validator's current state on the Beacon chain
+	ErrInvalidExitMessage error = errors.New("exit message is not valid for the validator on the Beacon chain")
+)
+
+// Status of a validator on the simulated Beacon chain
+type BeaconValidatorStatus string
+
+const (
+	// The validator has been deposited but isn't active yet
+	BeaconValidatorStatus_Pending BeaconValidatorStatus = "pending"
+
+	// The validator is active and attesting
+	BeaconValidatorStatus_Active BeaconValidatorStatus = "active"
+
+	// The validator has had an exit processed but hasn't reached its exit epoch yet
+	BeaconValidatorStatus_Exiting BeaconValidatorStatus = "exiting"
+
+	// The validator has exited the Beacon chain
+	BeaconValidatorStatus_Exited BeaconValidatorStatus = "exited"
+)
+
+// Info about a validator on the simulated Beacon chain
+type BeaconValidator struct {
+	Pubkey          beacon.ValidatorPubkey
+	Index           uint64
+	Status          BeaconValidatorStatus
+	ActivationEpoch uint64
+	ExitEpoch       uint64
+	Slashed         bool
+}
+
+// Database for a simulated Beacon chain
+type Database_Beacon struct {
+	// The time of the chain's genesis
+	GenesisTime time.Time
+
+	// Number of seconds in a slot
+	SecondsPerSlot uint64
+
+	// Number of slots in an epoch
+	SlotsPerEpoch uint64
+
+	// Number of epochs a new validator waits in the queue before becoming active
+	ActivationDelayEpochs uint64
+
+	// Number of epochs an exiting validator waits before becoming exited
+	ExitDelayEpochs uint64
+
+	// The current slot of the chain
+	currentSlot uint64
+
+	// Validators on the chain, ordered by index
+	validators []*BeaconValidator
+
+	// Validator lookup by pubkey
+	validatorMap map[beacon.ValidatorPubkey]*BeaconValidator
+
+	// Internal fields
+	logger *slog.Logger
+	db     *Database
+}
+
+// Create a new Beacon database
+func newDatabase_Beacon(db *Database, logger *slog.Logger) *Database_Beacon {
+	return &Database_Beacon{
+		GenesisTime:           time.Now().Truncate(time.Second),
+		SecondsPerSlot:        DefaultSecondsPerSlot,
+		SlotsPerEpoch:         DefaultSlotsPerEpoch,
+		ActivationDelayEpochs: DefaultActivationDelayEpochs,
+		ExitDelayEpochs:       DefaultExitDelayEpochs,
+		validators:            []*BeaconValidator{},
+		validatorMap:          map[beacon.ValidatorPubkey]*BeaconValidator{},
+		logger:                logger,
+		db:                    db,
+	}
+}
+
+// Clone the database
+func (d *Database_Beacon) clone(dbClone *Database) *Database_Beacon {
+	clone := newDatabase_Beacon(dbClone, d.logger)
+	clone.GenesisTime = d.GenesisTime
+	clone.SecondsPerSlot = d.SecondsPerSlot
+	clone.SlotsPerEpoch = d.SlotsPerEpoch
+	clone.ActivationDelayEpochs = d.ActivationDelayEpochs
+	clone.ExitDelayEpochs = d.ExitDelayEpochs
+	clone.currentSlot = d.currentSlot
+	for _, validator := range d.validators {
+		validatorClone := *validator
+		clone.validators = append(clone.validators, &validatorClone)
+		clone.validatorMap[validatorClone.Pubkey] = &validatorClone
+	}
+	return clone
+}
+
+// =============
+// === Clock ===
+// =============
+
+// Get the current slot of the chain
+func (d *Database_Beacon) GetCurrentSlot() uint64 {
+	return d.currentSlot
+}
+
+// Get the current epoch of the chain
+func (d *Database_Beacon) GetCurrentEpoch() uint64 {
+	return d.currentSlot / d.SlotsPerEpoch
+}
+
+// Get the wall-clock time of the provided slot
+func (d *Database_Beacon) GetSlotTime(slot uint64) time.Time {
+	return d.GenesisTime.Add(time.Duration(slot*d.SecondsPerSlot) * time.Second)
+}
+
+// Advance the chain by the provided number of slots, processing any validator status transitions along the way
+func (d *Database_Beacon) AdvanceSlots(slots uint64) {
+	d.currentSlot += slots
+	d.processTransitions()
+}
+
+// Advance the chain by the provided number of epochs, processing any validator status transitions along the way
+func (d *Database_Beacon) AdvanceEpochs(epochs uint64) {
+	d.AdvanceSlots(epochs * d.SlotsPerEpoch)
+}
+
+// Move validators whose activation or exit epoch has been reached into their next status
+func (d *Database_Beacon) processTransitions() {
+	epoch := d.GetCurrentEpoch()
+	for _, validator := range d.validators {
+		switch validator.Status {
+		case BeaconValidatorStatus_Pending:
+			if epoch >= validator.ActivationEpoch {
+				validator.Status = BeaconValidatorStatus_Active
+				d.logger.Debug("Validator activated", "pubkey", validator.Pubkey.Hex(), "index", validator.Index, "epoch", epoch)
+			}
+		case BeaconValidatorStatus_Exiting:
+			if epoch >= validator.ExitEpoch {
+				validator.Status = BeaconValidatorStatus_Exited
+				d.logger.Debug("Validator exited", "pubkey", validator.Pubkey.Hex(), "index", validator.Index, "epoch", epoch)
+			}
+		}
+	}
+}
+
+// ==================
+// === Validators ===
+// ==================
+
+// Add a validator to the chain as pending. If it's already on the chain, the existing validator is returned.
+func (d *Database_Beacon) AddValidator(pubkey beacon.ValidatorPubkey) *BeaconValidator {
+	if validator, exists := d.validatorMap[pubkey]; exists {
+		return validator
+	}
+
+	validator := &BeaconValidator{
+		Pubkey:          pubkey,
+		Index:           uint64(len(d.validators)),
+		Status:          BeaconValidatorStatus_Pending,
+		ActivationEpoch: d.GetCurrentEpoch() + d.ActivationDelayEpochs,
+	}
+	d.validators = append(d.validators, validator)
+	d.validatorMap[pubkey] = validator
+	d.processTransitions()
+	return validator
+}
+
+// Get a validator by its pubkey. Returns nil if it isn't on the chain.
+func (d *Database_Beacon) GetValidator(pubkey beacon.ValidatorPubkey) *BeaconValidator {
+	return d.validatorMap[pubkey]
+}
+
+// Get a validator by its index. Returns nil if it isn't on the chain.
+func (d *Database_Beacon) GetValidatorByIndex(index uint64) *BeaconValidator {
+	if index >= uint64(len(d.validators)) {
+		return nil
+	}
+	return d.validators[index]
+}
+
+// Get all of the validators on the chain, ordered by index
+func (d *Database_Beacon) GetValidators() []*BeaconValidator {
+	return d.validators
+}
+
+// Process a voluntary exit for the validator, moving it into the exiting state
+func (d *Database_Beacon) ExitValidator(pubkey beacon.ValidatorPubkey) error {
+	validator := d.validatorMap[pubkey]
+	if validator == nil {
+		return fmt.Errorf("validator [%s] is not on the Beacon chain", pubkey.Hex())
+	}
+	if validator.Status != BeaconValidatorStatus_Active {
+		return fmt.Errorf("validator [%s] can't exit while %s", pubkey.Hex(), validator.Status)
+	}
+
+	validator.Status = BeaconValidatorStatus_Exiting
+	validator.ExitEpoch = d.GetCurrentEpoch() + d.ExitDelayEpochs
+	d.processTransitions()
+	return nil
+}
+
+// Slash the validator, moving it into the exiting state if it hasn't already exited
+func (d *Database_Beacon) SlashValidator(pubkey beacon.ValidatorPubkey) error {
+	validator := d.validatorMap[pubkey]
+	if validator == nil {
+		return fmt.Errorf("validator [%s] is not on the Beacon chain", pubkey.Hex())
+	}
+	if validator.Slashed {
+		return fmt.Errorf("validator [%s] has already been slashed", pubkey.Hex())
+	}
+	if validator.Status == BeaconValidatorStatus_Pending || validator.Status == BeaconValidatorStatus_Exited {
+		return fmt.Errorf("validator [%s] can't be slashed while %s", pubkey.Hex(), validator.Status)
+	}
+
+	validator.Slashed = true
+	if validator.Status == BeaconValidatorStatus_Active {
+		validator.Status = BeaconValidatorStatus_Exiting
+		validator.ExitEpoch = d.GetCurrentEpoch() + d.ExitDelayEpochs
+	}
+	d.processTransitions()
+	return nil
+}
+
+// Get the v3 lifecycle status of a validator from its state on the chain.
+// Validators that haven't been seen on Beacon are reported as deposited or registered, depending on whether their deposit has been made.
+func (d *Database_Beacon) getLifecycleStatus(pubkey beacon.ValidatorPubkey, deposited bool) common.ValidatorLifecycleStatus {
+	validator := d.validatorMap[pubkey]
+	if validator == nil {
+		if deposited {
+			return common.ValidatorLifecycleStatus_Deposited
+		}
+		return common.ValidatorLifecycleStatus_Registered
+	}
+	if validator.Slashed {
+		return common.ValidatorLifecycleStatus_Slashed
+	}
+	switch validator.Status {
+	case BeaconValidatorStatus_Active:
+		return common.ValidatorLifecycleStatus_Active
+	case BeaconValidatorStatus_Exiting:
+		return common.ValidatorLifecycleStatus_Exiting
+	case BeaconValidatorStatus_Exited:
+		return common.ValidatorLifecycleStatus_Exited
+	default:
+		return common.ValidatorLifecycleStatus_Deposited
+	}
+}
+
+// Check if the validator is pending or active on the chain (i.e., it has been seen on Beacon but hasn't exited)
+func (d *Database_Beacon) IsValidatorLive(pubkey beacon.ValidatorPubkey) bool {
+	validator := d.validatorMap[pubkey]
+	if validator == nil {
+		return false
+	}
+	return validator.Status != BeaconValidatorStatus_Exited
+}
+
+// Check that an exit message is valid for the validator's state on the chain.
+// Validators that haven't been seen on Beacon yet can't be checked, so their exit messages are accepted as-is.
+func (d *Database_Beacon) ValidateExitMessage(pubkey beacon.ValidatorPubkey, exitMessage common.ExitMessage) error {
+	validator := d.validatorMap[pubkey]
+	if validator == nil {
+		return nil
+	}
+
+	index, err := strconv.ParseUint(exitMessage.Message.ValidatorIndex, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid validator index [%s]", ErrInvalidExitMessage, exitMessage.Message.ValidatorIndex)
+	}
+	if index != validator.Index {
+		return fmt.Errorf("%w: validator index %d doesn't match index %d for validator [%s]", ErrInvalidExitMessage, index, validator.Index, pubkey.Hex())
+	}
+	epoch, err := strconv.ParseUint(exitMessage.Message.Epoch, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid epoch [%s]", ErrInvalidExitMessage, exitMessage.Message.Epoch)
+	}
+	currentEpoch := d.GetCurrentEpoch()
+	if epoch > currentEpoch {
+		return fmt.Errorf("%w: epoch %d is after the current epoch %d", ErrInvalidExitMessage, epoch, currentEpoch)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"log/slog"
 	"math/big"
 
@@ -50,3 +51,12 @@ func (d *Database_Constellation) GetDeployment(deploymentID string) *Constellati
 func (d *Database_Constellation) GetDeployments() map[string]*ConstellationDeployment {
 	return d.deployments
 }
+
+// Removes a deployment and all of its whitelist, minipool, and validator info
+func (d *Database_Constellation) RemoveDeployment(id string) error {
+	if _, exists := d.deployments[id]; !exists {
+		return fmt.Errorf("Constellation deployment [%s] not found", id)
+	}
+	delete(d.deployments, id)
+	return nil
+}
@@ -74,6 +74,25 @@ func (d *Database_Core) GetUsers() []*User {
 	return d.users
 }
 
+// Removes a user, along with its nodes, their sessions, and its Constellation whitelist entries.
+// Validators the user's nodes have uploaded are kept.
+func (d *Database_Core) RemoveUser(email string) error {
+	for i, user := range d.users {
+		if user.Email != email {
+			continue
+		}
+		for address := range user.nodes {
+			d.removeSessionsForNode(address)
+		}
+		for _, deployment := range d.db.Constellation.deployments {
+			delete(deployment.whitelistedNodeMap, email)
+		}
+		d.users = append(d.users[:i], d.users[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("user with email [%s] not found", email)
+}
+
 // ============
 // === Core ===
 // ============
@@ -121,6 +140,25 @@ func (d *Database_Core) GetSessions() []*Session {
 	return d.sessions
 }
 
+// Removes all of the sessions, so every node has to log in again. Returns the number of sessions that were removed.
+func (d *Database_Core) ExpireSessions() int {
+	count := len(d.sessions)
+	d.sessions = []*Session{}
+	return count
+}
+
+// Removes the sessions that are logged in as the node
+func (d *Database_Core) removeSessionsForNode(nodeAddress ethcommon.Address) {
+	sessions := []*Session{}
+	for _, session := range d.sessions {
+		if session.isLoggedIn && session.NodeAddress == nodeAddress {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	d.sessions = sessions
+}
+
 // Attempts to log an existing session in with the provided node address and nonce
 func (d *Database_Core) Login(nodeAddress ethcommon.Address, nonce string, signature []byte) error {
 	return d.loginImpl(nodeAddress, nonce, signature, false)
@@ -1,17 +1,54 @@
 package db
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"log/slog"
+	"math/big"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
 )
 
-// Database for Ethereum chain mock info
-// TEMPORARY until Hardhat support is added
+const (
+	// Depth of the Beacon deposit contract's Merkle tree
+	DepositContractTreeDepth int = 32
+
+	// Default chain ID reported by the mock's Execution client
+	DefaultEthChainID uint64 = 31337
+)
+
+var (
+	// Roots of empty subtrees at each height of the deposit contract's Merkle tree
+	depositZeroHashes [DepositContractTreeDepth]ethcommon.Hash
+)
+
+func init() {
+	for height := 0; height < DepositContractTreeDepth-1; height++ {
+		depositZeroHashes[height+1] = sha256.Sum256(append(depositZeroHashes[height].Bytes(), depositZeroHashes[height].Bytes()...))
+	}
+}
+
+// Database for Ethereum chain mock info, modeling the Beacon deposit contract's incremental Merkle tree
 type Database_Ethereum struct {
+	// The chain ID reported by the mock's Execution client
+	ChainID *big.Int
+
+	// The current block number, incremented whenever a deposit is made
+	blockNumber uint64
+
+	// Number of deposits made to the Beacon deposit contract
+	depositCount uint64
+
+	// Left-side branch of the deposit contract's incremental Merkle tree
+	branch [DepositContractTreeDepth]ethcommon.Hash
+
 	// The current deposit root for the Beacon deposit contract
 	depositRoot ethcommon.Hash
 
+	// Deposits made to the contract, in order
+	deposits []beacon.ExtendedDepositData
+
 	// Internal fields
 	logger *slog.Logger
 	db     *Database
@@ -19,26 +56,98 @@ type Database_Ethereum struct {
 
 // Create a new Ethereum database
 func newDatabase_Ethereum(db *Database, logger *slog.Logger) *Database_Ethereum {
-	return &Database_Ethereum{
-		depositRoot: ethcommon.Hash{},
-		logger:      logger,
-		db:          db,
+	d := &Database_Ethereum{
+		ChainID:  new(big.Int).SetUint64(DefaultEthChainID),
+		deposits: []beacon.ExtendedDepositData{},
+		logger:   logger,
+		db:       db,
 	}
+	d.depositRoot = d.calculateDepositRoot()
+	return d
 }
 
 // Clone the database
 func (d *Database_Ethereum) clone(dbClone *Database) *Database_Ethereum {
 	clone := newDatabase_Ethereum(dbClone, d.logger)
+	clone.ChainID = new(big.Int).Set(d.ChainID)
+	clone.blockNumber = d.blockNumber
+	clone.depositCount = d.depositCount
+	clone.branch = d.branch
 	clone.depositRoot = d.depositRoot
+	clone.deposits = append(clone.deposits, d.deposits...)
 	return clone
 }
 
-// Gets a deployment by its ID. If there isn't one, returns nil
+// Get the Beacon deposit contract's current deposit root
 func (d *Database_Ethereum) GetDepositRoot() ethcommon.Hash {
 	return d.depositRoot
 }
 
-// Sets the Beacon deposit contract's deposit root
+// Override the Beacon deposit contract's deposit root and mine a new block, so the new root is served by the Execution
+// client. The override lasts until the next deposit, which recalculates the root from the contract's Merkle tree.
 func (d *Database_Ethereum) SetDepositRoot(root ethcommon.Hash) {
 	d.depositRoot = root
+	d.blockNumber++
+}
+
+// Get the number of deposits made to the Beacon deposit contract
+func (d *Database_Ethereum) GetDepositCount() uint64 {
+	return d.depositCount
+}
+
+// Get the current block number
+func (d *Database_Ethereum) GetBlockNumber() uint64 {
+	return d.blockNumber
+}
+
+// Get the deposits made to the Beacon deposit contract, in order
+func (d *Database_Ethereum) GetDeposits() []beacon.ExtendedDepositData {
+	return d.deposits
+}
+
+// Make a deposit to the Beacon deposit contract. This appends the deposit data root as a new leaf, updates the deposit root,
+// mines a new block, and puts the validator on the simulated Beacon chain.
+func (d *Database_Ethereum) AddDeposit(depositData beacon.ExtendedDepositData) ethcommon.Hash {
+	// Insert the leaf
+	node := ethcommon.BytesToHash(depositData.DepositDataRoot)
+	d.depositCount++
+	size := d.depositCount
+	for height := 0; height < DepositContractTreeDepth; height++ {
+		if size&1 == 1 {
+			d.branch[height] = node
+			break
+		}
+		node = sha256.Sum256(append(d.branch[height].Bytes(), node.Bytes()...))
+		size /= 2
+	}
+	d.deposits = append(d.deposits, depositData)
+	d.depositRoot = d.calculateDepositRoot()
+	d.blockNumber++
+
+	pubkey := beacon.ValidatorPubkey(depositData.PublicKey)
+	d.db.Beacon.AddValidator(pubkey)
+	d.logger.Debug("Deposit made",
+		"pubkey", pubkey.Hex(),
+		"depositCount", d.depositCount,
+		"depositRoot", d.depositRoot.Hex(),
+	)
+	return d.depositRoot
+}
+
+// Calculate the deposit root from the tree's branch and deposit count, matching get_deposit_root() in the deposit contract
+func (d *Database_Ethereum) calculateDepositRoot() ethcommon.Hash {
+	node := ethcommon.Hash{}
+	size := d.depositCount
+	for height := 0; height < DepositContractTreeDepth; height++ {
+		if size&1 == 1 {
+			node = sha256.Sum256(append(d.branch[height].Bytes(), node.Bytes()...))
+		} else {
+			node = sha256.Sum256(append(node.Bytes(), depositZeroHashes[height].Bytes()...))
+		}
+		size /= 2
+	}
+
+	countBytes := [32]byte{}
+	binary.LittleEndian.PutUint64(countBytes[:8], d.depositCount)
+	return sha256.Sum256(append(node.Bytes(), countBytes[:]...))
 }
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"log/slog"
 	"math/big"
 )
@@ -48,3 +49,12 @@ func (d *Database_StakeWise) GetDeployment(id string) *StakeWiseDeployment {
 func (d *Database_StakeWise) GetDeployments() map[string]*StakeWiseDeployment {
 	return d.Deployments
 }
+
+// Removes a deployment and all of its vaults
+func (d *Database_StakeWise) RemoveDeployment(id string) error {
+	if _, exists := d.Deployments[id]; !exists {
+		return fmt.Errorf("StakeWise deployment [%s] not found", id)
+	}
+	delete(d.Deployments, id)
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 type Database struct {
 	Core          *Database_Core
 	Eth           *Database_Ethereum
+	Beacon        *Database_Beacon
 	Constellation *Database_Constellation
 	StakeWise     *Database_StakeWise
 
@@ -27,6 +28,7 @@ func NewDatabase(logger *slog.Logger) *Database {
 	}
 	db.Core = newDatabase_Core(db, logger)
 	db.Eth = newDatabase_Ethereum(db, logger)
+	db.Beacon = newDatabase_Beacon(db, logger)
 	db.Constellation = newDatabase_Constellation(db, logger)
 	db.StakeWise = newDatabase_StakeWise(db, logger)
 	return db
@@ -39,6 +41,7 @@ func (d *Database) Clone() *Database {
 	}
 	dbClone.Core = d.Core.clone(dbClone)
 	dbClone.Eth = d.Eth.clone(dbClone)
+	dbClone.Beacon = d.Beacon.clone(dbClone)
 	dbClone.Constellation = d.Constellation.clone(dbClone)
 	dbClone.StakeWise = d.StakeWise.clone(dbClone)
 	dbClone.secretEncryptionIdentity = d.secretEncryptionIdentity
@@ -0,0 +1,402 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// Version of the database export format
+	databaseExportVersion int = 1
+)
+
+var (
+	// The database can only be exported if every Constellation deployment keeps its chain state in memory
+	ErrCannotExportChainBackend error = errors.New("Constellation deployments that read their state from a chain can't be exported")
+)
+
+// Serialized form of the database, including the internal fields needed to restore it exactly
+type databaseExport struct {
+	Version                  int                         `json:"version"`
+	SecretEncryptionIdentity string                      `json:"secretEncryptionIdentity,omitempty"`
+	Users                    []userExport                `json:"users"`
+	Sessions                 []sessionExport             `json:"sessions"`
+	Eth                      ethExport                   `json:"eth"`
+	Beacon                   beaconExport                `json:"beacon"`
+	Constellation            []constellationExport       `json:"constellation"`
+	StakeWise                []stakeWiseDeploymentExport `json:"stakeWise"`
+}
+
+type userExport struct {
+	Email              string           `json:"email"`
+	Nodes              []nodeExport     `json:"nodes"`
+	RevokedPermissions []api.Permission `json:"revokedPermissions"`
+}
+
+type nodeExport struct {
+	Address    ethcommon.Address `json:"address"`
+	Registered bool              `json:"registered"`
+}
+
+type sessionExport struct {
+	Nonce       string            `json:"nonce"`
+	Token       string            `json:"token"`
+	NodeAddress ethcommon.Address `json:"nodeAddress"`
+	LoggedIn    bool              `json:"loggedIn"`
+}
+
+type ethExport struct {
+	ChainID      *big.Int                     `json:"chainId"`
+	BlockNumber  uint64                       `json:"blockNumber"`
+	DepositCount uint64                       `json:"depositCount"`
+	Branch       []ethcommon.Hash             `json:"branch"`
+	DepositRoot  ethcommon.Hash               `json:"depositRoot"`
+	Deposits     []beacon.ExtendedDepositData `json:"deposits"`
+}
+
+type beaconExport struct {
+	GenesisTime           time.Time         `json:"genesisTime"`
+	SecondsPerSlot        uint64            `json:"secondsPerSlot"`
+	SlotsPerEpoch         uint64            `json:"slotsPerEpoch"`
+	ActivationDelayEpochs uint64            `json:"activationDelayEpochs"`
+	ExitDelayEpochs       uint64            `json:"exitDelayEpochs"`
+	CurrentSlot           uint64            `json:"currentSlot"`
+	Validators            []BeaconValidator `json:"validators"`
+}
+
+type constellationExport struct {
+	ID                 string                                       `json:"id"`
+	ChainID            *big.Int                                     `json:"chainId"`
+	WhitelistAddress   ethcommon.Address                            `json:"whitelistAddress"`
+	SuperNodeAddress   ethcommon.Address                            `json:"superNodeAddress"`
+	AdminPrivateKey    hexutil.Bytes                                `json:"adminPrivateKey,omitempty"`
+	WhitelistedNodeMap map[string]ethcommon.Address                 `json:"whitelistedNodeMap"`
+	Minipools          map[ethcommon.Address][]ethcommon.Address    `json:"minipools"`
+	Validators         []constellationValidatorExport               `json:"validators"`
+	WhitelistNonces    map[ethcommon.Address]uint64                 `json:"whitelistNonces"`
+	SuperNodeNonces    map[ethcommon.Address]uint64                 `json:"superNodeNonces"`
+	MinipoolPubkeys    map[ethcommon.Address]beacon.ValidatorPubkey `json:"minipoolPubkeys"`
+
+	// Minipool rules; the limit is optional so exports from before it existed keep the default
+	MinipoolLimit       *int                      `json:"minipoolLimit,omitempty"`
+	RequireExitMessages bool                      `json:"requireExitMessages"`
+	UserMinipoolLimits  map[string]int            `json:"userMinipoolLimits"`
+	NodeMinipoolLimits  map[ethcommon.Address]int `json:"nodeMinipoolLimits"`
+}
+
+type constellationValidatorExport struct {
+	Minipool          ethcommon.Address      `json:"minipool"`
+	Pubkey            beacon.ValidatorPubkey `json:"pubkey"`
+	BeaconDepositRoot ethcommon.Hash         `json:"beaconDepositRoot"`
+	RegistrationTime  time.Time              `json:"registrationTime"`
+	ExitMessage       *common.ExitMessage    `json:"exitMessage,omitempty"`
+}
+
+type stakeWiseDeploymentExport struct {
+	ID      string                 `json:"id"`
+	ChainID *big.Int               `json:"chainId"`
+	Vaults  []stakeWiseVaultExport `json:"vaults"`
+}
+
+type stakeWiseVaultExport struct {
+	Name                      string                                          `json:"name"`
+	Address                   ethcommon.Address                               `json:"address"`
+	UploadedData              []beacon.ValidatorPubkey                        `json:"uploadedData"`
+	LatestDepositDataSetIndex int                                             `json:"latestDepositDataSetIndex"`
+	LatestDepositDataSet      []beacon.ExtendedDepositData                    `json:"latestDepositDataSet"`
+	MaxValidatorsPerUser      int                                             `json:"maxValidatorsPerUser"`
+	Balance                   *big.Int                                        `json:"balance"`
+	Validators                map[ethcommon.Address][]*StakeWiseValidatorInfo `json:"validators"`
+}
+
+// Serialize the database, including all of its internal state, so it can be restored later with DeserializeDatabase
+func (d *Database) Serialize() ([]byte, error) {
+	export := databaseExport{
+		Version:       databaseExportVersion,
+		Users:         []userExport{},
+		Sessions:      []sessionExport{},
+		Constellation: []constellationExport{},
+		StakeWise:     []stakeWiseDeploymentExport{},
+	}
+	if d.secretEncryptionIdentity != nil {
+		export.SecretEncryptionIdentity = d.secretEncryptionIdentity.String()
+	}
+
+	// Core
+	for _, user := range d.Core.users {
+		userExport := userExport{
+			Email:              user.Email,
+			Nodes:              []nodeExport{},
+			RevokedPermissions: user.GetRevokedPermissions(),
+		}
+		for _, node := range sortedNodes(user) {
+			userExport.Nodes = append(userExport.Nodes, nodeExport{
+				Address:    node.Address,
+				Registered: node.isRegistered,
+			})
+		}
+		export.Users = append(export.Users, userExport)
+	}
+	for _, session := range d.Core.sessions {
+		export.Sessions = append(export.Sessions, sessionExport{
+			Nonce:       session.Nonce,
+			Token:       session.Token,
+			NodeAddress: session.NodeAddress,
+			LoggedIn:    session.isLoggedIn,
+		})
+	}
+
+	// Eth
+	export.Eth = ethExport{
+		ChainID:      d.Eth.ChainID,
+		BlockNumber:  d.Eth.blockNumber,
+		DepositCount: d.Eth.depositCount,
+		Branch:       d.Eth.branch[:],
+		DepositRoot:  d.Eth.depositRoot,
+		Deposits:     d.Eth.deposits,
+	}
+
+	// Beacon
+	export.Beacon = beaconExport{
+		GenesisTime:           d.Beacon.GenesisTime,
+		SecondsPerSlot:        d.Beacon.SecondsPerSlot,
+		SlotsPerEpoch:         d.Beacon.SlotsPerEpoch,
+		ActivationDelayEpochs: d.Beacon.ActivationDelayEpochs,
+		ExitDelayEpochs:       d.Beacon.ExitDelayEpochs,
+		CurrentSlot:           d.Beacon.currentSlot,
+		Validators:            []BeaconValidator{},
+	}
+	for _, validator := range d.Beacon.validators {
+		export.Beacon.Validators = append(export.Beacon.Validators, *validator)
+	}
+
+	// Constellation
+	for _, id := range sortedKeys(d.Constellation.deployments) {
+		deployment := d.Constellation.deployments[id]
+		backend, isMemory := deployment.chainBackend.(*MemoryConstellationBackend)
+		if !isMemory {
+			return nil, fmt.Errorf("error exporting Constellation deployment [%s]: %w", id, ErrCannotExportChainBackend)
+		}
+		deploymentExport := constellationExport{
+			ID:                 deployment.ID,
+			ChainID:            deployment.ChainID,
+			WhitelistAddress:   deployment.WhitelistAddress,
+			SuperNodeAddress:   deployment.SuperNodeAddress,
+			WhitelistedNodeMap: deployment.whitelistedNodeMap,
+			Minipools:          deployment.minipools,
+			Validators:         []constellationValidatorExport{},
+			WhitelistNonces:    backend.whitelistNonces,
+			SuperNodeNonces:    backend.superNodeNonces,
+			MinipoolPubkeys:    backend.minipoolPubkeys,
+
+			MinipoolLimit:       &deployment.MinipoolLimit,
+			RequireExitMessages: deployment.RequireExitMessages,
+			UserMinipoolLimits:  deployment.userMinipoolLimits,
+			NodeMinipoolLimits:  deployment.nodeMinipoolLimits,
+		}
+		if deployment.adminPrivateKey != nil {
+			deploymentExport.AdminPrivateKey = crypto.FromECDSA(deployment.adminPrivateKey)
+		}
+		for _, minipool := range sortedAddresses(deployment.validators) {
+			validator := deployment.validators[minipool]
+			deploymentExport.Validators = append(deploymentExport.Validators, constellationValidatorExport{
+				Minipool:          minipool,
+				Pubkey:            validator.Pubkey,
+				BeaconDepositRoot: validator.BeaconDepositRoot,
+				RegistrationTime:  validator.RegistrationTime,
+				ExitMessage:       validator.exitMessage,
+			})
+		}
+		export.Constellation = append(export.Constellation, deploymentExport)
+	}
+
+	// StakeWise
+	for _, id := range sortedKeys(d.StakeWise.Deployments) {
+		deployment := d.StakeWise.Deployments[id]
+		deploymentExport := stakeWiseDeploymentExport{
+			ID:      deployment.ID,
+			ChainID: deployment.ChainID,
+			Vaults:  []stakeWiseVaultExport{},
+		}
+		for _, address := range sortedAddresses(deployment.Vaults) {
+			vault := deployment.Vaults[address]
+			vaultExport := stakeWiseVaultExport{
+				Name:                      vault.Name,
+				Address:                   vault.Address,
+				UploadedData:              []beacon.ValidatorPubkey{},
+				LatestDepositDataSetIndex: vault.LatestDepositDataSetIndex,
+				LatestDepositDataSet:      vault.LatestDepositDataSet,
+				MaxValidatorsPerUser:      vault.MaxValidatorsPerUser,
+				Balance:                   vault.Balance,
+				Validators:                map[ethcommon.Address][]*StakeWiseValidatorInfo{},
+			}
+			for _, pubkey := range sortedPubkeys(vault.UploadedData) {
+				if vault.UploadedData[pubkey] {
+					vaultExport.UploadedData = append(vaultExport.UploadedData, pubkey)
+				}
+			}
+			for nodeAddress, validators := range vault.Validators {
+				for _, pubkey := range sortedPubkeys(validators) {
+					vaultExport.Validators[nodeAddress] = append(vaultExport.Validators[nodeAddress], validators[pubkey])
+				}
+			}
+			deploymentExport.Vaults = append(deploymentExport.Vaults, vaultExport)
+		}
+		export.StakeWise = append(export.StakeWise, deploymentExport)
+	}
+
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// Restore a database that was serialized with Serialize
+func DeserializeDatabase(logger *slog.Logger, data []byte) (*Database, error) {
+	var export databaseExport
+	err := json.Unmarshal(data, &export)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing database: %w", err)
+	}
+	if export.Version != databaseExportVersion {
+		return nil, fmt.Errorf("unsupported database export version %d (expected %d)", export.Version, databaseExportVersion)
+	}
+
+	d := NewDatabase(logger)
+	if export.SecretEncryptionIdentity != "" {
+		d.secretEncryptionIdentity, err = age.ParseX25519Identity(export.SecretEncryptionIdentity)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing secret encryption identity: %w", err)
+		}
+	}
+
+	// Core
+	for _, userExport := range export.Users {
+		user := newUser(d, userExport.Email)
+		for _, nodeExport := range userExport.Nodes {
+			node := newNode(user, nodeExport.Address)
+			node.isRegistered = nodeExport.Registered
+			user.nodes[node.Address] = node
+		}
+		for _, permission := range userExport.RevokedPermissions {
+			err = user.SetPermission(permission, false)
+			if err != nil {
+				return nil, fmt.Errorf("error loading permissions for user [%s]: %w", user.Email, err)
+			}
+		}
+		d.Core.users = append(d.Core.users, user)
+	}
+	for _, sessionExport := range export.Sessions {
+		d.Core.sessions = append(d.Core.sessions, &Session{
+			Nonce:       sessionExport.Nonce,
+			Token:       sessionExport.Token,
+			NodeAddress: sessionExport.NodeAddress,
+			isLoggedIn:  sessionExport.LoggedIn,
+		})
+	}
+
+	// Eth
+	if len(export.Eth.Branch) != DepositContractTreeDepth {
+		return nil, fmt.Errorf("deposit contract branch has %d nodes (expected %d)", len(export.Eth.Branch), DepositContractTreeDepth)
+	}
+	if export.Eth.ChainID != nil {
+		d.Eth.ChainID = export.Eth.ChainID
+	}
+	d.Eth.blockNumber = export.Eth.BlockNumber
+	d.Eth.depositCount = export.Eth.DepositCount
+	copy(d.Eth.branch[:], export.Eth.Branch)
+	d.Eth.depositRoot = export.Eth.DepositRoot
+	d.Eth.deposits = append(d.Eth.deposits, export.Eth.Deposits...)
+
+	// Beacon
+	d.Beacon.GenesisTime = export.Beacon.GenesisTime
+	d.Beacon.SecondsPerSlot = export.Beacon.SecondsPerSlot
+	d.Beacon.SlotsPerEpoch = export.Beacon.SlotsPerEpoch
+	d.Beacon.ActivationDelayEpochs = export.Beacon.ActivationDelayEpochs
+	d.Beacon.ExitDelayEpochs = export.Beacon.ExitDelayEpochs
+	d.Beacon.currentSlot = export.Beacon.CurrentSlot
+	for _, validatorExport := range export.Beacon.Validators {
+		validator := validatorExport
+		d.Beacon.validators = append(d.Beacon.validators, &validator)
+		d.Beacon.validatorMap[validator.Pubkey] = &validator
+	}
+
+	// Constellation
+	for _, deploymentExport := range export.Constellation {
+		deployment := d.Constellation.AddDeployment(deploymentExport.ID, deploymentExport.ChainID, deploymentExport.WhitelistAddress, deploymentExport.SuperNodeAddress)
+		if len(deploymentExport.AdminPrivateKey) > 0 {
+			deployment.adminPrivateKey, err = crypto.ToECDSA(deploymentExport.AdminPrivateKey)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing admin private key for Constellation deployment [%s]: %w", deploymentExport.ID, err)
+			}
+		}
+		for email, address := range deploymentExport.WhitelistedNodeMap {
+			deployment.whitelistedNodeMap[email] = address
+		}
+		if deploymentExport.MinipoolLimit != nil {
+			deployment.MinipoolLimit = *deploymentExport.MinipoolLimit
+		}
+		deployment.RequireExitMessages = deploymentExport.RequireExitMessages
+		for email, limit := range deploymentExport.UserMinipoolLimits {
+			deployment.userMinipoolLimits[email] = limit
+		}
+		for address, limit := range deploymentExport.NodeMinipoolLimits {
+			deployment.nodeMinipoolLimits[address] = limit
+		}
+		for nodeAddress, minipools := range deploymentExport.Minipools {
+			deployment.minipools[nodeAddress] = minipools
+		}
+		for _, validatorExport := range deploymentExport.Validators {
+			validator := newConstellationValidatorInfo(validatorExport.Pubkey)
+			validator.BeaconDepositRoot = validatorExport.BeaconDepositRoot
+			validator.RegistrationTime = validatorExport.RegistrationTime
+			validator.exitMessage = validatorExport.ExitMessage
+			deployment.validators[validatorExport.Minipool] = validator
+		}
+		backend := NewMemoryConstellationBackend()
+		for address, nonce := range deploymentExport.WhitelistNonces {
+			backend.whitelistNonces[address] = nonce
+		}
+		for address, nonce := range deploymentExport.SuperNodeNonces {
+			backend.superNodeNonces[address] = nonce
+		}
+		for minipool, pubkey := range deploymentExport.MinipoolPubkeys {
+			backend.minipoolPubkeys[minipool] = pubkey
+		}
+		deployment.chainBackend = backend
+	}
+
+	// StakeWise
+	for _, deploymentExport := range export.StakeWise {
+		deployment := d.StakeWise.AddDeployment(deploymentExport.ID, deploymentExport.ChainID)
+		for _, vaultExport := range deploymentExport.Vaults {
+			vault := deployment.AddVault(vaultExport.Name, vaultExport.Address)
+			vault.LatestDepositDataSetIndex = vaultExport.LatestDepositDataSetIndex
+			vault.LatestDepositDataSet = append(vault.LatestDepositDataSet, vaultExport.LatestDepositDataSet...)
+			vault.MaxValidatorsPerUser = vaultExport.MaxValidatorsPerUser
+			if vaultExport.Balance != nil {
+				vault.Balance = vaultExport.Balance
+			}
+			for _, pubkey := range vaultExport.UploadedData {
+				vault.UploadedData[pubkey] = true
+			}
+			for nodeAddress, validators := range vaultExport.Validators {
+				validatorMap := map[beacon.ValidatorPubkey]*StakeWiseValidatorInfo{}
+				for _, validator := range validators {
+					validatorMap[validator.Pubkey] = validator
+				}
+				vault.Validators[nodeAddress] = validatorMap
+			}
+		}
+	}
+	return d, nil
+}
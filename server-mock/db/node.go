@@ -88,3 +88,13 @@ func (n *Node) registerImpl(signature []byte, skipVerification bool, signatureFo
 	n.isRegistered = true
 	return nil
 }
+
+// Deregister the node from the NodeSet server, logging out its sessions. It stays whitelisted on the user account, so it can register again.
+func (n *Node) Deregister() error {
+	if !n.isRegistered {
+		return ErrUnregisteredNode
+	}
+	n.isRegistered = false
+	n.user.db.Core.removeSessionsForNode(n.Address)
+	return nil
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"math/big"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -49,6 +50,15 @@ func (d *StakeWiseDeployment) AddVault(name string, address ethcommon.Address) *
 	return vault
 }
 
+// Removes a StakeWise vault and all of its validators from the deployment
+func (d *StakeWiseDeployment) RemoveVault(address ethcommon.Address) error {
+	if _, exists := d.Vaults[address]; !exists {
+		return fmt.Errorf("StakeWise vault [%s] not found in deployment [%s]", address.Hex(), d.ID)
+	}
+	delete(d.Vaults, address)
+	return nil
+}
+
 // Get a StakeWise vault by its address. If there isn't one, returns nil
 func (d *StakeWiseDeployment) GetVault(address ethcommon.Address) *StakeWiseVault {
 	return d.Vaults[address]
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"time"
+
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	apiv0 "github.com/nodeset-org/nodeset-client-go/api-v0"
 	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
@@ -21,12 +23,14 @@ type StakeWiseValidatorInfo struct {
 	BeaconDepositRoot ethcommon.Hash
 
 	// True if there was a deposit event for this validator on the Execution layer
-	// TEMP until Hardhat is added
 	HasDepositEvent bool
 
-	// True if this validator is active in the Beacon chain
-	// TEMP until OSHA is added
+	// True if this validator is active on the Beacon chain. This overrides the simulated Beacon chain for validators that
+	// haven't been seen there.
 	IsActiveOnBeacon bool
+
+	// The time the validator was registered with NodeSet (used in v3)
+	RegistrationTime time.Time
 }
 
 // Create a new StakeWise validator info
@@ -47,6 +51,9 @@ func (v *StakeWiseValidatorInfo) clone() *StakeWiseValidatorInfo {
 		DepositDataUsed:     v.DepositDataUsed,
 		MarkedActive:        v.MarkedActive,
 		BeaconDepositRoot:   v.BeaconDepositRoot,
+		HasDepositEvent:     v.HasDepositEvent,
+		IsActiveOnBeacon:    v.IsActiveOnBeacon,
+		RegistrationTime:    v.RegistrationTime,
 	}
 }
 
@@ -60,6 +67,13 @@ func (v *StakeWiseValidatorInfo) MarkActive() {
 	v.MarkedActive = true
 }
 
+// Register the validator with NodeSet using the provided deposit root, marking it as active
+func (v *StakeWiseValidatorInfo) Register(beaconDepositRoot ethcommon.Hash, registrationTime time.Time) {
+	v.MarkActive()
+	v.BeaconDepositRoot = beaconDepositRoot
+	v.RegistrationTime = registrationTime
+}
+
 // Set the signed exit message for the validator
 func (v *StakeWiseValidatorInfo) SetExitMessage(exitMessage common.ExitMessage) {
 	// Normally this is where validation would occur
@@ -67,8 +81,15 @@ func (v *StakeWiseValidatorInfo) SetExitMessage(exitMessage common.ExitMessage)
 	v.ExitMessageUploaded = true
 }
 
+// Remove the signed exit message for the validator
+func (v *StakeWiseValidatorInfo) ClearExitMessage() {
+	v.SignedExit = common.ExitMessage{}
+	v.ExitMessageUploaded = false
+}
+
+// Get the validator's v0 status from its NodeSet state
 func (v *StakeWiseValidatorInfo) GetStatusV0() apiv0.StakeWiseStatus {
-	if v.MarkedActive {
+	if v.MarkedActive || v.IsActiveOnBeacon {
 		return apiv0.StakeWiseStatus_Registered
 	}
 	if v.DepositDataUsed {
@@ -77,8 +98,24 @@ func (v *StakeWiseValidatorInfo) GetStatusV0() apiv0.StakeWiseStatus {
 	return apiv0.StakeWiseStatus_Pending
 }
 
+// Get the validator's v0 status, using its state on the simulated Beacon chain if it has been seen there
+func (v *StakeWiseValidatorInfo) GetChainStatusV0(beaconDB *Database_Beacon) apiv0.StakeWiseStatus {
+	if beaconValidator := beaconDB.GetValidator(v.Pubkey); beaconValidator != nil {
+		switch beaconValidator.Status {
+		case BeaconValidatorStatus_Exited:
+			return apiv0.StakeWiseStatus_Removed
+		case BeaconValidatorStatus_Active, BeaconValidatorStatus_Exiting:
+			return apiv0.StakeWiseStatus_Registered
+		case BeaconValidatorStatus_Pending:
+			return apiv0.StakeWiseStatus_Uploaded
+		}
+	}
+	return v.GetStatusV0()
+}
+
+// Get the validator's v2 status from its NodeSet state
 func (v *StakeWiseValidatorInfo) GetStatusV2() v2stakewise.StakeWiseStatus {
-	if v.MarkedActive {
+	if v.MarkedActive || v.IsActiveOnBeacon {
 		return v2stakewise.StakeWiseStatus_Registered
 	}
 	if v.DepositDataUsed {
@@ -86,3 +123,35 @@ func (v *StakeWiseValidatorInfo) GetStatusV2() v2stakewise.StakeWiseStatus {
 	}
 	return v2stakewise.StakeWiseStatus_Pending
 }
+
+// Get the validator's v2 status, using its state on the simulated Beacon chain if it has been seen there
+func (v *StakeWiseValidatorInfo) GetChainStatusV2(beaconDB *Database_Beacon) v2stakewise.StakeWiseStatus {
+	if beaconValidator := beaconDB.GetValidator(v.Pubkey); beaconValidator != nil {
+		switch beaconValidator.Status {
+		case BeaconValidatorStatus_Exited:
+			return v2stakewise.StakeWiseStatus_Removed
+		case BeaconValidatorStatus_Active, BeaconValidatorStatus_Exiting:
+			return v2stakewise.StakeWiseStatus_Registered
+		case BeaconValidatorStatus_Pending:
+			return v2stakewise.StakeWiseStatus_Uploaded
+		}
+	}
+	return v.GetStatusV2()
+}
+
+// Check if the validator has been registered with NodeSet, rather than only having its deposit data uploaded
+func (v *StakeWiseValidatorInfo) IsRegistered() bool {
+	return v.MarkedActive || v.IsActiveOnBeacon
+}
+
+// Get the validator's v3 lifecycle status, using its state on the simulated Beacon chain if it has been seen there.
+// Validators that haven't been registered with NodeSet don't have a lifecycle status, so this returns an empty one.
+func (v *StakeWiseValidatorInfo) GetStatusV3(beaconDB *Database_Beacon) common.ValidatorLifecycleStatus {
+	if !v.IsRegistered() {
+		return ""
+	}
+	if v.IsActiveOnBeacon && beaconDB.GetValidator(v.Pubkey) == nil {
+		return common.ValidatorLifecycleStatus_Active
+	}
+	return beaconDB.getLifecycleStatus(v.Pubkey, v.HasDepositEvent)
+}
@@ -2,8 +2,10 @@ package db
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 
 	"filippo.io/age"
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -15,6 +17,20 @@ import (
 
 const (
 	DefaultMaxValidatorsPerUser int = 1
+
+	// ETH balance of a new vault
+	DefaultVaultBalanceEth int64 = 1000
+
+	// ETH each validator registration takes from the vault's balance
+	ValidatorDepositAmountEth int64 = 32
+)
+
+var (
+	// The vault doesn't have enough ETH to deposit the validators being registered
+	ErrInsufficientVaultBalance error = errors.New("vault doesn't have enough ETH to register the validators")
+
+	// The deposit root was already used to register validators by another user
+	ErrDepositRootAlreadyAssigned error = errors.New("deposit root has already been assigned to another node operator")
 )
 
 // Info for StakeWise vaults
@@ -40,6 +56,9 @@ type StakeWiseVault struct {
 	// The max number of validators per user
 	MaxValidatorsPerUser int
 
+	// The ETH available for new validator deposits, in wei
+	Balance *big.Int
+
 	deployment *StakeWiseDeployment
 	db         *Database
 }
@@ -54,6 +73,7 @@ func newStakeWiseVault(deployment *StakeWiseDeployment, name string, address eth
 		LatestDepositDataSetIndex: 0,
 		Validators:                map[ethcommon.Address]map[beacon.ValidatorPubkey]*StakeWiseValidatorInfo{},
 		MaxValidatorsPerUser:      DefaultMaxValidatorsPerUser,
+		Balance:                   ethToWei(DefaultVaultBalanceEth),
 		deployment:                deployment,
 		db:                        deployment.db,
 	}
@@ -63,6 +83,7 @@ func newStakeWiseVault(deployment *StakeWiseDeployment, name string, address eth
 func (v *StakeWiseVault) clone(deploymentClone *StakeWiseDeployment) *StakeWiseVault {
 	clone := newStakeWiseVault(deploymentClone, v.Name, v.Address)
 	clone.MaxValidatorsPerUser = v.MaxValidatorsPerUser
+	clone.Balance = new(big.Int).Set(v.Balance)
 	clone.LatestDepositDataSetIndex = v.LatestDepositDataSetIndex
 	clone.LatestDepositDataSet = make([]beacon.ExtendedDepositData, len(v.LatestDepositDataSet))
 	copy(clone.LatestDepositDataSet, v.LatestDepositDataSet)
@@ -97,6 +118,16 @@ func (v *StakeWiseVault) AddStakeWiseDepositData(node *Node, depositData beacon.
 	nodeValidators[pubkey] = validator
 }
 
+// Get a StakeWise validator by its pubkey, regardless of which node it belongs to. Returns nil if it isn't in the vault.
+func (v *StakeWiseVault) GetStakeWiseValidator(pubkey beacon.ValidatorPubkey) *StakeWiseValidatorInfo {
+	for _, validators := range v.Validators {
+		if validator, exists := validators[pubkey]; exists {
+			return validator
+		}
+	}
+	return nil
+}
+
 // Get the StakeWise validators for a node
 func (v *StakeWiseVault) GetStakeWiseValidatorsForNode(node *Node) map[beacon.ValidatorPubkey]*StakeWiseValidatorInfo {
 	return v.Validators[node.Address]
@@ -133,6 +164,10 @@ func (v *StakeWiseVault) HandleSignedExitUpload(node *Node, data []common.ExitDa
 		found := false
 		for _, validator := range validators {
 			if validator.Pubkey == pubkey {
+				err := v.db.Beacon.ValidateExitMessage(pubkey, signedExit.ExitMessage)
+				if err != nil {
+					return err
+				}
 				validator.SetExitMessage(signedExit.ExitMessage)
 				found = true
 				break
@@ -189,6 +224,10 @@ func (v *StakeWiseVault) HandleEncryptedSignedExitUpload(node *Node, data []comm
 		found := false
 		for _, validator := range validators {
 			if validator.Pubkey == pubkey {
+				err := v.db.Beacon.ValidateExitMessage(pubkey, exitMessage)
+				if err != nil {
+					return err
+				}
 				validator.SetExitMessage(exitMessage)
 				found = true
 				break
@@ -235,6 +274,24 @@ func (v *StakeWiseVault) CreateNewDepositDataSet(validatorsPerUser int) []beacon
 	return depositData
 }
 
+// Get the number of deposit data entries from registered nodes that haven't been included in a set yet
+func (v *StakeWiseVault) GetPendingDepositDataCount() int {
+	count := 0
+	for _, user := range v.db.Core.users {
+		for _, node := range user.nodes {
+			if !node.isRegistered {
+				continue
+			}
+			for _, validator := range v.Validators[node.Address] {
+				if !validator.DepositDataUsed {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
 // Mark the deposit data for the provided validator as uploaded to StakeWise
 func (v *StakeWiseVault) MarkDepositDataUploaded(pubkey beacon.ValidatorPubkey) {
 	v.UploadedData[pubkey] = true
@@ -299,6 +356,26 @@ func (v *StakeWiseVault) MarkValidatorsRegistered(data []beacon.ExtendedDepositD
 	}
 }
 
+// Make the Beacon deposit for a validator that has been registered with the vault
+func (v *StakeWiseVault) DepositValidator(pubkey beacon.ValidatorPubkey) error {
+	for _, validators := range v.Validators {
+		validator, exists := validators[pubkey]
+		if !exists {
+			continue
+		}
+		if !validator.MarkedActive {
+			return fmt.Errorf("validator [%s] hasn't been registered with vault [%s]", pubkey.Hex(), v.Address.Hex())
+		}
+		if validator.HasDepositEvent {
+			return fmt.Errorf("validator [%s] has already been deposited", pubkey.Hex())
+		}
+		v.db.Eth.AddDeposit(validator.DepositData)
+		validator.HasDepositEvent = true
+		return nil
+	}
+	return fmt.Errorf("validator [%s] not found in vault [%s]", pubkey.Hex(), v.Address.Hex())
+}
+
 // Get the number of active / registered validators for a user
 func (v *StakeWiseVault) GetRegisteredValidatorsPerUser(user *User) int {
 	registered := 0
@@ -309,6 +386,7 @@ func (v *StakeWiseVault) GetRegisteredValidatorsPerUser(user *User) int {
 		validators := v.Validators[node.Address]
 		for _, validator := range validators {
 			if validator.IsActiveOnBeacon ||
+				v.db.Beacon.IsValidatorLive(validator.Pubkey) ||
 				validator.HasDepositEvent ||
 				validator.BeaconDepositRoot == v.db.Eth.depositRoot {
 				registered++
@@ -317,3 +395,53 @@ func (v *StakeWiseVault) GetRegisteredValidatorsPerUser(user *User) int {
 	}
 	return registered
 }
+
+// Get the user whose validators were registered with the provided Beacon deposit root, or nil if it hasn't been used yet
+func (v *StakeWiseVault) GetDepositRootAssignee(depositRoot ethcommon.Hash) *User {
+	for nodeAddress, validators := range v.Validators {
+		for _, validator := range validators {
+			if !validator.MarkedActive || validator.BeaconDepositRoot != depositRoot {
+				continue
+			}
+			node, _ := v.db.Core.GetNode(nodeAddress)
+			if node != nil {
+				return node.user
+			}
+		}
+	}
+	return nil
+}
+
+// Make sure a user can register validators with the provided Beacon deposit root and the vault can fund them
+func (v *StakeWiseVault) CheckValidatorRegistration(user *User, depositRoot ethcommon.Hash, count int) error {
+	assignee := v.GetDepositRootAssignee(depositRoot)
+	if assignee != nil && assignee.Email != user.Email {
+		return ErrDepositRootAlreadyAssigned
+	}
+	if v.Balance.Cmp(getValidatorDepositAmount(count)) < 0 {
+		return ErrInsufficientVaultBalance
+	}
+	return nil
+}
+
+// Take the deposits for newly registered validators out of the vault's balance
+func (v *StakeWiseVault) ConsumeValidatorDeposits(count int) error {
+	amount := getValidatorDepositAmount(count)
+	if v.Balance.Cmp(amount) < 0 {
+		return ErrInsufficientVaultBalance
+	}
+	v.Balance.Sub(v.Balance, amount)
+	return nil
+}
+
+// Get the ETH needed to deposit the provided number of validators, in wei
+func getValidatorDepositAmount(count int) *big.Int {
+	amount := ethToWei(ValidatorDepositAmountEth)
+	return amount.Mul(amount, big.NewInt(int64(count)))
+}
+
+// Convert a whole number of ETH to wei
+func ethToWei(amount int64) *big.Int {
+	wei := big.NewInt(amount)
+	return wei.Mul(wei, big.NewInt(1e18))
+}
@@ -0,0 +1,303 @@
+package db
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Get a serializable view of the database's state, restricted to the entities that match the filter
+func (d *Database) GetState(filter api.StateFilter) (api.StateData, error) {
+	state := api.StateData{
+		Users:         []api.UserState{},
+		Sessions:      []api.SessionState{},
+		StakeWise:     []api.StakeWiseDeploymentState{},
+		Constellation: []api.ConstellationDeploymentState{},
+		Eth: api.EthState{
+			ChainID:      new(big.Int).Set(d.Eth.ChainID),
+			BlockNumber:  d.Eth.GetBlockNumber(),
+			DepositCount: d.Eth.GetDepositCount(),
+			DepositRoot:  d.Eth.GetDepositRoot(),
+		},
+		Beacon: api.BeaconState{
+			GenesisTime:  d.Beacon.GenesisTime,
+			CurrentSlot:  d.Beacon.GetCurrentSlot(),
+			CurrentEpoch: d.Beacon.GetCurrentEpoch(),
+			Validators:   []api.BeaconValidatorState{},
+		},
+	}
+
+	// Users and nodes
+	for _, user := range d.Core.users {
+		if filter.User != "" && filter.User != user.Email {
+			continue
+		}
+		userState := api.UserState{
+			Email:              user.Email,
+			Nodes:              []api.NodeState{},
+			RevokedPermissions: user.GetRevokedPermissions(),
+		}
+		for _, node := range sortedNodes(user) {
+			if filter.Node != nil && *filter.Node != node.Address {
+				continue
+			}
+			userState.Nodes = append(userState.Nodes, api.NodeState{
+				Address:    node.Address,
+				Registered: node.isRegistered,
+			})
+		}
+		if filter.Node != nil && len(userState.Nodes) == 0 {
+			continue
+		}
+		state.Users = append(state.Users, userState)
+	}
+
+	// Sessions
+	for _, session := range d.Core.sessions {
+		if !matchesNodeFilter(d, filter, session.NodeAddress) {
+			continue
+		}
+		state.Sessions = append(state.Sessions, api.SessionState{
+			Nonce:       session.Nonce,
+			Token:       session.Token,
+			NodeAddress: session.NodeAddress,
+			LoggedIn:    session.isLoggedIn,
+		})
+	}
+
+	// StakeWise
+	for _, deploymentID := range sortedKeys(d.StakeWise.Deployments) {
+		deployment := d.StakeWise.Deployments[deploymentID]
+		deploymentState := api.StakeWiseDeploymentState{
+			ID:      deployment.ID,
+			ChainID: deployment.ChainID,
+			Vaults:  []api.StakeWiseVaultState{},
+		}
+		for _, vaultAddress := range sortedAddresses(deployment.Vaults) {
+			if filter.Vault != nil && *filter.Vault != vaultAddress {
+				continue
+			}
+			vault := deployment.Vaults[vaultAddress]
+			vaultState := api.StakeWiseVaultState{
+				Name:                      vault.Name,
+				Address:                   vault.Address,
+				MaxValidatorsPerUser:      vault.MaxValidatorsPerUser,
+				Balance:                   new(big.Int).Set(vault.Balance),
+				LatestDepositDataSetIndex: vault.LatestDepositDataSetIndex,
+				Validators:                []api.StakeWiseValidatorState{},
+			}
+			for _, nodeAddress := range sortedAddresses(vault.Validators) {
+				if !matchesNodeFilter(d, filter, nodeAddress) {
+					continue
+				}
+				validators := vault.Validators[nodeAddress]
+				for _, pubkey := range sortedPubkeys(validators) {
+					if filter.Pubkey != nil && *filter.Pubkey != pubkey {
+						continue
+					}
+					validator := validators[pubkey]
+					validatorState := api.StakeWiseValidatorState{
+						Pubkey:              validator.Pubkey,
+						NodeAddress:         nodeAddress,
+						UploadedToStakeWise: vault.UploadedData[pubkey],
+						DepositDataUsed:     validator.DepositDataUsed,
+						MarkedActive:        validator.MarkedActive,
+						HasDepositEvent:     validator.HasDepositEvent,
+						BeaconDepositRoot:   validator.BeaconDepositRoot,
+						RegistrationTime:    validator.RegistrationTime,
+						Status:              validator.GetStatusV3(d.Beacon),
+						ExitMessageUploaded: validator.ExitMessageUploaded,
+					}
+					if validator.ExitMessageUploaded {
+						signedExit := validator.SignedExit
+						validatorState.SignedExit = &signedExit
+					}
+					vaultState.Validators = append(vaultState.Validators, validatorState)
+				}
+			}
+			deploymentState.Vaults = append(deploymentState.Vaults, vaultState)
+		}
+		state.StakeWise = append(state.StakeWise, deploymentState)
+	}
+
+	// Constellation
+	for _, deploymentID := range sortedKeys(d.Constellation.deployments) {
+		deployment := d.Constellation.deployments[deploymentID]
+		deploymentState, err := deployment.getState(filter)
+		if err != nil {
+			return api.StateData{}, fmt.Errorf("error getting state for Constellation deployment [%s]: %w", deploymentID, err)
+		}
+		state.Constellation = append(state.Constellation, deploymentState)
+	}
+
+	// Beacon
+	for _, validator := range d.Beacon.validators {
+		if filter.Pubkey != nil && *filter.Pubkey != validator.Pubkey {
+			continue
+		}
+		state.Beacon.Validators = append(state.Beacon.Validators, api.BeaconValidatorState{
+			Pubkey:          validator.Pubkey,
+			Index:           validator.Index,
+			Status:          string(validator.Status),
+			ActivationEpoch: validator.ActivationEpoch,
+			ExitEpoch:       validator.ExitEpoch,
+			Slashed:         validator.Slashed,
+		})
+	}
+	return state, nil
+}
+
+// Get a serializable view of the deployment's state
+func (d *ConstellationDeployment) getState(filter api.StateFilter) (api.ConstellationDeploymentState, error) {
+	state := api.ConstellationDeploymentState{
+		ID:               d.ID,
+		ChainID:          d.ChainID,
+		WhitelistAddress: d.WhitelistAddress,
+		SuperNodeAddress: d.SuperNodeAddress,
+		Whitelist:        []api.ConstellationWhitelistEntry{},
+		Nodes:            []api.ConstellationNodeState{},
+
+		MinipoolLimit:       d.MinipoolLimit,
+		RequireExitMessages: d.RequireExitMessages,
+		UserMinipoolLimits:  map[string]int{},
+		NodeMinipoolLimits:  map[ethcommon.Address]int{},
+	}
+	for email, limit := range d.userMinipoolLimits {
+		state.UserMinipoolLimits[email] = limit
+	}
+	for address, limit := range d.nodeMinipoolLimits {
+		state.NodeMinipoolLimits[address] = limit
+	}
+	for _, email := range sortedKeys(d.whitelistedNodeMap) {
+		address := d.whitelistedNodeMap[email]
+		if filter.User != "" && filter.User != email {
+			continue
+		}
+		if filter.Node != nil && *filter.Node != address {
+			continue
+		}
+		state.Whitelist = append(state.Whitelist, api.ConstellationWhitelistEntry{
+			User:        email,
+			NodeAddress: address,
+		})
+	}
+
+	// Include every known node so nonces show up even before a node has minipools
+	for _, user := range d.db.Core.users {
+		for _, node := range sortedNodes(user) {
+			if !matchesNodeFilter(d.db, filter, node.Address) {
+				continue
+			}
+			whitelistNonce, err := d.chainBackend.GetWhitelistNonce(node.Address)
+			if err != nil {
+				return api.ConstellationDeploymentState{}, err
+			}
+			superNodeNonce, err := d.chainBackend.GetSuperNodeNonce(node.Address)
+			if err != nil {
+				return api.ConstellationDeploymentState{}, err
+			}
+			nodeState := api.ConstellationNodeState{
+				Address:        node.Address,
+				WhitelistNonce: whitelistNonce,
+				SuperNodeNonce: superNodeNonce,
+				Minipools:      []api.ConstellationMinipoolState{},
+			}
+			for _, minipool := range d.minipools[node.Address] {
+				validator, err := d.getValidatorForMinipool(minipool)
+				if err != nil {
+					return api.ConstellationDeploymentState{}, err
+				}
+				if filter.Pubkey != nil && (validator == nil || validator.Pubkey != *filter.Pubkey) {
+					continue
+				}
+				minipoolState := api.ConstellationMinipoolState{
+					Address: minipool,
+				}
+				if validator != nil {
+					pubkey := validator.Pubkey
+					minipoolState.Pubkey = &pubkey
+					depositRoot := validator.BeaconDepositRoot
+					minipoolState.BeaconDepositRoot = &depositRoot
+					registrationTime := validator.RegistrationTime
+					minipoolState.RegistrationTime = &registrationTime
+					minipoolState.Status = validator.GetStatusV3(d.db.Beacon)
+					if exitMessage := validator.GetExitMessage(); exitMessage != nil {
+						exitMessageCopy := *exitMessage
+						minipoolState.ExitMessage = &exitMessageCopy
+					}
+				}
+				nodeState.Minipools = append(nodeState.Minipools, minipoolState)
+			}
+			if filter.Pubkey != nil && len(nodeState.Minipools) == 0 {
+				continue
+			}
+			state.Nodes = append(state.Nodes, nodeState)
+		}
+	}
+	return state, nil
+}
+
+// =========================
+// === Filtering Helpers ===
+// =========================
+
+// Check if the node address passes the filter's user and node restrictions
+func matchesNodeFilter(d *Database, filter api.StateFilter, address ethcommon.Address) bool {
+	if filter.Node != nil && *filter.Node != address {
+		return false
+	}
+	if filter.User != "" {
+		node, _ := d.Core.GetNode(address)
+		if node == nil || node.user.Email != filter.User {
+			return false
+		}
+	}
+	return true
+}
+
+// Get a user's nodes sorted by address
+func sortedNodes(user *User) []*Node {
+	nodes := make([]*Node, 0, len(user.nodes))
+	for _, address := range sortedAddresses(user.nodes) {
+		nodes = append(nodes, user.nodes[address])
+	}
+	return nodes
+}
+
+// Get the keys of a string-keyed map in sorted order
+func sortedKeys[ValueType any](m map[string]ValueType) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get the keys of an address-keyed map in sorted order
+func sortedAddresses[ValueType any](m map[ethcommon.Address]ValueType) []ethcommon.Address {
+	keys := make([]ethcommon.Address, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Cmp(keys[j]) < 0
+	})
+	return keys
+}
+
+// Get the keys of a pubkey-keyed map in sorted order
+func sortedPubkeys[ValueType any](m map[beacon.ValidatorPubkey]ValueType) []beacon.ValidatorPubkey {
+	keys := make([]beacon.ValidatorPubkey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Hex() < keys[j].Hex()
+	})
+	return keys
+}
@@ -2,26 +2,38 @@ package db
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 )
 
 var (
 	ErrNotWhitelisted error = errors.New("node address hasn't been whitelisted on the provided NodeSet account")
 )
 
+// Comparable form of a permission, used as a map key
+type permissionKey struct {
+	module     string
+	deployment string
+	vault      common.Address
+}
+
 type User struct {
 	Email string
 
-	nodes map[common.Address]*Node
-	db    *Database
+	nodes              map[common.Address]*Node
+	revokedPermissions map[permissionKey]bool
+	db                 *Database
 }
 
 func newUser(db *Database, email string) *User {
 	return &User{
-		Email: email,
-		nodes: map[common.Address]*Node{},
-		db:    db,
+		Email:              email,
+		nodes:              map[common.Address]*Node{},
+		revokedPermissions: map[permissionKey]bool{},
+		db:                 db,
 	}
 }
 
@@ -30,6 +42,9 @@ func (u *User) clone(dbClone *Database) *User {
 	for address, node := range u.nodes {
 		userClone.nodes[address] = node.clone(userClone)
 	}
+	for key := range u.revokedPermissions {
+		userClone.revokedPermissions[key] = true
+	}
 	return userClone
 }
 
@@ -42,6 +57,21 @@ func (u *User) WhitelistNode(nodeAddress common.Address) *Node {
 	return node
 }
 
+// Removes a node from the user's whitelist, logging out its sessions and removing it from the user's Constellation whitelist entries
+func (u *User) RemoveNode(nodeAddress common.Address) error {
+	if _, exists := u.nodes[nodeAddress]; !exists {
+		return ErrNotWhitelisted
+	}
+	delete(u.nodes, nodeAddress)
+	u.db.Core.removeSessionsForNode(nodeAddress)
+	for _, deployment := range u.db.Constellation.deployments {
+		if address, exists := deployment.whitelistedNodeMap[u.Email]; exists && address == nodeAddress {
+			delete(deployment.whitelistedNodeMap, u.Email)
+		}
+	}
+	return nil
+}
+
 func (u *User) GetNode(nodeAddress common.Address) *Node {
 	return u.nodes[nodeAddress]
 }
@@ -49,3 +79,88 @@ func (u *User) GetNode(nodeAddress common.Address) *Node {
 func (u *User) GetNodes() map[common.Address]*Node {
 	return u.nodes
 }
+
+// Grants or revokes a permission. Users have every permission until it's revoked.
+func (u *User) SetPermission(permission api.Permission, permitted bool) error {
+	key, err := getPermissionKey(permission)
+	if err != nil {
+		return err
+	}
+	if permitted {
+		delete(u.revokedPermissions, key)
+	} else {
+		u.revokedPermissions[key] = true
+	}
+	return nil
+}
+
+// Checks if the user can use a module, deployment, or vault. Access is denied if the permission or any of its parents has been revoked.
+func (u *User) HasPermission(permission api.Permission) bool {
+	if u.revokedPermissions[permissionKey{module: permission.Module}] {
+		return false
+	}
+	if permission.Deployment == "" {
+		return true
+	}
+	if u.revokedPermissions[permissionKey{module: permission.Module, deployment: permission.Deployment}] {
+		return false
+	}
+	if permission.Vault == nil {
+		return true
+	}
+	return !u.revokedPermissions[permissionKey{module: permission.Module, deployment: permission.Deployment, vault: *permission.Vault}]
+}
+
+// Gets the permissions that have been revoked from the user, sorted by module, deployment, and vault
+func (u *User) GetRevokedPermissions() []api.Permission {
+	keys := make([]permissionKey, 0, len(u.revokedPermissions))
+	for key := range u.revokedPermissions {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].module != keys[j].module {
+			return keys[i].module < keys[j].module
+		}
+		if keys[i].deployment != keys[j].deployment {
+			return keys[i].deployment < keys[j].deployment
+		}
+		return keys[i].vault.Cmp(keys[j].vault) < 0
+	})
+
+	permissions := make([]api.Permission, 0, len(keys))
+	for _, key := range keys {
+		permission := api.Permission{
+			Module:     key.module,
+			Deployment: key.deployment,
+		}
+		if key.vault != (common.Address{}) {
+			vault := key.vault
+			permission.Vault = &vault
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
+
+// Validates a permission and converts it to its map key
+func getPermissionKey(permission api.Permission) (permissionKey, error) {
+	switch permission.Module {
+	case api.PermissionModule_StakeWise, api.PermissionModule_Constellation:
+	default:
+		return permissionKey{}, fmt.Errorf("unknown module [%s]", permission.Module)
+	}
+	key := permissionKey{
+		module:     permission.Module,
+		deployment: permission.Deployment,
+	}
+	if permission.Vault != nil {
+		if permission.Module != api.PermissionModule_StakeWise {
+			return permissionKey{}, fmt.Errorf("vault permissions are only supported by the [%s] module", api.PermissionModule_StakeWise)
+		}
+		if permission.Deployment == "" {
+			return permissionKey{}, fmt.Errorf("vault permissions require a deployment")
+		}
+		key.vault = *permission.Vault
+	}
+	return key, nil
+}
@@ -0,0 +1,137 @@
+// Package fake provides NodeSet clients that are served by a mock server entirely in memory, for unit tests that shouldn't
+// open a network listener. The fake bindings implement the same interfaces as the HTTP bindings and send their requests
+// through the mock server's own router, so they return exactly the same data and errors as the HTTP clients do.
+package fake
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	v2constellation "github.com/nodeset-org/nodeset-client-go/api-v2/constellation"
+	v2core "github.com/nodeset-org/nodeset-client-go/api-v2/core"
+	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	v3constellation "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
+	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server"
+)
+
+const (
+	// The base URL used by the fake clients; it's never resolved
+	BaseUrl string = "http://nodeset-mock/api"
+)
+
+// Fake binding for the v2 core routes
+type V2CoreClient struct {
+	*v2core.V2CoreClient
+}
+
+// Fake binding for the v2 StakeWise routes
+type V2StakeWiseClient struct {
+	*v2stakewise.V2StakeWiseClient
+}
+
+// Fake binding for the v2 Constellation routes
+type V2ConstellationClient struct {
+	*v2constellation.V2ConstellationClient
+}
+
+// Fake binding for the v3 core routes
+type V3CoreClient struct {
+	*v3core.V3CoreClient
+}
+
+// Fake binding for the v3 StakeWise routes
+type V3StakeWiseClient struct {
+	*v3stakewise.V3StakeWiseClient
+}
+
+// Fake binding for the v3 Constellation routes
+type V3ConstellationClient struct {
+	*v3constellation.V3ConstellationClient
+}
+
+// HTTP transport that serves requests with a mock server's router in memory instead of sending them over the network
+type Transport struct {
+	server    *server.NodeSetMockServer
+	namespace string
+}
+
+// Creates a new in-memory transport for the mock server's root namespace. The server doesn't need to be started.
+func NewTransport(server *server.NodeSetMockServer) *Transport {
+	return &Transport{
+		server: server,
+	}
+}
+
+// Creates a new in-memory transport for one of the mock server's namespaces. The server doesn't need to be started.
+func NewNamespaceTransport(server *server.NodeSetMockServer, namespace string) *Transport {
+	return &Transport{
+		server:    server,
+		namespace: namespace,
+	}
+}
+
+// Serve the request with the mock server and return its response
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := request.Context().Err(); err != nil {
+		return nil, err
+	}
+	if t.namespace != "" {
+		request = request.Clone(request.Context())
+		request.Header.Set(api.NamespaceHeader, t.namespace)
+	}
+	recorder := httptest.NewRecorder()
+	t.server.ServeHTTP(recorder, request)
+	response := recorder.Result()
+	response.Request = request
+	return response, nil
+}
+
+// Creates a new HTTP client that uses the in-memory transport
+func NewHttpClient(transport *Transport) *http.Client {
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// Creates a v2 NodeSet client whose core, StakeWise, and Constellation bindings are fakes served by the mock server's root
+// namespace
+func NewV2Client(server *server.NodeSetMockServer) *apiv2.NodeSetClient {
+	return NewV2ClientWithTransport(NewTransport(server))
+}
+
+// Creates a v2 NodeSet client whose core, StakeWise, and Constellation bindings are fakes served by the transport
+func NewV2ClientWithTransport(transport *Transport) *apiv2.NodeSetClient {
+	expandedUrl, _ := url.JoinPath(BaseUrl, apiv2.ApiVersion)
+	commonClient := common.NewCommonNodeSetClientWithHttpClient(expandedUrl, NewHttpClient(transport))
+	return &apiv2.NodeSetClient{
+		CommonNodeSetClient: commonClient,
+		Core:                &V2CoreClient{V2CoreClient: v2core.NewV2CoreClient(commonClient)},
+		StakeWise:           &V2StakeWiseClient{V2StakeWiseClient: v2stakewise.NewV2StakeWiseClient(commonClient)},
+		Constellation:       &V2ConstellationClient{V2ConstellationClient: v2constellation.NewV2ConstellationClient(commonClient)},
+	}
+}
+
+// Creates a v3 NodeSet client whose core, StakeWise, and Constellation bindings are fakes served by the mock server's root
+// namespace
+func NewV3Client(server *server.NodeSetMockServer) *apiv3.NodeSetClient {
+	return NewV3ClientWithTransport(NewTransport(server))
+}
+
+// Creates a v3 NodeSet client whose core, StakeWise, and Constellation bindings are fakes served by the transport
+func NewV3ClientWithTransport(transport *Transport) *apiv3.NodeSetClient {
+	expandedUrl, _ := url.JoinPath(BaseUrl, apiv3.ApiVersion)
+	commonClient := common.NewCommonNodeSetClientWithHttpClient(expandedUrl, NewHttpClient(transport))
+	return &apiv3.NodeSetClient{
+		CommonNodeSetClient: commonClient,
+		Core:                &V3CoreClient{V3CoreClient: v3core.NewV3CoreClient(commonClient)},
+		StakeWise:           &V3StakeWiseClient{V3StakeWiseClient: v3stakewise.NewV3StakeWiseClient(commonClient)},
+		Constellation:       &V3ConstellationClient{V3ConstellationClient: v3constellation.NewV3ConstellationClient(commonClient)},
+	}
+}
@@ -0,0 +1,490 @@
+package fake_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	v2constellation "github.com/nodeset-org/nodeset-client-go/api-v2/constellation"
+	v2core "github.com/nodeset-org/nodeset-client-go/api-v2/core"
+	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	v3constellation "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
+	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/core"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/fake"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	nsutil "github.com/nodeset-org/nodeset-client-go/utils"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ v2core.CoreClient                   = (*fake.V2CoreClient)(nil)
+	_ v2stakewise.StakeWiseClient         = (*fake.V2StakeWiseClient)(nil)
+	_ v2constellation.ConstellationClient = (*fake.V2ConstellationClient)(nil)
+	_ v3core.CoreClient                   = (*fake.V3CoreClient)(nil)
+	_ v3stakewise.StakeWiseClient         = (*fake.V3StakeWiseClient)(nil)
+	_ v3constellation.ConstellationClient = (*fake.V3ConstellationClient)(nil)
+)
+
+const (
+	timeout time.Duration = 5 * time.Second
+)
+
+// Starts a mock server with a provisioned database, returning it and the URL of an HTTP listener for comparing against
+func startServer(t *testing.T, logger *slog.Logger) (*server.NodeSetMockServer, string) {
+	s, err := server.NewNodeSetMockServer(logger, "localhost", 0)
+	require.NoError(t, err)
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	testkit.MarkValidatorsActive(database)
+	s.GetManager().SetDatabase(database)
+	listener := httptest.NewServer(s)
+	t.Cleanup(listener.Close)
+	return s, listener.URL + "/api"
+}
+
+// Make sure the fake and HTTP clients get the same data and errors back
+func requireSameResult[DataType any](t *testing.T, httpCall func() (DataType, error), fakeCall func() (DataType, error)) {
+	t.Helper()
+	httpData, httpErr := httpCall()
+	fakeData, fakeErr := fakeCall()
+	require.Equal(t, httpErr, fakeErr)
+	require.Equal(t, httpData, fakeData)
+}
+
+// Make sure the fake and HTTP clients get the same validators and errors back, in any order
+func requireSameValidators(t *testing.T, httpCall func() (v3stakewise.ValidatorsData, error), fakeCall func() (v3stakewise.ValidatorsData, error)) {
+	t.Helper()
+	httpData, httpErr := httpCall()
+	fakeData, fakeErr := fakeCall()
+	require.Equal(t, httpErr, fakeErr)
+	require.ElementsMatch(t, httpData.Validators, fakeData.Validators)
+}
+
+// Run a call that can change the database with the HTTP client, then again with the fake from the same starting point,
+// making sure both get the same data and errors back and leave the database in the same state. The fake's changes are
+// kept, and its error is returned.
+func requireSameChange[DataType any](t *testing.T, mgr *manager.NodeSetMockManager, httpCall func() (DataType, error), fakeCall func() (DataType, error)) error {
+	t.Helper()
+	mgr.TakeSnapshot("parity")
+	httpData, httpErr := httpCall()
+	httpState, err := mgr.GetDatabase().GetState(api.StateFilter{})
+	require.NoError(t, err)
+	require.NoError(t, mgr.RevertToSnapshot("parity"))
+	fakeData, fakeErr := fakeCall()
+	fakeState, err := mgr.GetDatabase().GetState(api.StateFilter{})
+	require.NoError(t, err)
+	require.Equal(t, httpErr, fakeErr)
+	require.Equal(t, httpData, fakeData)
+	require.Equal(t, httpState, fakeState)
+	return fakeErr
+}
+
+// Make sure the v3 fakes return the same data and errors as the HTTP client
+func TestFakeV3Clients(t *testing.T) {
+	logger := slog.Default()
+	s, apiUrl := startServer(t, logger)
+	database := s.GetManager().GetDatabase()
+	session := testkit.LoginNode(t, database, testkit.GetNodeAddress(t, 1))
+	httpClient := apiv3.NewNodeSetClient(apiUrl, timeout)
+	httpClient.SetSessionToken(session.Token)
+	fakeClient := fake.NewV3Client(s)
+	fakeClient.SetSessionToken(session.Token)
+	ctx := context.Background()
+
+	// StakeWise routes
+	requireSameResult(t,
+		func() (common.DeploymentsData, error) { return httpClient.StakeWise.Deployments(ctx, logger) },
+		func() (common.DeploymentsData, error) { return fakeClient.StakeWise.Deployments(ctx, logger) },
+	)
+	requireSameResult(t,
+		func() (v3stakewise.VaultsData, error) {
+			return httpClient.StakeWise.Vaults(ctx, logger, testkit.Network)
+		},
+		func() (v3stakewise.VaultsData, error) {
+			return fakeClient.StakeWise.Vaults(ctx, logger, testkit.Network)
+		},
+	)
+	requireSameValidators(t,
+		func() (v3stakewise.ValidatorsData, error) {
+			return httpClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+		},
+		func() (v3stakewise.ValidatorsData, error) {
+			return fakeClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+		},
+	)
+	data, err := fakeClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	require.Len(t, data.Validators, 2)
+	requireSameValidators(t,
+		func() (v3stakewise.ValidatorsData, error) {
+			return httpClient.StakeWise.Validators_Get(ctx, logger, "bogus", testkit.StakeWiseVaultAddress)
+		},
+		func() (v3stakewise.ValidatorsData, error) {
+			return fakeClient.StakeWise.Validators_Get(ctx, logger, "bogus", testkit.StakeWiseVaultAddress)
+		},
+	)
+	requireSameValidators(t,
+		func() (v3stakewise.ValidatorsData, error) {
+			return httpClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.WhitelistAddress)
+		},
+		func() (v3stakewise.ValidatorsData, error) {
+			return fakeClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.WhitelistAddress)
+		},
+	)
+	t.Log("StakeWise routes matched")
+
+	// Constellation routes
+	requireSameResult(t,
+		func() (common.DeploymentsData, error) { return httpClient.Constellation.Deployments(ctx, logger) },
+		func() (common.DeploymentsData, error) { return fakeClient.Constellation.Deployments(ctx, logger) },
+	)
+	requireSameResult(t,
+		func() (v3constellation.Whitelist_GetData, error) {
+			return httpClient.Constellation.Whitelist_Get(ctx, logger, testkit.Network)
+		},
+		func() (v3constellation.Whitelist_GetData, error) {
+			return fakeClient.Constellation.Whitelist_Get(ctx, logger, testkit.Network)
+		},
+	)
+	requireSameResult(t,
+		func() (v3constellation.MinipoolDepositSignatureData, error) {
+			return httpClient.Constellation.MinipoolDepositSignature(ctx, logger, "bogus", testkit.WhitelistAddress, big.NewInt(1))
+		},
+		func() (v3constellation.MinipoolDepositSignatureData, error) {
+			return fakeClient.Constellation.MinipoolDepositSignature(ctx, logger, "bogus", testkit.WhitelistAddress, big.NewInt(1))
+		},
+	)
+	t.Log("Constellation routes matched")
+
+	// Permission and session errors
+	user := database.Core.GetUser(testkit.User2Email)
+	vaultAddress := testkit.StakeWiseVaultAddress
+	require.NoError(t, user.SetPermission(api.Permission{Module: api.PermissionModule_StakeWise, Deployment: testkit.Network, Vault: &vaultAddress}, false))
+	requireSameValidators(t,
+		func() (v3stakewise.ValidatorsData, error) {
+			return httpClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+		},
+		func() (v3stakewise.ValidatorsData, error) {
+			return fakeClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+		},
+	)
+	_, err = fakeClient.StakeWise.Validators_Get(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	httpClient.SetSessionToken("bogus")
+	fakeClient.SetSessionToken("bogus")
+	requireSameResult(t,
+		func() (common.DeploymentsData, error) { return httpClient.Constellation.Deployments(ctx, logger) },
+		func() (common.DeploymentsData, error) { return fakeClient.Constellation.Deployments(ctx, logger) },
+	)
+	_, err = fakeClient.Constellation.Deployments(ctx, logger)
+	require.ErrorIs(t, err, common.ErrInvalidSession)
+	t.Log("Fake clients returned the expected errors")
+
+	// Canceled requests aren't served
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = fakeClient.Core.Nonce(canceledCtx, logger)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// Make sure nodes can register and log in through the fake core client
+func TestFakeCoreClient(t *testing.T) {
+	logger := slog.Default()
+	s, _ := startServer(t, logger)
+	database := s.GetManager().GetDatabase()
+	client := fake.NewV2Client(s)
+	ctx := context.Background()
+	nodeKey := testkit.GetNodeKey(t, 4)
+	nodeAddress := testkit.GetNodeAddress(t, 4)
+	signer := func(message []byte) ([]byte, error) {
+		return nsutil.CreateSignature(message, nodeKey)
+	}
+
+	// Nodes have to be whitelisted before they can register
+	err := client.Core.NodeAddress(ctx, logger, testkit.User0Email, nodeAddress, signer)
+	require.ErrorIs(t, err, core.ErrNotWhitelisted)
+	database.Core.GetUser(testkit.User0Email).WhitelistNode(nodeAddress)
+	err = client.Core.NodeAddress(ctx, logger, testkit.User0Email, nodeAddress, signer)
+	require.NoError(t, err)
+	err = client.Core.NodeAddress(ctx, logger, testkit.User0Email, nodeAddress, signer)
+	require.ErrorIs(t, err, core.ErrAlreadyRegistered)
+	t.Log("Registered the node")
+
+	// Log in and use the session with the other bindings
+	nonce, err := client.Core.Nonce(ctx, logger)
+	require.NoError(t, err)
+	client.SetSessionToken(nonce.Token)
+	_, err = client.StakeWise.Deployments(ctx, logger)
+	require.Error(t, err)
+	login, err := client.Core.Login(ctx, logger, nonce.Nonce, nodeAddress, signer)
+	require.NoError(t, err)
+	require.Equal(t, nonce.Token, login.Token)
+	deployments, err := client.StakeWise.Deployments(ctx, logger)
+	require.NoError(t, err)
+	require.Len(t, deployments.Deployments, 1)
+	t.Log("Logged in and used the session")
+
+	// Logins with the wrong key are rejected
+	nonce, err = client.Core.Nonce(ctx, logger)
+	require.NoError(t, err)
+	client.SetSessionToken(nonce.Token)
+	_, err = client.Core.Login(ctx, logger, nonce.Nonce, testkit.GetNodeAddress(t, 0), signer)
+	require.ErrorIs(t, err, common.ErrInvalidSignature)
+}
+
+// Make sure validator registrations through the fake v3 StakeWise client succeed and fail the same way as over HTTP
+func TestFakeValidatorRegistration(t *testing.T) {
+	logger := slog.Default()
+	s, apiUrl := startServer(t, logger)
+	mgr := s.GetManager()
+	database := mgr.GetDatabase()
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	database.SetSecretEncryptionIdentity(id)
+	database.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress).MaxValidatorsPerUser = 10
+	session := testkit.LoginNode(t, database, testkit.GetNodeAddress(t, 0))
+	details := make([]v3stakewise.ValidatorRegistrationDetails, 2)
+	for i := range details {
+		exitMessage := common.ExitMessage{
+			Message: common.ExitMessageDetails{
+				Epoch:          "0",
+				ValidatorIndex: strconv.Itoa(i),
+			},
+			Signature: "0x01",
+		}
+		encryptedMessage, err := common.EncryptSignedExitMessage(exitMessage, id.Recipient().String())
+		require.NoError(t, err)
+		pubkey := make([]byte, 48)
+		pubkey[0] = byte(i + 1)
+		details[i] = v3stakewise.ValidatorRegistrationDetails{
+			DepositData: beacon.ExtendedDepositData{
+				PublicKey: pubkey,
+				Signature: make([]byte, 96),
+			},
+			ExitMessage: encryptedMessage,
+		}
+	}
+	mgr.TakeSnapshot("start")
+	httpClient := apiv3.NewNodeSetClient(apiUrl, timeout)
+	httpClient.SetSessionToken(session.Token)
+	fakeClient := fake.NewV3Client(s)
+	fakeClient.SetSessionToken(session.Token)
+
+	// Each case changes the starting database and returns the deposit root to register with
+	testCases := []struct {
+		name          string
+		setup         func(database *db.Database, vault *db.StakeWiseVault) ethcommon.Hash
+		expectedErr   error
+		errorContains string
+	}{
+		{
+			name: "registration",
+			setup: func(database *db.Database, vault *db.StakeWiseVault) ethcommon.Hash {
+				return database.Eth.GetDepositRoot()
+			},
+		}, {
+			name: "stale deposit root",
+			setup: func(database *db.Database, vault *db.StakeWiseVault) ethcommon.Hash {
+				depositRoot := database.Eth.GetDepositRoot()
+				database.Eth.AddDeposit(testkit.GenerateDepositData(t, 4, testkit.StakeWiseVaultAddress))
+				return depositRoot
+			},
+			expectedErr: v3stakewise.ErrDepositRootAlreadyAssigned,
+		}, {
+			name: "insufficient vault balance",
+			setup: func(database *db.Database, vault *db.StakeWiseVault) ethcommon.Hash {
+				vault.Balance = big.NewInt(0)
+				return database.Eth.GetDepositRoot()
+			},
+			expectedErr: common.ErrInsufficientVaultBalance,
+		}, {
+			name: "not enough slots",
+			setup: func(database *db.Database, vault *db.StakeWiseVault) ethcommon.Hash {
+				vault.MaxValidatorsPerUser = 1
+				return database.Eth.GetDepositRoot()
+			},
+			errorContains: "code 400",
+		}, {
+			name: "invalid exit message",
+			setup: func(database *db.Database, vault *db.StakeWiseVault) ethcommon.Hash {
+				// The second validator gets Beacon index 0, which doesn't match its exit message
+				database.Eth.AddDeposit(details[1].DepositData)
+				return database.Eth.GetDepositRoot()
+			},
+			expectedErr: common.ErrInvalidExitMessage,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.NoError(t, mgr.RevertToSnapshot("start"))
+			database := mgr.GetDatabase()
+			depositRoot := testCase.setup(database, database.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress))
+
+			err := requireSameChange(t, mgr,
+				func() (v3stakewise.PostValidatorData, error) {
+					return httpClient.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, details, depositRoot)
+				},
+				func() (v3stakewise.PostValidatorData, error) {
+					return fakeClient.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, details, depositRoot)
+				},
+			)
+			switch {
+			case testCase.expectedErr != nil:
+				require.ErrorIs(t, err, testCase.expectedErr)
+			case testCase.errorContains != "":
+				require.ErrorContains(t, err, testCase.errorContains)
+			default:
+				require.NoError(t, err)
+				data, err := fakeClient.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+				require.NoError(t, err)
+				require.Len(t, data.Validators, 3)
+			}
+		})
+	}
+}
+
+// Make sure Constellation whitelisting, minipool signatures, and exit uploads through the fake v3 client behave the same
+// as over HTTP
+func TestFakeConstellationClient(t *testing.T) {
+	logger := slog.Default()
+	s, apiUrl := startServer(t, logger)
+	mgr := s.GetManager()
+	database := mgr.GetDatabase()
+	adminKey, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	database.Constellation.GetDeployment(testkit.Network).SetAdminPrivateKey(adminKey)
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	database.SetSecretEncryptionIdentity(id)
+	nodeAddress := testkit.GetNodeAddress(t, 1)
+	session := testkit.LoginNode(t, database, nodeAddress)
+	httpClient := apiv3.NewNodeSetClient(apiUrl, timeout)
+	httpClient.SetSessionToken(session.Token)
+	fakeClient := fake.NewV3Client(s)
+	fakeClient.SetSessionToken(session.Token)
+	ctx := context.Background()
+	minipools := []ethcommon.Address{
+		ethcommon.HexToAddress("0x90de0"),
+		ethcommon.HexToAddress("0x90de1"),
+	}
+	pubkey := beacon.ValidatorPubkey{0xbe}
+	requestSignature := func(minipool ethcommon.Address) error {
+		return requireSameChange(t, mgr,
+			func() (v3constellation.MinipoolDepositSignatureData, error) {
+				return httpClient.Constellation.MinipoolDepositSignature(ctx, logger, testkit.Network, minipool, big.NewInt(1))
+			},
+			func() (v3constellation.MinipoolDepositSignatureData, error) {
+				return fakeClient.Constellation.MinipoolDepositSignature(ctx, logger, testkit.Network, minipool, big.NewInt(1))
+			},
+		)
+	}
+
+	// Whitelist the node and create a minipool
+	err = requireSameChange(t, mgr,
+		func() (v3constellation.Whitelist_PostData, error) {
+			return httpClient.Constellation.Whitelist_Post(ctx, logger, testkit.Network)
+		},
+		func() (v3constellation.Whitelist_PostData, error) {
+			return fakeClient.Constellation.Whitelist_Post(ctx, logger, testkit.Network)
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, requestSignature(minipools[0]))
+	deployment := mgr.GetDatabase().Constellation.GetDeployment(testkit.Network)
+	deployment.SetValidatorInfoForMinipool(minipools[0], pubkey)
+	deployment.IncrementSuperNodeNonce(nodeAddress)
+	t.Log("Whitelisted the node and created a minipool")
+
+	// The next minipool needs the first one's exit message
+	deployment.RequireExitMessages = true
+	require.ErrorIs(t, requestSignature(minipools[1]), common.ErrMissingExitMessage)
+	encryptedMessage, err := common.EncryptSignedExitMessage(common.ExitMessage{
+		Message: common.ExitMessageDetails{
+			Epoch:          strconv.FormatUint(testkit.ExitEpoch, 10),
+			ValidatorIndex: "0",
+		},
+		Signature: "0x01",
+	}, id.Recipient().String())
+	require.NoError(t, err)
+	exitData := []common.EncryptedExitData{
+		{
+			Pubkey:      pubkey.HexWithPrefix(),
+			ExitMessage: encryptedMessage,
+		},
+	}
+	uploadExits := func() error {
+		return requireSameChange(t, mgr,
+			func() (struct{}, error) {
+				return struct{}{}, httpClient.Constellation.Validators_Patch(ctx, logger, testkit.Network, exitData)
+			},
+			func() (struct{}, error) {
+				return struct{}{}, fakeClient.Constellation.Validators_Patch(ctx, logger, testkit.Network, exitData)
+			},
+		)
+	}
+	require.NoError(t, uploadExits())
+	require.ErrorIs(t, uploadExits(), v3constellation.ErrExitMessageExists)
+	t.Log("Missing exit message was enforced and uploaded")
+
+	// The deployment's limit applies the same way
+	mgr.GetDatabase().Constellation.GetDeployment(testkit.Network).MinipoolLimit = 1
+	require.ErrorIs(t, requestSignature(minipools[1]), common.ErrMinipoolLimitReached)
+	t.Log("Minipool limit was enforced")
+}
+
+// Make sure the v2 fakes upload exit messages and work with the v2 exit message client the same way as over HTTP
+func TestFakeV2ExitMessages(t *testing.T) {
+	logger := slog.Default()
+	s, apiUrl := startServer(t, logger)
+	mgr := s.GetManager()
+	database := mgr.GetDatabase()
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	database.SetSecretEncryptionIdentity(id)
+	session := testkit.LoginNode(t, database, testkit.GetNodeAddress(t, 1))
+	httpClient := apiv2.NewNodeSetClient(apiUrl, timeout)
+	httpClient.SetSessionToken(session.Token)
+	fakeClient := fake.NewV2Client(s)
+	fakeClient.SetSessionToken(session.Token)
+	ctx := context.Background()
+
+	httpMissing, err := httpClient.StakeWise.NewExitMessageClient(testkit.Network, testkit.StakeWiseVaultAddress).GetValidatorsMissingExitMessages(ctx, logger)
+	require.NoError(t, err)
+	fakeMissing, err := fakeClient.StakeWise.NewExitMessageClient(testkit.Network, testkit.StakeWiseVaultAddress).GetValidatorsMissingExitMessages(ctx, logger)
+	require.NoError(t, err)
+	require.ElementsMatch(t, httpMissing, fakeMissing)
+	require.Len(t, fakeMissing, 2)
+	t.Log("Missing exit messages matched")
+
+	// Upload an exit for one of the node's validators
+	exitData := []common.EncryptedExitData{
+		testkit.GenerateEncryptedExit(t, 1, id.Recipient().String()),
+	}
+	err = requireSameChange(t, mgr,
+		func() (struct{}, error) {
+			return struct{}{}, httpClient.StakeWise.Validators_Patch(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, exitData)
+		},
+		func() (struct{}, error) {
+			return struct{}{}, fakeClient.StakeWise.Validators_Patch(ctx, logger, testkit.Network, testkit.StakeWiseVaultAddress, exitData)
+		},
+	)
+	require.NoError(t, err)
+	fakeMissing, err = fakeClient.StakeWise.NewExitMessageClient(testkit.Network, testkit.StakeWiseVaultAddress).GetValidatorsMissingExitMessages(ctx, logger)
+	require.NoError(t, err)
+	require.Len(t, fakeMissing, 1)
+}
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Minimal dev chain stub that serves the Constellation nonces and Rocket Pool minipool pubkeys
+type constellationChainStub struct {
+	contracts       abi.ABI
+	whitelist       ethcommon.Address
+	superNode       ethcommon.Address
+	minipoolManager ethcommon.Address
+	whitelistNonces map[ethcommon.Address]uint64
+	superNodeNonces map[ethcommon.Address]uint64
+	minipoolPubkeys map[ethcommon.Address]beacon.ValidatorPubkey
+}
+
+func (s *constellationChainStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil || request.Method != "eth_call" || len(request.Params) == 0 {
+		http.Error(w, "unsupported request", http.StatusBadRequest)
+		return
+	}
+	var call struct {
+		To    ethcommon.Address `json:"to"`
+		Data  hexutil.Bytes     `json:"data"`
+		Input hexutil.Bytes     `json:"input"`
+	}
+	err = json.Unmarshal(request.Params[0], &call)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data := call.Input
+	if len(data) == 0 {
+		data = call.Data
+	}
+	method, err := s.contracts.MethodById(data[:4])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	address := args[0].(ethcommon.Address)
+
+	var output []byte
+	switch {
+	case method.Name == "nonces" && call.To == s.whitelist:
+		output, err = method.Outputs.Pack(new(big.Int).SetUint64(s.whitelistNonces[address]))
+	case method.Name == "nonces" && call.To == s.superNode:
+		output, err = method.Outputs.Pack(new(big.Int).SetUint64(s.superNodeNonces[address]))
+	case method.Name == "getMinipoolPubkey" && call.To == s.minipoolManager:
+		pubkey, exists := s.minipoolPubkeys[address]
+		pubkeyBytes := []byte{}
+		if exists {
+			pubkeyBytes = pubkey[:]
+		}
+		output, err = method.Outputs.Pack(pubkeyBytes)
+	default:
+		http.Error(w, "unsupported call", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result":  hexutil.Encode(output),
+	})
+}
+
+func TestRpcConstellationBackend(t *testing.T) {
+	// Set up the chain
+	contracts, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"nonces","stateMutability":"view","inputs":[{"name":"","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"getMinipoolPubkey","stateMutability":"view","inputs":[{"name":"_minipoolAddress","type":"address"}],"outputs":[{"name":"","type":"bytes"}]}
+	]`))
+	require.NoError(t, err)
+	nodeAddress := ethcommon.HexToAddress("0x90de")
+	minipool := ethcommon.HexToAddress("0x0303")
+	pubkey := beacon.ValidatorPubkey(testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress).PublicKey)
+	stub := &constellationChainStub{
+		contracts:       contracts,
+		whitelist:       testkit.WhitelistAddress,
+		superNode:       testkit.SuperNodeAddress,
+		minipoolManager: ethcommon.HexToAddress("0x3333"),
+		whitelistNonces: map[ethcommon.Address]uint64{nodeAddress: 1},
+		superNodeNonces: map[ethcommon.Address]uint64{nodeAddress: 4},
+		minipoolPubkeys: map[ethcommon.Address]beacon.ValidatorPubkey{minipool: pubkey},
+	}
+	rpcServer := httptest.NewServer(stub)
+	defer rpcServer.Close()
+
+	// Point a deployment at it
+	database := db.NewDatabase(slog.Default())
+	deployment := database.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	backend, err := db.NewRpcConstellationBackend(context.Background(), rpcServer.URL, testkit.WhitelistAddress, testkit.SuperNodeAddress, stub.minipoolManager)
+	require.NoError(t, err)
+	defer backend.Close()
+	deployment.SetChainBackend(backend)
+
+	// Read the nonces
+	nonce, err := deployment.TryGetWhitelistNonce(nodeAddress)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), nonce)
+	nonce, err = deployment.TryGetSuperNodeNonce(nodeAddress)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), nonce)
+	t.Log("Read nonces from the chain")
+
+	// Read the minipool pubkeys
+	minipoolPubkey, exists, err := backend.GetMinipoolPubkey(minipool)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, pubkey, minipoolPubkey)
+	_, exists, err = backend.GetMinipoolPubkey(ethcommon.HexToAddress("0x0404"))
+	require.NoError(t, err)
+	require.False(t, exists)
+	t.Log("Read minipool pubkeys from the chain")
+
+	// The chain owns the state, so writes are rejected
+	require.ErrorIs(t, deployment.TryIncrementSuperNodeNonce(nodeAddress), db.ErrReadOnlyBackend)
+	require.ErrorIs(t, deployment.TrySetValidatorInfoForMinipool(minipool, pubkey), db.ErrReadOnlyBackend)
+	t.Log("Writes were rejected by the read-only backend")
+
+	// Clones should keep reading from the chain
+	clone := database.Clone()
+	require.Same(t, backend, clone.Constellation.GetDeployment(testkit.Network).GetChainBackend())
+
+	// Replacing the backend should close its connection
+	deployment.SetChainBackend(db.NewMemoryConstellationBackend())
+	_, err = backend.GetWhitelistNonce(nodeAddress)
+	require.ErrorIs(t, err, db.ErrBackendClosed)
+	t.Log("Replaced backend was closed")
+}
+
+func TestReadingValidatorsDoesntRecordThem(t *testing.T) {
+	// Give a node a minipool
+	database := testkit.ProvisionFullDatabase(t, slog.Default(), false)
+	var node *db.Node
+	for _, userNode := range database.Core.GetUser(testkit.User1Email).GetNodes() {
+		node = userNode
+	}
+	deployment := database.Constellation.GetDeployment(testkit.Network)
+	adminKey, err := testkit.GetEthPrivateKey(9)
+	require.NoError(t, err)
+	deployment.SetAdminPrivateKey(adminKey)
+	_, err = deployment.GetWhitelistSignature(node.Address)
+	require.NoError(t, err)
+	minipool := ethcommon.HexToAddress("0x90de")
+	_, err = deployment.GetMinipoolDepositSignature(node.Address, minipool, big.NewInt(1))
+	require.NoError(t, err)
+
+	// Assign its validator on the chain, without going through the deployment
+	pubkey := beacon.ValidatorPubkey(testkit.GenerateDepositData(t, 6, testkit.StakeWiseVaultAddress).PublicKey)
+	require.NoError(t, deployment.GetChainBackend().SetMinipoolPubkey(minipool, pubkey))
+
+	// Reading the state at different times shouldn't record the validator
+	getRegistrationTime := func() time.Time {
+		state, err := database.GetState(api.StateFilter{Pubkey: &pubkey})
+		require.NoError(t, err)
+		return *state.Constellation[0].Nodes[0].Minipools[0].RegistrationTime
+	}
+	firstRead := getRegistrationTime()
+	database.Beacon.AdvanceSlots(10)
+	require.Equal(t, firstRead, getRegistrationTime())
+	require.Equal(t, firstRead, deployment.GetValidator(node, pubkey).RegistrationTime)
+	bytes, err := database.Serialize()
+	require.NoError(t, err)
+	var export struct {
+		Constellation []struct {
+			Validators []json.RawMessage `json:"validators"`
+		} `json:"constellation"`
+	}
+	require.NoError(t, json.Unmarshal(bytes, &export))
+	require.Empty(t, export.Constellation[0].Validators)
+	t.Log("Reads didn't record the validator")
+
+	// Uploading an exit message should record it
+	exitData := testkit.GenerateSignedExit(t, 6)
+	require.NoError(t, deployment.HandleSignedExitUpload(node, []common.ExitData{exitData}))
+	require.Equal(t, &exitData.ExitMessage, deployment.GetValidator(node, pubkey).GetExitMessage())
+	t.Log("Exit message upload recorded the validator")
+}
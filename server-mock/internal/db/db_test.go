@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/stretchr/testify/assert"
 )
@@ -13,7 +13,9 @@ import (
 func TestDatabaseClone(t *testing.T) {
 	// Set up a database
 	logger := slog.Default()
-	db := ProvisionFullDatabase(t, logger, true)
+	db := testkit.ProvisionFullDatabase(t, logger, true)
+	db.Beacon.AddValidator(beacon.ValidatorPubkey(testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress).PublicKey))
+	db.Beacon.AdvanceEpochs(1)
 
 	// Clone the database
 	clone := db.Clone()
@@ -30,8 +32,8 @@ func TestDatabaseClone(t *testing.T) {
 	t.Log("Clone has identical contents to the original database but different pointers")
 
 	// Get the first pubkey from user 2 that hasn't been uploaded yet
-	user := db.Core.GetUser(test.User2Email)
-	vault := db.StakeWise.GetDeployment(test.Network).GetVault(test.StakeWiseVaultAddress)
+	user := db.Core.GetUser(testkit.User2Email)
+	vault := db.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress)
 	var pubkey beacon.ValidatorPubkey
 	found := false
 	for _, node := range user.GetNodes() {
@@ -59,7 +61,7 @@ func TestDatabaseClone(t *testing.T) {
 	t.Log("Marked deposit data uploaded for StakeWise vault")
 
 	// Make sure the clone didn't get the update
-	if clone.StakeWise.GetDeployment(test.Network).GetVault(test.StakeWiseVaultAddress).UploadedData[pubkey] {
+	if clone.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress).UploadedData[pubkey] {
 		t.Fatalf("Clone got the update")
 	}
 	t.Log("Clone wasn't updated, as expected")
@@ -81,6 +83,13 @@ func compareDatabases(t *testing.T, db *db.Database, clone *db.Database) {
 		}
 	}
 
+	// Compare the Beacon chain
+	assert.Equal(t, db.Beacon.GetCurrentSlot(), clone.Beacon.GetCurrentSlot())
+	assert.Equal(t, db.Beacon.GetValidators(), clone.Beacon.GetValidators())
+	for i, validator := range db.Beacon.GetValidators() {
+		assert.NotSame(t, validator, clone.Beacon.GetValidators()[i])
+	}
+
 	// Compare users
 	assert.Equal(t, db.Core.GetUsers(), clone.Core.GetUsers())
 
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseSerialization(t *testing.T) {
+	// Set up a database with every part populated
+	logger := slog.Default()
+	database := testkit.ProvisionFullDatabase(t, logger, true)
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	database.SetSecretEncryptionIdentity(id)
+	database.Eth.AddDeposit(testkit.GenerateDepositData(t, 5, testkit.StakeWiseVaultAddress))
+	database.Beacon.AdvanceEpochs(2)
+	_ = database.Core.CreateSession()
+
+	// Whitelist a node for Constellation and give it a minipool with an exit message
+	var node *db.Node
+	for _, userNode := range database.Core.GetUser(testkit.User1Email).GetNodes() {
+		node = userNode
+	}
+	deployment := database.Constellation.GetDeployment(testkit.Network)
+	adminKey, err := testkit.GetEthPrivateKey(9)
+	require.NoError(t, err)
+	deployment.SetAdminPrivateKey(adminKey)
+	_, err = deployment.GetWhitelistSignature(node.Address)
+	require.NoError(t, err)
+	minipool := ethcommon.HexToAddress("0x90de")
+	_, err = deployment.GetMinipoolDepositSignature(node.Address, minipool, big.NewInt(1))
+	require.NoError(t, err)
+	pubkey := beacon.ValidatorPubkey(testkit.GenerateDepositData(t, 6, testkit.StakeWiseVaultAddress).PublicKey)
+	deployment.SetValidatorInfoForMinipool(minipool, pubkey)
+	deployment.IncrementSuperNodeNonce(node.Address)
+	validator := deployment.GetValidator(node, pubkey)
+	exitData := testkit.GenerateSignedExit(t, 6)
+	validator.SetExitMessage(&exitData.ExitMessage)
+	vaultAddress := testkit.StakeWiseVaultAddress
+	require.NoError(t, database.Core.GetUser(testkit.User2Email).SetPermission(api.Permission{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: testkit.Network,
+		Vault:      &vaultAddress,
+	}, false))
+	t.Log("Provisioned database")
+
+	// Serialize and restore it
+	bytes, err := database.Serialize()
+	require.NoError(t, err)
+	restored, err := db.DeserializeDatabase(logger, bytes)
+	require.NoError(t, err)
+	t.Log("Serialized and restored database")
+
+	// Make sure the state and internals survived the round trip
+	requireSameState(t, database, restored)
+	require.Equal(t, id.String(), restored.GetSecretEncryptionIdentity().String())
+	require.Equal(t, crypto.FromECDSA(adminKey), crypto.FromECDSA(restored.Constellation.GetDeployment(testkit.Network).GetAdminPrivateKey()))
+	require.Equal(t, database.Eth.GetDeposits(), restored.Eth.GetDeposits())
+	restoredBytes, err := restored.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, string(bytes), string(restoredBytes))
+	t.Log("Restored database matches the original")
+
+	// Make sure the restored deposit tree keeps producing the same roots
+	depositData := testkit.GenerateDepositData(t, 7, testkit.StakeWiseVaultAddress)
+	require.Equal(t, database.Eth.AddDeposit(depositData), restored.Eth.AddDeposit(depositData))
+	t.Log("Restored deposit contract tree is intact")
+
+	// Make sure the restored exit message is still there
+	restoredNode, _ := restored.Core.GetNode(node.Address)
+	restoredValidator := restored.Constellation.GetDeployment(testkit.Network).GetValidator(restoredNode, pubkey)
+	require.Equal(t, &exitData.ExitMessage, restoredValidator.GetExitMessage())
+}
+
+// Make sure two databases report the same state
+func requireSameState(t *testing.T, expected *db.Database, actual *db.Database) {
+	expectedState, err := expected.GetState(api.StateFilter{})
+	require.NoError(t, err)
+	actualState, err := actual.GetState(api.StateFilter{})
+	require.NoError(t, err)
+	expectedJson, err := json.Marshal(expectedState)
+	require.NoError(t, err)
+	actualJson, err := json.Marshal(actualState)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJson), string(actualJson))
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure revoking a permission also denies everything beneath it
+func TestPermissionHierarchy(t *testing.T) {
+	logger := slog.Default()
+	database := testkit.ProvisionFullDatabase(t, logger, false)
+	user := database.Core.GetUser(testkit.User1Email)
+	vaultAddress := testkit.StakeWiseVaultAddress
+	module := api.Permission{Module: api.PermissionModule_StakeWise}
+	deployment := api.Permission{Module: api.PermissionModule_StakeWise, Deployment: testkit.Network}
+	vault := api.Permission{Module: api.PermissionModule_StakeWise, Deployment: testkit.Network, Vault: &vaultAddress}
+
+	// Everything is permitted by default
+	require.True(t, user.HasPermission(module))
+	require.True(t, user.HasPermission(deployment))
+	require.True(t, user.HasPermission(vault))
+
+	// Revoking the vault only affects the vault
+	require.NoError(t, user.SetPermission(vault, false))
+	require.True(t, user.HasPermission(deployment))
+	require.False(t, user.HasPermission(vault))
+	require.Equal(t, []api.Permission{vault}, user.GetRevokedPermissions())
+	require.NoError(t, user.SetPermission(vault, true))
+	require.True(t, user.HasPermission(vault))
+	t.Log("Vault permission toggled correctly")
+
+	// Revoking the module affects its deployments and vaults, but not the other module
+	require.NoError(t, user.SetPermission(module, false))
+	require.False(t, user.HasPermission(deployment))
+	require.False(t, user.HasPermission(vault))
+	require.True(t, user.HasPermission(api.Permission{Module: api.PermissionModule_Constellation, Deployment: testkit.Network}))
+	require.True(t, database.Core.GetUser(testkit.User2Email).HasPermission(vault))
+	t.Log("Module permission covers its deployments and vaults")
+
+	// Invalid permissions are rejected
+	require.Error(t, user.SetPermission(api.Permission{Module: "unknown"}, false))
+	require.Error(t, user.SetPermission(api.Permission{Module: api.PermissionModule_Constellation, Deployment: testkit.Network, Vault: &vaultAddress}, false))
+	require.Error(t, user.SetPermission(api.Permission{Module: api.PermissionModule_StakeWise, Vault: &vaultAddress}, false))
+}
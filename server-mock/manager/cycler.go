@@ -0,0 +1,240 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// How often the cycler checks for policies and activations that are due
+	cyclerPollInterval time.Duration = 20 * time.Millisecond
+)
+
+// Cycles StakeWise deposit data sets in the background for the vaults that have a cycle policy. Policies aren't part of
+// the database, so they keep applying to whichever database the manager is using.
+type DepositDataCycler struct {
+	manager  *NodeSetMockManager
+	logger   *slog.Logger
+	policies map[cycleKey]*cycleState
+	lock     sync.Mutex
+
+	// Used to stop the background loop, which only runs while there are policies
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Identifies a vault with a cycle policy
+type cycleKey struct {
+	deployment string
+	vault      ethcommon.Address
+}
+
+// The progress of a vault's cycle policy
+type cycleState struct {
+	policy      api.StakeWiseCyclePolicy
+	cycles      int
+	lastCycle   time.Time
+	nextCycle   time.Time
+	activations []pendingActivation
+}
+
+// A cycled set whose validators will be marked as active
+type pendingActivation struct {
+	time time.Time
+	set  []beacon.ExtendedDepositData
+}
+
+// Creates a new cycler for the manager's databases
+func newDepositDataCycler(manager *NodeSetMockManager, logger *slog.Logger) *DepositDataCycler {
+	return &DepositDataCycler{
+		manager:  manager,
+		logger:   logger,
+		policies: map[cycleKey]*cycleState{},
+	}
+}
+
+// Set the cycle policy for a vault, replacing the one it already has. The first cycle happens one interval from now.
+func (c *DepositDataCycler) SetPolicy(deployment string, vault ethcommon.Address, policy api.StakeWiseCyclePolicy) error {
+	if policy.Interval <= 0 {
+		return fmt.Errorf("cycle interval must be positive")
+	}
+	if policy.UserLimit <= 0 {
+		return fmt.Errorf("user limit must be positive")
+	}
+	if policy.MinPending < 0 {
+		return fmt.Errorf("min pending count can't be negative")
+	}
+	if policy.ActivationDelay < 0 {
+		return fmt.Errorf("activation delay can't be negative")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.policies[cycleKey{deployment: deployment, vault: vault}] = &cycleState{
+		policy:    policy,
+		nextCycle: time.Now().Add(time.Duration(policy.Interval)),
+	}
+	if c.stop == nil {
+		c.stop = make(chan struct{})
+		c.done = make(chan struct{})
+		go c.loop(c.stop, c.done)
+	}
+	return nil
+}
+
+// Remove the cycle policy for a vault, along with any activations it hasn't done yet. Cycling stops in the background
+// once every policy has been removed.
+func (c *DepositDataCycler) ClearPolicy(deployment string, vault ethcommon.Address) error {
+	c.lock.Lock()
+	key := cycleKey{deployment: deployment, vault: vault}
+	if _, exists := c.policies[key]; !exists {
+		c.lock.Unlock()
+		return fmt.Errorf("vault [%s] in deployment [%s] doesn't have a cycle policy", vault.Hex(), deployment)
+	}
+	delete(c.policies, key)
+	c.lock.Unlock()
+	return nil
+}
+
+// Get the progress of each vault's cycle policy, sorted by deployment and vault
+func (c *DepositDataCycler) GetPolicies() []api.StakeWiseCyclePolicyStatus {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	statuses := []api.StakeWiseCyclePolicyStatus{}
+	for key, state := range c.policies {
+		status := api.StakeWiseCyclePolicyStatus{
+			Deployment:         key.deployment,
+			Vault:              key.vault,
+			Policy:             state.policy,
+			Cycles:             state.cycles,
+			NextCycle:          state.nextCycle,
+			PendingActivations: len(state.activations),
+		}
+		if !state.lastCycle.IsZero() {
+			lastCycle := state.lastCycle
+			status.LastCycle = &lastCycle
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i int, j int) bool {
+		if statuses[i].Deployment != statuses[j].Deployment {
+			return statuses[i].Deployment < statuses[j].Deployment
+		}
+		return bytes.Compare(statuses[i].Vault[:], statuses[j].Vault[:]) < 0
+	})
+	return statuses
+}
+
+// Stop cycling in the background. Policies are kept, and setting one starts cycling again.
+func (c *DepositDataCycler) Stop() {
+	c.lock.Lock()
+	stop := c.stop
+	done := c.done
+	c.stop = nil
+	c.done = nil
+	c.lock.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// Cycle sets and activate validators as they come due, until stopped or every policy has been removed
+func (c *DepositDataCycler) loop(stop chan struct{}, done chan struct{}) {
+	ticker := time.NewTicker(cyclerPollInterval)
+	defer func() {
+		ticker.Stop()
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if !c.tick(now) {
+				return
+			}
+		}
+	}
+}
+
+// Run the cycles and activations that are due. Returns false, and marks the loop as stopped, if there aren't any
+// policies left.
+func (c *DepositDataCycler) tick(now time.Time) bool {
+	c.manager.Lock()
+	defer c.manager.Unlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.policies) == 0 {
+		c.stop = nil
+		c.done = nil
+		return false
+	}
+
+	db := c.manager.GetDatabase()
+	for key, state := range c.policies {
+		// Activate the validators in sets that have been cycled long enough
+		for len(state.activations) > 0 && !now.Before(state.activations[0].time) {
+			activation := state.activations[0]
+			state.activations = state.activations[1:]
+			vault := getCycledVault(db, key)
+			if vault == nil {
+				continue
+			}
+			vault.MarkValidatorsRegistered(activation.set)
+			c.logger.Info("Marked cycled validators as active", "vault", key.vault.Hex(), "count", len(activation.set))
+		}
+
+		// Cycle a new set if it's time and there's enough new deposit data
+		if now.Before(state.nextCycle) {
+			continue
+		}
+		state.nextCycle = now.Add(time.Duration(state.policy.Interval))
+		vault := getCycledVault(db, key)
+		if vault == nil {
+			continue
+		}
+		pending := vault.GetPendingDepositDataCount()
+		if pending == 0 || pending < state.policy.MinPending {
+			continue
+		}
+		set := vault.CreateNewDepositDataSet(state.policy.UserLimit)
+		vault.UploadDepositDataToStakeWise(set)
+		vault.MarkDepositDataSetUploaded(set)
+		state.cycles++
+		state.lastCycle = now
+		state.activations = append(state.activations, pendingActivation{
+			time: now.Add(time.Duration(state.policy.ActivationDelay)),
+			set:  set,
+		})
+		c.logger.Info("Cycled deposit data set",
+			"deployment", key.deployment,
+			"vault", key.vault.Hex(),
+			"version", vault.LatestDepositDataSetIndex,
+			"count", len(set),
+		)
+	}
+	return true
+}
+
+// Get the vault a policy applies to, or nil if it's no longer in the database
+func getCycledVault(database *db.Database, key cycleKey) *db.StakeWiseVault {
+	deployment := database.StakeWise.GetDeployment(key.deployment)
+	if deployment == nil {
+		return nil
+	}
+	return deployment.GetVault(key.vault)
+}
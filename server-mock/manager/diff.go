@@ -0,0 +1,186 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+)
+
+// Flattened view of a database state: entity paths, and field paths mapped to their values
+type flatState struct {
+	entities map[string]bool
+	fields   map[string]string
+}
+
+// Add an entity and return its path
+func (s *flatState) entity(path ...string) string {
+	entityPath := strings.Join(path, "/")
+	s.entities[entityPath] = true
+	return entityPath
+}
+
+// Add a field of an entity
+func (s *flatState) field(entityPath string, name string, value any) {
+	s.fields[entityPath+"/"+name] = fmt.Sprint(value)
+}
+
+// Flatten a database state so it can be compared
+func flattenState(state api.StateData) *flatState {
+	s := &flatState{
+		entities: map[string]bool{},
+		fields:   map[string]string{},
+	}
+
+	for _, user := range state.Users {
+		userPath := s.entity("users", user.Email)
+		for _, node := range user.Nodes {
+			nodePath := s.entity(userPath, "nodes", node.Address.Hex())
+			s.field(nodePath, "registered", node.Registered)
+		}
+		for _, permission := range user.RevokedPermissions {
+			permissionPath := []string{userPath, "revokedPermissions", permission.Module}
+			if permission.Deployment != "" {
+				permissionPath = append(permissionPath, permission.Deployment)
+			}
+			if permission.Vault != nil {
+				permissionPath = append(permissionPath, permission.Vault.Hex())
+			}
+			s.entity(permissionPath...)
+		}
+	}
+	for _, session := range state.Sessions {
+		sessionPath := s.entity("sessions", session.Token)
+		s.field(sessionPath, "nodeAddress", session.NodeAddress.Hex())
+		s.field(sessionPath, "loggedIn", session.LoggedIn)
+	}
+
+	for _, deployment := range state.StakeWise {
+		deploymentPath := s.entity("stakewise", deployment.ID)
+		s.field(deploymentPath, "chainId", deployment.ChainID)
+		for _, vault := range deployment.Vaults {
+			vaultPath := s.entity(deploymentPath, "vaults", vault.Address.Hex())
+			s.field(vaultPath, "name", vault.Name)
+			s.field(vaultPath, "maxValidatorsPerUser", vault.MaxValidatorsPerUser)
+			s.field(vaultPath, "balance", vault.Balance)
+			s.field(vaultPath, "latestDepositDataSetIndex", vault.LatestDepositDataSetIndex)
+			for _, validator := range vault.Validators {
+				validatorPath := s.entity(vaultPath, "validators", validator.Pubkey.HexWithPrefix())
+				s.field(validatorPath, "nodeAddress", validator.NodeAddress.Hex())
+				s.field(validatorPath, "uploadedToStakeWise", validator.UploadedToStakeWise)
+				s.field(validatorPath, "depositDataUsed", validator.DepositDataUsed)
+				s.field(validatorPath, "markedActive", validator.MarkedActive)
+				s.field(validatorPath, "hasDepositEvent", validator.HasDepositEvent)
+				s.field(validatorPath, "beaconDepositRoot", validator.BeaconDepositRoot.Hex())
+				s.field(validatorPath, "registrationTime", validator.RegistrationTime.UTC().Format(time.RFC3339))
+				s.field(validatorPath, "status", validator.Status)
+				s.field(validatorPath, "exitMessageUploaded", validator.ExitMessageUploaded)
+			}
+		}
+	}
+
+	for _, deployment := range state.Constellation {
+		deploymentPath := s.entity("constellation", deployment.ID)
+		s.field(deploymentPath, "chainId", deployment.ChainID)
+		s.field(deploymentPath, "minipoolLimit", deployment.MinipoolLimit)
+		s.field(deploymentPath, "requireExitMessages", deployment.RequireExitMessages)
+		for email, limit := range deployment.UserMinipoolLimits {
+			s.field(deploymentPath, "userMinipoolLimits/"+email, limit)
+		}
+		for address, limit := range deployment.NodeMinipoolLimits {
+			s.field(deploymentPath, "nodeMinipoolLimits/"+address.Hex(), limit)
+		}
+		for _, entry := range deployment.Whitelist {
+			entryPath := s.entity(deploymentPath, "whitelist", entry.User)
+			s.field(entryPath, "nodeAddress", entry.NodeAddress.Hex())
+		}
+		for _, node := range deployment.Nodes {
+			nodePath := s.entity(deploymentPath, "nodes", node.Address.Hex())
+			s.field(nodePath, "whitelistNonce", node.WhitelistNonce)
+			s.field(nodePath, "superNodeNonce", node.SuperNodeNonce)
+			for _, minipool := range node.Minipools {
+				minipoolPath := s.entity(nodePath, "minipools", minipool.Address.Hex())
+				if minipool.Pubkey != nil {
+					s.field(minipoolPath, "pubkey", minipool.Pubkey.HexWithPrefix())
+					s.field(minipoolPath, "beaconDepositRoot", minipool.BeaconDepositRoot.Hex())
+					s.field(minipoolPath, "registrationTime", minipool.RegistrationTime.UTC().Format(time.RFC3339))
+					s.field(minipoolPath, "status", minipool.Status)
+				}
+				s.field(minipoolPath, "exitMessageUploaded", minipool.ExitMessage != nil)
+			}
+		}
+	}
+
+	s.field("eth", "blockNumber", state.Eth.BlockNumber)
+	s.field("eth", "depositCount", state.Eth.DepositCount)
+	s.field("eth", "depositRoot", state.Eth.DepositRoot.Hex())
+	s.field("beacon", "currentSlot", state.Beacon.CurrentSlot)
+	for _, validator := range state.Beacon.Validators {
+		validatorPath := s.entity("beacon", "validators", validator.Pubkey.HexWithPrefix())
+		s.field(validatorPath, "index", validator.Index)
+		s.field(validatorPath, "status", validator.Status)
+		s.field(validatorPath, "activationEpoch", validator.ActivationEpoch)
+		s.field(validatorPath, "exitEpoch", validator.ExitEpoch)
+		s.field(validatorPath, "slashed", validator.Slashed)
+	}
+	return s
+}
+
+// Get the changes between two database states, sorted by path
+func diffStates(from api.StateData, to api.StateData) []api.StateChange {
+	fromFlat := flattenState(from)
+	toFlat := flattenState(to)
+	changes := []api.StateChange{}
+
+	// Added and removed entities
+	changedEntities := []string{}
+	for path := range fromFlat.entities {
+		if !toFlat.entities[path] {
+			changes = append(changes, api.StateChange{Path: path, Kind: api.StateChangeKind_Removed})
+			changedEntities = append(changedEntities, path)
+		}
+	}
+	for path := range toFlat.entities {
+		if !fromFlat.entities[path] {
+			changes = append(changes, api.StateChange{Path: path, Kind: api.StateChangeKind_Added})
+			changedEntities = append(changedEntities, path)
+		}
+	}
+	isInChangedEntity := func(path string) bool {
+		for _, entityPath := range changedEntities {
+			if strings.HasPrefix(path, entityPath+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Field changes
+	for path, oldValue := range fromFlat.fields {
+		newValue, exists := toFlat.fields[path]
+		if !exists {
+			changes = append(changes, api.StateChange{Path: path, Kind: api.StateChangeKind_Removed, Old: oldValue})
+		} else if newValue != oldValue {
+			changes = append(changes, api.StateChange{Path: path, Kind: api.StateChangeKind_Changed, Old: oldValue, New: newValue})
+		}
+	}
+	for path, newValue := range toFlat.fields {
+		if _, exists := fromFlat.fields[path]; !exists {
+			changes = append(changes, api.StateChange{Path: path, Kind: api.StateChangeKind_Added, New: newValue})
+		}
+	}
+
+	// Drop the fields and nested entities of added and removed entities
+	filtered := make([]api.StateChange, 0, len(changes))
+	for _, change := range changes {
+		if !isInChangedEntity(change.Path) {
+			filtered = append(filtered, change)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Path < filtered[j].Path
+	})
+	return filtered
+}
@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+)
+
+const (
+	// Default number of requests the journal holds before dropping the oldest ones
+	DefaultJournalCapacity int = 1000
+)
+
+// Bounded record of the API requests the mock has served. It isn't part of the database, so snapshots don't affect it.
+type RequestJournal struct {
+	capacity int
+	entries  []api.JournalEntry
+	nextID   uint64
+	lock     sync.Mutex
+}
+
+// Create a new request journal
+func NewRequestJournal(capacity int) *RequestJournal {
+	return &RequestJournal{
+		capacity: capacity,
+		entries:  []api.JournalEntry{},
+		nextID:   1,
+	}
+}
+
+// Record a request, dropping the oldest one if the journal is full. Returns the ID assigned to the entry.
+func (j *RequestJournal) Record(entry api.JournalEntry) uint64 {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entry.ID = j.nextID
+	j.nextID++
+	j.entries = append(j.entries, entry)
+	j.trim()
+	return entry.ID
+}
+
+// Get the recorded requests that match the filter, oldest first
+func (j *RequestJournal) GetEntries(filter api.JournalFilter) []api.JournalEntry {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entries := []api.JournalEntry{}
+	for _, entry := range j.entries {
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Get the recorded requests with IDs after the provided one, oldest first
+func (j *RequestJournal) GetEntriesAfter(id uint64) []api.JournalEntry {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entries := []api.JournalEntry{}
+	for _, entry := range j.entries {
+		if entry.ID > id {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Get the ID of the most recently recorded request, or 0 if none have been recorded
+func (j *RequestJournal) GetLastID() uint64 {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.nextID - 1
+}
+
+// Remove all of the recorded requests
+func (j *RequestJournal) Clear() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.entries = []api.JournalEntry{}
+}
+
+// Get the max number of requests the journal holds
+func (j *RequestJournal) GetCapacity() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.capacity
+}
+
+// Set the max number of requests the journal holds, dropping the oldest ones if it's already over the limit. The
+// capacity must be at least 1.
+func (j *RequestJournal) SetCapacity(capacity int) error {
+	if capacity < 1 {
+		return fmt.Errorf("journal capacity must be at least 1, but was %d", capacity)
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.capacity = capacity
+	j.trim()
+	return nil
+}
+
+// Drop the oldest entries until the journal is within its capacity
+func (j *RequestJournal) trim() {
+	if len(j.entries) <= j.capacity {
+		return
+	}
+	excess := len(j.entries) - j.capacity
+	j.entries = append([]api.JournalEntry{}, j.entries[excess:]...)
+}
@@ -3,6 +3,7 @@ package manager
 import (
 	"fmt"
 	"log/slog"
+	"sync"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
@@ -11,10 +12,13 @@ import (
 // Mock manager for the nodeset.io service
 type NodeSetMockManager struct {
 	database *db.Database
+	journal  *RequestJournal
+	cycler   *DepositDataCycler
 
 	// Internal fields
-	snapshots map[string]*db.Database
+	snapshots map[string]*snapshot
 	logger    *slog.Logger
+	lock      sync.Mutex
 }
 
 // Used when a deployment is not found
@@ -53,11 +57,14 @@ func (e *ErrInvalidVault) Error() string {
 
 // Creates a new manager
 func NewNodeSetMockManager(logger *slog.Logger) *NodeSetMockManager {
-	return &NodeSetMockManager{
+	m := &NodeSetMockManager{
 		database:  db.NewDatabase(logger),
-		snapshots: map[string]*db.Database{},
+		journal:   NewRequestJournal(DefaultJournalCapacity),
+		snapshots: map[string]*snapshot{},
 		logger:    logger,
 	}
+	m.cycler = newDepositDataCycler(m, logger)
+	return m
 }
 
 // Get the database the manager is currently using
@@ -65,24 +72,28 @@ func (m *NodeSetMockManager) GetDatabase() *db.Database {
 	return m.database
 }
 
-// Set the database for the manager directly if you need to custom provision it
-func (m *NodeSetMockManager) SetDatabase(db *db.Database) {
-	m.database = db
+// Get the journal of API requests the mock has served
+func (m *NodeSetMockManager) GetJournal() *RequestJournal {
+	return m.journal
 }
 
-// Take a snapshot of the current database state
-func (m *NodeSetMockManager) TakeSnapshot(name string) {
-	m.snapshots[name] = m.database.Clone()
-	m.logger.Info("Took DB snapshot", "name", name)
+// Lock the manager so the caller has exclusive access to its database. Request handlers hold the lock while they
+// run, so only background tasks need to call this.
+func (m *NodeSetMockManager) Lock() {
+	m.lock.Lock()
 }
 
-// Revert to a snapshot of the database state
-func (m *NodeSetMockManager) RevertToSnapshot(name string) error {
-	snapshot, exists := m.snapshots[name]
-	if !exists {
-		return fmt.Errorf("snapshot with name [%s] does not exist", name)
-	}
-	m.database = snapshot.Clone()
-	m.logger.Info("Reverted to DB snapshot", "name", name)
-	return nil
+// Unlock the manager after a call to Lock
+func (m *NodeSetMockManager) Unlock() {
+	m.lock.Unlock()
+}
+
+// Get the cycler that runs the StakeWise deposit data set cycle policies
+func (m *NodeSetMockManager) GetDepositDataCycler() *DepositDataCycler {
+	return m.cycler
+}
+
+// Set the database for the manager directly if you need to custom provision it
+func (m *NodeSetMockManager) SetDatabase(db *db.Database) {
+	m.database = db
 }
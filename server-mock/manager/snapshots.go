@@ -0,0 +1,142 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+)
+
+// A named copy of the database
+type snapshot struct {
+	database *db.Database
+	time     time.Time
+}
+
+// Take a snapshot of the current database state
+func (m *NodeSetMockManager) TakeSnapshot(name string) {
+	m.snapshots[name] = &snapshot{
+		database: m.database.Clone(),
+		time:     time.Now(),
+	}
+	m.logger.Info("Took DB snapshot", "name", name)
+}
+
+// Revert to a snapshot of the database state
+func (m *NodeSetMockManager) RevertToSnapshot(name string) error {
+	snapshot, exists := m.snapshots[name]
+	if !exists {
+		return fmt.Errorf("snapshot with name [%s] does not exist", name)
+	}
+	m.database = snapshot.database.Clone()
+	m.logger.Info("Reverted to DB snapshot", "name", name)
+	return nil
+}
+
+// Get info about the snapshots, oldest first
+func (m *NodeSetMockManager) GetSnapshots() []api.SnapshotInfo {
+	snapshots := make([]api.SnapshotInfo, 0, len(m.snapshots))
+	for name, snapshot := range m.snapshots {
+		snapshots = append(snapshots, api.SnapshotInfo{
+			Name: name,
+			Time: snapshot.time,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Time.Equal(snapshots[j].Time) {
+			return snapshots[i].Name < snapshots[j].Name
+		}
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	return snapshots
+}
+
+// Delete a snapshot
+func (m *NodeSetMockManager) DeleteSnapshot(name string) error {
+	_, exists := m.snapshots[name]
+	if !exists {
+		return fmt.Errorf("snapshot with name [%s] does not exist", name)
+	}
+	delete(m.snapshots, name)
+	m.logger.Info("Deleted DB snapshot", "name", name)
+	return nil
+}
+
+// Write a snapshot to a file so it can be imported later, possibly by another instance of the mock. The file is only
+// readable by its owner, since it has the database's private keys.
+func (m *NodeSetMockManager) ExportSnapshot(name string, path string) error {
+	snapshot, exists := m.snapshots[name]
+	if !exists {
+		return fmt.Errorf("snapshot with name [%s] does not exist", name)
+	}
+	bytes, err := snapshot.database.Serialize()
+	if err != nil {
+		return fmt.Errorf("error serializing snapshot [%s]: %w", name, err)
+	}
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing snapshot [%s] to [%s]: %w", name, tempPath, err)
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("error replacing snapshot file [%s]: %w", path, err)
+	}
+	m.logger.Info("Exported DB snapshot", "name", name, "path", path)
+	return nil
+}
+
+// Load a snapshot from a file written by ExportSnapshot, replacing any existing snapshot with the same name
+func (m *NodeSetMockManager) ImportSnapshot(name string, path string) error {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading snapshot from [%s]: %w", path, err)
+	}
+	database, err := db.DeserializeDatabase(m.logger, bytes)
+	if err != nil {
+		return fmt.Errorf("error loading snapshot from [%s]: %w", path, err)
+	}
+	m.snapshots[name] = &snapshot{
+		database: database,
+		time:     time.Now(),
+	}
+	m.logger.Info("Imported DB snapshot", "name", name, "path", path)
+	return nil
+}
+
+// Get the changes between two snapshots. An empty name refers to the live database.
+func (m *NodeSetMockManager) DiffSnapshots(from string, to string) (api.StateDiffData, error) {
+	fromState, err := m.getSnapshotState(from)
+	if err != nil {
+		return api.StateDiffData{}, err
+	}
+	toState, err := m.getSnapshotState(to)
+	if err != nil {
+		return api.StateDiffData{}, err
+	}
+	return api.StateDiffData{
+		From:    from,
+		To:      to,
+		Changes: diffStates(fromState, toState),
+	}, nil
+}
+
+// Get the state of a snapshot, or of the live database if the name is empty
+func (m *NodeSetMockManager) getSnapshotState(name string) (api.StateData, error) {
+	database := m.database
+	if name != "" {
+		snapshot, exists := m.snapshots[name]
+		if !exists {
+			return api.StateData{}, fmt.Errorf("snapshot with name [%s] does not exist", name)
+		}
+		database = snapshot.database
+	}
+	state, err := database.GetState(api.StateFilter{})
+	if err != nil {
+		return api.StateData{}, fmt.Errorf("error getting state of snapshot [%s]: %w", name, err)
+	}
+	return state, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/openapi"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	Version string = "1.0.0"
+)
+
+// Run
+func main() {
+	// Initialise application
+	app := cli.NewApp()
+
+	// Set application info
+	app.Name = "nodeset-openapi"
+	app.Usage = "Generates the OpenAPI documents for the NodeSet API from the client bindings"
+	app.Version = Version
+	app.Authors = []*cli.Author{
+		{
+			Name:  "NodeSet",
+			Email: "info@nodeset.io",
+		},
+	}
+	app.Copyright = "(C) 2024 NodeSet LLC"
+
+	sourceFlag := &cli.StringFlag{
+		Name:    "source",
+		Aliases: []string{"s"},
+		Usage:   "The root directory of the client module",
+		Value:   ".",
+	}
+	outputFlag := &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "The directory to write the documents to",
+		Value:   ".",
+	}
+
+	app.Flags = []cli.Flag{
+		sourceFlag,
+		outputFlag,
+	}
+	app.Action = func(c *cli.Context) error {
+		source := c.String(sourceFlag.Name)
+		output := c.String(outputFlag.Name)
+		err := os.MkdirAll(output, 0755)
+		if err != nil {
+			return fmt.Errorf("error creating output directory: %w", err)
+		}
+
+		for _, version := range openapi.Versions {
+			document, err := openapi.Generate(version, source)
+			if err != nil {
+				return fmt.Errorf("error generating %s document: %w", version, err)
+			}
+			bytes, err := document.Marshal()
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(output, filepath.Base(openapi.GetFileName(version)))
+			err = os.WriteFile(path, bytes, 0644)
+			if err != nil {
+				return fmt.Errorf("error writing %s document: %w", version, err)
+			}
+			fmt.Printf("Wrote %s\n", path)
+		}
+		return nil
+	}
+
+	// Run application
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,443 @@
+// Package openapi generates OpenAPI 3 documents for the v2 and v3 NodeSet APIs from the client bindings, and validates server
+// responses against them. The request and response schemas come from the client's wire types, and each route's status codes
+// and error keys come from the source of the client function that calls it, so the spec can't drift from the client.
+//
+// The generated documents are committed in the spec directory; run `go generate ./server-mock/openapi` after changing the
+// client bindings to update them.
+package openapi
+
+//go:generate go run ./nodeset-openapi --source ../.. --output spec
+
+import (
+	"embed"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/common"
+)
+
+const (
+	// Version of the OpenAPI specification the documents follow
+	OpenApiVersion string = "3.0.3"
+
+	// Name of the security scheme for routes that need a session token
+	sessionSecurityScheme string = "session"
+
+	// Content type of every request and response body
+	jsonContentType string = "application/json"
+
+	// Base URL of the production server
+	productionUrl string = "https://nodeset.io/api"
+)
+
+// The API versions there are documents for
+var Versions []string = []string{
+	apiv2.ApiVersion,
+	apiv3.ApiVersion,
+}
+
+// The committed documents, generated from the client bindings
+//
+//go:embed spec/*.json
+var specFiles embed.FS
+
+// An OpenAPI 3 document
+type Document struct {
+	OpenApi    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers"`
+	Tags       []Tag                 `json:"tags"`
+	Security   []SecurityRequirement `json:"security"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components Components            `json:"components"`
+}
+
+// Metadata about the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// A server that hosts the API
+type Server struct {
+	Url         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// A group of operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// The security schemes an operation needs, by name
+type SecurityRequirement map[string][]string
+
+// The operations on a single path
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// A single API operation
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+}
+
+// A parameter of an operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// The body of a request
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// A possible response to an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// The schema of a body with a specific content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Reusable parts of the document
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// A way for requests to authenticate
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+// Get the operation for an HTTP method
+func (p *PathItem) GetOperation(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return p.Get
+	case http.MethodPost:
+		return p.Post
+	case http.MethodPatch:
+		return p.Patch
+	case http.MethodPut:
+		return p.Put
+	case http.MethodDelete:
+		return p.Delete
+	}
+	return nil
+}
+
+// Set the operation for an HTTP method
+func (p *PathItem) setOperation(method string, operation *Operation) error {
+	switch method {
+	case http.MethodGet:
+		p.Get = operation
+	case http.MethodPost:
+		p.Post = operation
+	case http.MethodPatch:
+		p.Patch = operation
+	case http.MethodPut:
+		p.Put = operation
+	case http.MethodDelete:
+		p.Delete = operation
+	default:
+		return fmt.Errorf("unsupported method [%s]", method)
+	}
+	return nil
+}
+
+// Get the base path of the version's routes, such as [/api/v3]
+func (d *Document) GetBasePath() string {
+	url := d.Servers[0].Url
+	schemeEnd := strings.Index(url, "://")
+	if schemeEnd >= 0 {
+		url = url[schemeEnd+3:]
+	}
+	slash := strings.Index(url, "/")
+	if slash < 0 {
+		return ""
+	}
+	return url[slash:]
+}
+
+// Serialize the document the way it's committed
+func (d *Document) Marshal() ([]byte, error) {
+	bytes, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing document: %w", err)
+	}
+	return append(bytes, '\n'), nil
+}
+
+// Load the committed document for an API version
+func Load(version string) (*Document, error) {
+	bytes, err := specFiles.ReadFile(GetFileName(version))
+	if err != nil {
+		return nil, fmt.Errorf("there's no document for API version [%s]", version)
+	}
+	var document Document
+	err = json.Unmarshal(bytes, &document)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing document for API version [%s]: %w", version, err)
+	}
+	return &document, nil
+}
+
+// Get the path of an API version's document, relative to this package
+func GetFileName(version string) string {
+	return "spec/" + version + ".json"
+}
+
+// Generate the document for an API version from the client bindings. The source directory is the root of the client module,
+// which is read to find each route's error keys.
+func Generate(version string, sourceDir string) (*Document, error) {
+	var routes []route
+	switch version {
+	case apiv2.ApiVersion:
+		routes = v2Routes
+	case apiv3.ApiVersion:
+		routes = v3Routes
+	default:
+		return nil, fmt.Errorf("unknown API version [%s]", version)
+	}
+	source, err := newSourceIndex(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+	builder := newSchemaBuilder(source)
+
+	document := &Document{
+		OpenApi: OpenApiVersion,
+		Info: Info{
+			Title:       "NodeSet API",
+			Description: "The nodeset.io API used by the NodeSet client bindings. Every response is wrapped in the NodeSetResponse envelope; failed requests set its error field to one of the error keys listed for the response's status code.",
+			Version:     version,
+		},
+		Servers: []Server{
+			{
+				Url:         productionUrl + "/" + version,
+				Description: "Production",
+			},
+		},
+		Tags: []Tag{
+			{Name: tag_Core, Description: "Node registration and sessions"},
+			{Name: tag_StakeWise, Description: "StakeWise vaults and validators"},
+			{Name: tag_Constellation, Description: "Constellation whitelisting, minipools, and validators"},
+		},
+		Security: []SecurityRequirement{
+			{sessionSecurityScheme: []string{}},
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				sessionSecurityScheme: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "The session token from a successful login",
+				},
+			},
+		},
+	}
+	for _, route := range routes {
+		operation, err := buildOperation(source, builder, route)
+		if err != nil {
+			return nil, fmt.Errorf("error generating %s %s: %w", route.method, route.path, err)
+		}
+		pathItem, exists := document.Paths[route.path]
+		if !exists {
+			pathItem = &PathItem{}
+			document.Paths[route.path] = pathItem
+		}
+		if pathItem.GetOperation(route.method) != nil {
+			return nil, fmt.Errorf("%s %s is declared more than once", route.method, route.path)
+		}
+		err = pathItem.setOperation(route.method, operation)
+		if err != nil {
+			return nil, err
+		}
+	}
+	document.Components.Schemas = builder.components
+	return document, nil
+}
+
+// Build the operation for a route
+func buildOperation(source *sourceIndex, builder *schemaBuilder, route route) (*Operation, error) {
+	contract, errorDocs, err := source.getClientContract(route.pkgPath, route.function)
+	if err != nil {
+		return nil, err
+	}
+	if contract.method != route.method {
+		return nil, fmt.Errorf("the client sends %s, not %s", contract.method, route.method)
+	}
+	err = checkDataType(contract, route.data)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := source.getFuncDoc(route.pkgPath, route.function)
+	if err != nil {
+		return nil, err
+	}
+
+	_, methodName, _ := strings.Cut(route.function, ".")
+	operation := &Operation{
+		OperationID: route.tag + "." + methodName,
+		Summary:     summary,
+		Tags:        []string{route.tag},
+		Responses:   map[string]*Response{},
+	}
+	if !contract.requireAuth {
+		operation.Security = &[]SecurityRequirement{}
+	}
+
+	// Path parameters
+	for _, segment := range strings.Split(route.path, "/") {
+		if !strings.HasPrefix(segment, "{") {
+			continue
+		}
+		name := strings.Trim(segment, "{}")
+		parameter, exists := pathParameters[name]
+		if !exists {
+			return nil, fmt.Errorf("path parameter [%s] isn't described", name)
+		}
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+
+	// Request body
+	if route.body != nil {
+		schema, err := builder.build(route.body)
+		if err != nil {
+			return nil, err
+		}
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				jsonContentType: {Schema: schema},
+			},
+		}
+	}
+
+	// Responses; every route that needs a session can fail because of it, even if the client leaves that to the caller
+	responses := contract.responses
+	if contract.requireAuth && !contains(responses[http.StatusUnauthorized], common.InvalidSessionKey) {
+		responses[http.StatusUnauthorized] = append(responses[http.StatusUnauthorized], common.InvalidSessionKey)
+		sort.Strings(responses[http.StatusUnauthorized])
+		if errorDocs[common.InvalidSessionKey] == "" {
+			_, decl, err := source.resolveConstant(typeOf[common.Deployment]().PkgPath(), "InvalidSessionKey")
+			if err != nil {
+				return nil, err
+			}
+			errorDocs[common.InvalidSessionKey] = decl.doc
+		}
+	}
+	for status, keys := range responses {
+		if len(keys) == 0 {
+			schema, err := buildEnvelope(builder, route.data)
+			if err != nil {
+				return nil, err
+			}
+			operation.Responses[strconv.Itoa(status)] = &Response{
+				Description: http.StatusText(status),
+				Content: map[string]MediaType{
+					jsonContentType: {Schema: schema},
+				},
+			}
+			continue
+		}
+
+		description := http.StatusText(status) + ". The error field is one of:"
+		for _, key := range keys {
+			description += fmt.Sprintf("\n- `%s`: %s", key, errorDocs[key])
+		}
+		operation.Responses[strconv.Itoa(status)] = &Response{
+			Description: description,
+			Content: map[string]MediaType{
+				jsonContentType: {Schema: buildErrorEnvelope(keys)},
+			},
+		}
+	}
+	return operation, nil
+}
+
+// Make sure a route's declared response data matches the type the client decodes
+func checkDataType(contract *clientContract, data reflect.Type) error {
+	if data == nil {
+		if contract.dataType != "" {
+			return fmt.Errorf("the client decodes %s.%s but the route doesn't declare response data", contract.dataPackage, contract.dataType)
+		}
+		return nil
+	}
+	if data.PkgPath() != contract.dataPackage || data.Name() != contract.dataType {
+		return fmt.Errorf("the client decodes %s.%s but the route declares %s", contract.dataPackage, contract.dataType, data)
+	}
+	return nil
+}
+
+// Build the schema of the NodeSetResponse envelope for a successful response
+func buildEnvelope(builder *schemaBuilder, data reflect.Type) (*Schema, error) {
+	schema := newEnvelope()
+	schema.Required = []string{"ok"}
+	if data == nil {
+		schema.Properties["data"] = &Schema{Type: "object"}
+		return schema, nil
+	}
+	dataSchema, err := builder.build(data)
+	if err != nil {
+		return nil, err
+	}
+	schema.Properties["data"] = dataSchema
+	schema.Required = append(schema.Required, "data")
+	return schema, nil
+}
+
+// Build the schema of the NodeSetResponse envelope for a failed response
+func buildErrorEnvelope(keys []string) *Schema {
+	schema := newEnvelope()
+	schema.Required = []string{"ok", "error"}
+	schema.Properties["error"].Enum = keys
+	schema.Properties["data"] = &Schema{Type: "object", Description: "Always empty, since the envelope's data isn't omitted"}
+	return schema
+}
+
+// Create the base schema of the NodeSetResponse envelope
+func newEnvelope() *Schema {
+	noExtras := false
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"ok":      {Type: "boolean"},
+			"message": {Type: "string"},
+			"error":   {Type: "string"},
+		},
+		AdditionalProperties: &noExtras,
+	}
+}
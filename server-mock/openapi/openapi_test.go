@@ -0,0 +1,116 @@
+package openapi_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/conformance"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/openapi"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure the committed documents match what the client bindings generate
+func TestDocumentsAreUpToDate(t *testing.T) {
+	for _, version := range openapi.Versions {
+		document, err := openapi.Generate(version, "../..")
+		require.NoError(t, err)
+		generated, err := document.Marshal()
+		require.NoError(t, err)
+		committed, err := os.ReadFile(openapi.GetFileName(version))
+		require.NoError(t, err)
+		require.Equal(t, string(committed), string(generated), "the %s document is out of date; run `go generate ./server-mock/openapi`", version)
+	}
+}
+
+// Make sure every response the mock gives during a full conformance run matches the documents, and that the run covers
+// every documented operation
+func TestMockResponses(t *testing.T) {
+	logger := slog.Default()
+	server := testkit.StartServer(t, logger)
+	documents := []*openapi.Document{}
+	for _, version := range openapi.Versions {
+		document, err := openapi.Load(version)
+		require.NoError(t, err)
+		documents = append(documents, document)
+	}
+
+	// Validate every API response on its way back to the client
+	target, err := url.Parse(fmt.Sprintf("http://localhost:%d", server.GetPort()))
+	require.NoError(t, err)
+	lock := &sync.Mutex{}
+	failures := []string{}
+	succeeded := map[string]bool{}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ModifyResponse = func(response *http.Response) error {
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+		response.Body = io.NopCloser(bytes.NewReader(body))
+
+		request := response.Request
+		lock.Lock()
+		defer lock.Unlock()
+		for _, document := range documents {
+			if !strings.HasPrefix(request.URL.Path, document.GetBasePath()+"/") {
+				continue
+			}
+			operation, err := document.ValidateResponse(request.Method, request.URL.Path, response.StatusCode, body)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s %s: %s", request.Method, request.URL.Path, err.Error()))
+				return nil
+			}
+			if response.StatusCode == http.StatusOK {
+				succeeded[document.Info.Version+" "+operation.OperationID] = true
+			}
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("%s %s isn't covered by any document", request.Method, request.URL.Path))
+		return nil
+	}
+	proxyServer := httptest.NewServer(proxy)
+	t.Cleanup(proxyServer.Close)
+
+	// Run the conformance suite through the proxy
+	ctx := context.Background()
+	depositRootProvider, err := v3stakewise.NewRpcDepositRootProvider(ctx, fmt.Sprintf("http://localhost:%d/eth", server.GetPort()), testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	report, err := conformance.Run(ctx, logger, conformance.Config{
+		ApiUrl:              proxyServer.URL + "/api",
+		Timeout:             testkit.DefaultTimeout,
+		Admin:               server.NewAdminClient(),
+		DepositRootProvider: depositRootProvider,
+	})
+	require.NoError(t, err)
+	require.True(t, report.Passed())
+	require.Empty(t, failures)
+
+	// Every documented operation should have succeeded at least once
+	missing := []string{}
+	for _, document := range documents {
+		for _, item := range document.Paths {
+			for _, operation := range []*openapi.Operation{item.Get, item.Post, item.Patch, item.Put, item.Delete} {
+				if operation != nil && !succeeded[document.Info.Version+" "+operation.OperationID] {
+					missing = append(missing, document.Info.Version+" "+operation.OperationID)
+				}
+			}
+		}
+	}
+	sort.Strings(missing)
+	require.Empty(t, missing, "operations without a validated successful response")
+	t.Logf("Validated successful responses for %d operations", len(succeeded))
+}
@@ -0,0 +1,125 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+
+	v2constellation "github.com/nodeset-org/nodeset-client-go/api-v2/constellation"
+	v2core "github.com/nodeset-org/nodeset-client-go/api-v2/core"
+	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
+	v3constellation "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
+	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/core"
+	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
+)
+
+const (
+	// Tags for the modules of the API
+	tag_Core          string = "core"
+	tag_StakeWise     string = "stakewise"
+	tag_Constellation string = "constellation"
+
+	// Import paths of the packages with the client functions for each route
+	pkg_V2Core          string = "github.com/nodeset-org/nodeset-client-go/api-v2/core"
+	pkg_V2StakeWise     string = "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
+	pkg_V2Constellation string = "github.com/nodeset-org/nodeset-client-go/api-v2/constellation"
+	pkg_V3Core          string = "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	pkg_V3StakeWise     string = "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	pkg_V3Constellation string = "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
+)
+
+// A route of the API, and the client function that calls it. Everything else about the route, like its error keys and
+// response data, is read from the client function's source so the spec can't drift from it.
+type route struct {
+	// The module the route belongs to
+	tag string
+
+	// The HTTP method of the route, which has to match what the client function sends
+	method string
+
+	// The path of the route relative to the version's base URL
+	path string
+
+	// The package and name of the client function that calls the route, as [Type.Method] for methods
+	pkgPath  string
+	function string
+
+	// The type of the request body, or nil if the route doesn't take one
+	body reflect.Type
+
+	// The type of the response data, or nil if the route doesn't return any. It has to match what the client function decodes.
+	data reflect.Type
+}
+
+// Get the type of a value, for route declarations
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// The routes of the v2 API
+var v2Routes = []route{
+	// Core
+	{tag_Core, http.MethodGet, "/" + v2core.CorePrefix + core.NoncePath, pkg_V2Core, "V2CoreClient.Nonce", nil, typeOf[core.NonceData]()},
+	{tag_Core, http.MethodPost, "/" + v2core.CorePrefix + core.NodeAddressPath, pkg_V2Core, "V2CoreClient.NodeAddress", typeOf[v2core.NodeAddressRequest](), nil},
+	{tag_Core, http.MethodPost, "/" + v2core.CorePrefix + core.LoginPath, pkg_V2Core, "V2CoreClient.Login", typeOf[core.LoginRequest](), typeOf[core.LoginData]()},
+
+	// StakeWise
+	{tag_StakeWise, http.MethodGet, "/" + v2stakewise.StakeWisePrefix + common.DeploymentsPath, pkg_V2StakeWise, "V2StakeWiseClient.Deployments", nil, typeOf[common.DeploymentsData]()},
+	{tag_StakeWise, http.MethodGet, "/" + v2stakewise.StakeWisePrefix + "{deployment}/" + v2stakewise.VaultsPath, pkg_V2StakeWise, "V2StakeWiseClient.Vaults", nil, typeOf[v2stakewise.VaultsData]()},
+	{tag_StakeWise, http.MethodGet, "/" + v2stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.DepositDataMetaPath, pkg_V2StakeWise, "V2StakeWiseClient.DepositDataMeta", nil, typeOf[stakewise.DepositDataMetaData]()},
+	{tag_StakeWise, http.MethodGet, "/" + v2stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.DepositDataPath, pkg_V2StakeWise, "V2StakeWiseClient.DepositData_Get", nil, typeOf[stakewise.DepositDataData]()},
+	{tag_StakeWise, http.MethodPost, "/" + v2stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.DepositDataPath, pkg_V2StakeWise, "V2StakeWiseClient.DepositData_Post", typeOf[v2stakewise.DepositData_PostBody](), nil},
+	{tag_StakeWise, http.MethodGet, "/" + v2stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.ValidatorsPath, pkg_V2StakeWise, "V2StakeWiseClient.Validators_Get", nil, typeOf[v2stakewise.ValidatorsData]()},
+	{tag_StakeWise, http.MethodPatch, "/" + v2stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.ValidatorsPath, pkg_V2StakeWise, "V2StakeWiseClient.Validators_Patch", typeOf[v2stakewise.Validators_PatchBody](), nil},
+
+	// Constellation
+	{tag_Constellation, http.MethodGet, "/" + v2constellation.ConstellationPrefix + common.DeploymentsPath, pkg_V2Constellation, "V2ConstellationClient.Deployments", nil, typeOf[common.DeploymentsData]()},
+	{tag_Constellation, http.MethodGet, "/" + v2constellation.ConstellationPrefix + "{deployment}/" + v2constellation.WhitelistPath, pkg_V2Constellation, "V2ConstellationClient.Whitelist_Get", nil, typeOf[v2constellation.Whitelist_GetData]()},
+	{tag_Constellation, http.MethodPost, "/" + v2constellation.ConstellationPrefix + "{deployment}/" + v2constellation.WhitelistPath, pkg_V2Constellation, "V2ConstellationClient.Whitelist_Post", nil, typeOf[v2constellation.Whitelist_PostData]()},
+	{tag_Constellation, http.MethodPost, "/" + v2constellation.ConstellationPrefix + "{deployment}/" + v2constellation.MinipoolDepositSignaturePath, pkg_V2Constellation, "V2ConstellationClient.MinipoolDepositSignature", typeOf[v2constellation.MinipoolDepositSignatureRequest](), typeOf[v2constellation.MinipoolDepositSignatureData]()},
+	{tag_Constellation, http.MethodGet, "/" + v2constellation.ConstellationPrefix + "{deployment}/" + v2constellation.ValidatorsPath, pkg_V2Constellation, "V2ConstellationClient.Validators_Get", nil, typeOf[v2constellation.ValidatorsData]()},
+	{tag_Constellation, http.MethodPatch, "/" + v2constellation.ConstellationPrefix + "{deployment}/" + v2constellation.ValidatorsPath, pkg_V2Constellation, "V2ConstellationClient.Validators_Patch", typeOf[v2constellation.Validators_PatchBody](), nil},
+}
+
+// The routes of the v3 API
+var v3Routes = []route{
+	// Core
+	{tag_Core, http.MethodGet, "/" + v3core.CorePrefix + core.NoncePath, pkg_V3Core, "V3CoreClient.Nonce", nil, typeOf[core.NonceData]()},
+	{tag_Core, http.MethodPost, "/" + v3core.CorePrefix + core.NodeAddressPath, pkg_V3Core, "V3CoreClient.NodeAddress", typeOf[v3core.NodeAddressRequest](), nil},
+	{tag_Core, http.MethodPost, "/" + v3core.CorePrefix + core.LoginPath, pkg_V3Core, "V3CoreClient.Login", typeOf[core.LoginRequest](), typeOf[core.LoginData]()},
+
+	// StakeWise
+	{tag_StakeWise, http.MethodGet, "/" + v3stakewise.StakeWisePrefix + common.DeploymentsPath, pkg_V3StakeWise, "V3StakeWiseClient.Deployments", nil, typeOf[common.DeploymentsData]()},
+	{tag_StakeWise, http.MethodGet, "/" + v3stakewise.StakeWisePrefix + "{deployment}/" + v3stakewise.VaultsPath, pkg_V3StakeWise, "V3StakeWiseClient.Vaults", nil, typeOf[v3stakewise.VaultsData]()},
+	{tag_StakeWise, http.MethodGet, "/" + v3stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.ValidatorsMetaPath, pkg_V3StakeWise, "V3StakeWiseClient.ValidatorMeta_Get", nil, typeOf[stakewise.ValidatorsMetaData]()},
+	{tag_StakeWise, http.MethodGet, "/" + v3stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.ValidatorsPath, pkg_V3StakeWise, "V3StakeWiseClient.Validators_Get", nil, typeOf[v3stakewise.ValidatorsData]()},
+	{tag_StakeWise, http.MethodPost, "/" + v3stakewise.StakeWisePrefix + "{deployment}/{vault}/" + stakewise.ValidatorsPath, pkg_V3StakeWise, "V3StakeWiseClient.Validators_Post", typeOf[v3stakewise.Validators_PostBody](), typeOf[v3stakewise.PostValidatorData]()},
+
+	// Constellation
+	{tag_Constellation, http.MethodGet, "/" + v3constellation.ConstellationPrefix + common.DeploymentsPath, pkg_V3Constellation, "V3ConstellationClient.Deployments", nil, typeOf[common.DeploymentsData]()},
+	{tag_Constellation, http.MethodGet, "/" + v3constellation.ConstellationPrefix + "{deployment}/" + v3constellation.WhitelistPath, pkg_V3Constellation, "V3ConstellationClient.Whitelist_Get", nil, typeOf[v3constellation.Whitelist_GetData]()},
+	{tag_Constellation, http.MethodPost, "/" + v3constellation.ConstellationPrefix + "{deployment}/" + v3constellation.WhitelistPath, pkg_V3Constellation, "V3ConstellationClient.Whitelist_Post", nil, typeOf[v3constellation.Whitelist_PostData]()},
+	{tag_Constellation, http.MethodPost, "/" + v3constellation.ConstellationPrefix + "{deployment}/" + v3constellation.MinipoolDepositSignaturePath, pkg_V3Constellation, "V3ConstellationClient.MinipoolDepositSignature", typeOf[v3constellation.MinipoolDepositSignatureRequest](), typeOf[v3constellation.MinipoolDepositSignatureData]()},
+	{tag_Constellation, http.MethodGet, "/" + v3constellation.ConstellationPrefix + "{deployment}/" + v3constellation.ValidatorsPath, pkg_V3Constellation, "V3ConstellationClient.Validators_Get", nil, typeOf[v3constellation.ValidatorsData]()},
+	{tag_Constellation, http.MethodPatch, "/" + v3constellation.ConstellationPrefix + "{deployment}/" + v3constellation.ValidatorsPath, pkg_V3Constellation, "V3ConstellationClient.Validators_Patch", typeOf[v3constellation.Validators_PatchBody](), nil},
+}
+
+// Descriptions of the path parameters the routes use
+var pathParameters = map[string]Parameter{
+	"deployment": {
+		Name:        "deployment",
+		In:          "path",
+		Required:    true,
+		Description: "The name of the deployment",
+		Schema:      &Schema{Type: "string"},
+	},
+	"vault": {
+		Name:        "vault",
+		In:          "path",
+		Required:    true,
+		Description: "The address of the StakeWise vault",
+		Schema:      &Schema{Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"},
+	},
+}
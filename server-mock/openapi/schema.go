@@ -0,0 +1,258 @@
+package openapi
+
+import (
+	"encoding"
+	"fmt"
+	"go/ast"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+const (
+	// Prefix of references to the document's schema components
+	schemaRefPrefix string = "#/components/schemas/"
+)
+
+// A JSON schema, limited to the OpenAPI 3.0 subset the generator produces
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// Types from outside the module with their own JSON encoding
+var knownSchemas = map[reflect.Type]func() *Schema{
+	reflect.TypeOf(ethcommon.Address{}): func() *Schema {
+		return &Schema{Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$", Description: "An Ethereum address"}
+	},
+	reflect.TypeOf(ethcommon.Hash{}): func() *Schema {
+		return &Schema{Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$", Description: "A 32-byte hash"}
+	},
+	reflect.TypeOf(beacon.ValidatorPubkey{}): func() *Schema {
+		return &Schema{Type: "string", Pattern: "^(0x)?[0-9a-fA-F]{96}$", Description: "A validator's BLS public key"}
+	},
+	reflect.TypeOf(beacon.ValidatorSignature{}): func() *Schema {
+		return &Schema{Type: "string", Pattern: "^(0x)?[0-9a-fA-F]{192}$", Description: "A BLS signature"}
+	},
+	reflect.TypeOf(beacon.ByteArray{}): func() *Schema {
+		return &Schema{Type: "string", Pattern: "^[0-9a-fA-F]*$", Description: "Hex-encoded bytes without a 0x prefix"}
+	},
+	reflect.TypeOf(big.Int{}): func() *Schema {
+		return &Schema{Type: "integer"}
+	},
+	reflect.TypeOf(time.Time{}): func() *Schema {
+		return &Schema{Type: "string", Format: "date-time"}
+	},
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Builds schemas for Go types, collecting named structs as reusable components
+type schemaBuilder struct {
+	source     *sourceIndex
+	components map[string]*Schema
+
+	// The Go type each component was built from, for catching name collisions
+	componentTypes map[string]reflect.Type
+}
+
+// Creates a new schema builder
+func newSchemaBuilder(source *sourceIndex) *schemaBuilder {
+	return &schemaBuilder{
+		source:         source,
+		components:     map[string]*Schema{},
+		componentTypes: map[string]reflect.Type{},
+	}
+}
+
+// Get the schema for a Go type, the way encoding/json would serialize it
+func (b *schemaBuilder) build(t reflect.Type) (*Schema, error) {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		nullable = true
+		t = t.Elem()
+	}
+	schema, err := b.buildValue(t)
+	if err != nil {
+		return nil, err
+	}
+	if nullable {
+		if schema.Ref != "" {
+			// Siblings of $ref are ignored in OpenAPI 3.0, so a nullable reference can't be expressed directly
+			return nil, fmt.Errorf("pointers to struct types like [%s] aren't supported", t)
+		}
+		schema.Nullable = true
+	}
+	return schema, nil
+}
+
+// Get the schema for a non-pointer Go type
+func (b *schemaBuilder) buildValue(t reflect.Type) (*Schema, error) {
+	if newSchema, exists := knownSchemas[t]; exists {
+		return newSchema(), nil
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return nil, fmt.Errorf("type [%s] has custom JSON encoding, so it needs an entry in the known schemas", t)
+	}
+	if t.Kind() != reflect.String && (t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return &Schema{Type: "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer", Format: intFormat(t)}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := int64(0)
+		return &Schema{Type: "integer", Format: intFormat(t), Minimum: &zero}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+
+	case reflect.String:
+		schema := &Schema{Type: "string"}
+		if t.PkgPath() != "" {
+			schema.Enum = b.source.getEnum(t.PkgPath(), t.Name())
+			schema.Description = b.source.getTypeDoc(t.PkgPath(), t.Name())
+		}
+		return schema, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := b.build(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items, Nullable: t.Kind() == reflect.Slice}, nil
+
+	case reflect.Struct:
+		return b.buildStruct(t)
+	}
+	return nil, fmt.Errorf("type [%s] can't be described with a schema", t)
+}
+
+// Get the format of an integer type
+func intFormat(t reflect.Type) string {
+	if t.Bits() > 32 {
+		return "int64"
+	}
+	return "int32"
+}
+
+// Get the schema for a struct. Exported named structs become components and are referenced; everything else is inlined.
+func (b *schemaBuilder) buildStruct(t reflect.Type) (*Schema, error) {
+	name := b.getComponentName(t)
+	if name != "" {
+		if existing, exists := b.componentTypes[name]; exists {
+			if existing != t {
+				return nil, fmt.Errorf("types [%s] and [%s] have the same component name [%s]", existing, t, name)
+			}
+			return &Schema{Ref: schemaRefPrefix + name}, nil
+		}
+		b.componentTypes[name] = t
+	}
+
+	noExtras := false
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		Required:             []string{},
+		AdditionalProperties: &noExtras,
+	}
+	if t.PkgPath() != "" {
+		schema.Description = b.source.getTypeDoc(t.PkgPath(), t.Name())
+	}
+	err := b.addFields(schema, t)
+	if err != nil {
+		return nil, err
+	}
+	if len(schema.Required) == 0 {
+		schema.Required = nil
+	}
+
+	if name == "" {
+		return schema, nil
+	}
+	b.components[name] = schema
+	return &Schema{Ref: schemaRefPrefix + name}, nil
+}
+
+// Add a struct's fields to an object schema, following encoding/json's rules for tags and embedded structs
+func (b *schemaBuilder) addFields(schema *Schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			err := b.addFields(schema, field.Type)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema, err := b.build(field.Type)
+		if err != nil {
+			return fmt.Errorf("error building schema for field [%s] of [%s]: %w", field.Name, t, err)
+		}
+		if fieldSchema.Ref == "" && t.PkgPath() != "" {
+			doc := b.source.getFieldDoc(t.PkgPath(), t.Name(), field.Name)
+			if doc != "" {
+				fieldSchema.Description = doc
+			}
+		}
+		schema.Properties[name] = fieldSchema
+		if !contains(strings.Split(options, ","), "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// Get the component name of a struct type, or an empty string if it should be inlined
+func (b *schemaBuilder) getComponentName(t reflect.Type) string {
+	if t.Name() == "" || t.PkgPath() == "" || !ast.IsExported(t.Name()) {
+		return ""
+	}
+	pkgName := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	if b.source.isModulePackage(t.PkgPath()) {
+		pkg, err := b.source.getPackage(t.PkgPath())
+		if err == nil {
+			pkgName = pkg.name
+		}
+	}
+	return pkgName + "." + t.Name()
+}
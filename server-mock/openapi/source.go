@@ -0,0 +1,608 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HTTP status codes that the client bindings switch on, by their name in net/http
+var httpStatuses = map[string]int{
+	"StatusOK":                  200,
+	"StatusCreated":             201,
+	"StatusNoContent":           204,
+	"StatusBadRequest":          400,
+	"StatusUnauthorized":        401,
+	"StatusForbidden":           403,
+	"StatusNotFound":            404,
+	"StatusConflict":            409,
+	"StatusUnprocessableEntity": 422,
+	"StatusTooManyRequests":     429,
+	"StatusInternalServerError": 500,
+}
+
+// What a client function, and the module functions it calls, expect from the server
+type clientContract struct {
+	// The HTTP method of the request
+	method string
+
+	// Whether the request carries the session token
+	requireAuth bool
+
+	// The package and name of the response data type, or empty for requests without response data
+	dataPackage string
+	dataType    string
+
+	// The error keys handled for each status code. Status codes without any keys are successful responses.
+	responses map[int][]string
+}
+
+// A package of the client module, parsed from source
+type sourcePackage struct {
+	// The package name, which can differ from the last element of its path
+	name string
+
+	files     []*ast.File
+	constants map[string]*constantDecl
+	funcs     map[string]*ast.FuncDecl
+	types     map[string]*ast.TypeSpec
+	typeDocs  map[string]string
+
+	// The values of the string constants declared with each named type, in declaration order
+	enums map[string][]string
+
+	// The file each function was declared in, for resolving its imports
+	funcFiles map[string]*ast.File
+}
+
+// A constant declaration
+type constantDecl struct {
+	file  *ast.File
+	value ast.Expr
+	doc   string
+}
+
+// Index of the client module's source, used to derive the parts of the spec that only exist as code
+type sourceIndex struct {
+	root       string
+	modulePath string
+	fset       *token.FileSet
+	packages   map[string]*sourcePackage
+}
+
+// Creates a source index for the module rooted at the provided directory
+func newSourceIndex(root string) (*sourceIndex, error) {
+	modBytes, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading go.mod in [%s]: %w", root, err)
+	}
+	modulePath := ""
+	for _, line := range strings.Split(string(modBytes), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			modulePath = strings.TrimSpace(strings.TrimPrefix(line, "module "))
+			break
+		}
+	}
+	if modulePath == "" {
+		return nil, fmt.Errorf("go.mod in [%s] doesn't declare a module", root)
+	}
+	return &sourceIndex{
+		root:       root,
+		modulePath: modulePath,
+		fset:       token.NewFileSet(),
+		packages:   map[string]*sourcePackage{},
+	}, nil
+}
+
+// Check if an import path belongs to the client module
+func (x *sourceIndex) isModulePackage(pkgPath string) bool {
+	return pkgPath == x.modulePath || strings.HasPrefix(pkgPath, x.modulePath+"/")
+}
+
+// Get a package of the client module, parsing it if it hasn't been parsed yet
+func (x *sourceIndex) getPackage(pkgPath string) (*sourcePackage, error) {
+	if pkg, exists := x.packages[pkgPath]; exists {
+		return pkg, nil
+	}
+	if !x.isModulePackage(pkgPath) {
+		return nil, fmt.Errorf("package [%s] isn't part of module [%s]", pkgPath, x.modulePath)
+	}
+	dir := filepath.Join(x.root, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(pkgPath, x.modulePath), "/")))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading package [%s]: %w", pkgPath, err)
+	}
+
+	pkg := &sourcePackage{
+		constants: map[string]*constantDecl{},
+		funcs:     map[string]*ast.FuncDecl{},
+		types:     map[string]*ast.TypeSpec{},
+		typeDocs:  map[string]string{},
+		enums:     map[string][]string{},
+		funcFiles: map[string]*ast.File{},
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(x.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing [%s]: %w", name, err)
+		}
+		pkg.name = file.Name.Name
+		pkg.files = append(pkg.files, file)
+		pkg.index(file)
+	}
+	if len(pkg.files) == 0 {
+		return nil, fmt.Errorf("package [%s] doesn't have any source files", pkgPath)
+	}
+	x.packages[pkgPath] = pkg
+	return pkg, nil
+}
+
+// Add a file's declarations to the package
+func (p *sourcePackage) index(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			name := decl.Name.Name
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				name = receiverName(decl.Recv.List[0].Type) + "." + name
+			}
+			p.funcs[name] = decl
+			p.funcFiles[name] = file
+
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					p.types[spec.Name.Name] = spec
+					doc := spec.Doc
+					if doc == nil {
+						doc = decl.Doc
+					}
+					p.typeDocs[spec.Name.Name] = commentText(doc)
+
+				case *ast.ValueSpec:
+					if decl.Tok != token.CONST {
+						continue
+					}
+					for i, name := range spec.Names {
+						if i >= len(spec.Values) {
+							continue
+						}
+						p.constants[name.Name] = &constantDecl{
+							file:  file,
+							value: spec.Values[i],
+							doc:   commentText(spec.Doc),
+						}
+						if typeIdent, isIdent := spec.Type.(*ast.Ident); isIdent {
+							if lit, isLit := spec.Values[i].(*ast.BasicLit); isLit && lit.Kind == token.STRING {
+								value, err := strconv.Unquote(lit.Value)
+								if err == nil {
+									p.enums[typeIdent.Name] = append(p.enums[typeIdent.Name], value)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Get the name of a method receiver's type
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// Get the text of a doc comment as a single line
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// Resolve the import path of a package alias used in a file
+func (x *sourceIndex) resolveImport(file *ast.File, alias string) (string, bool) {
+	for _, spec := range file.Imports {
+		pkgPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else if x.isModulePackage(pkgPath) {
+			// Module packages can have names that differ from their directory
+			pkg, err := x.getPackage(pkgPath)
+			if err != nil {
+				continue
+			}
+			name = pkg.name
+		} else {
+			name = pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+		}
+		if name == alias {
+			return pkgPath, true
+		}
+	}
+	return "", false
+}
+
+// Get the value of a string constant referenced from a file in the provided package
+func (x *sourceIndex) resolveString(pkgPath string, file *ast.File, expr ast.Expr) (string, *constantDecl, error) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind != token.STRING {
+			return "", nil, fmt.Errorf("[%s] isn't a string literal", expr.Value)
+		}
+		value, err := strconv.Unquote(expr.Value)
+		return value, nil, err
+
+	case *ast.ParenExpr:
+		return x.resolveString(pkgPath, file, expr.X)
+
+	case *ast.BinaryExpr:
+		if expr.Op != token.ADD {
+			return "", nil, fmt.Errorf("unsupported constant operator [%s]", expr.Op)
+		}
+		left, _, err := x.resolveString(pkgPath, file, expr.X)
+		if err != nil {
+			return "", nil, err
+		}
+		right, _, err := x.resolveString(pkgPath, file, expr.Y)
+		if err != nil {
+			return "", nil, err
+		}
+		return left + right, nil, nil
+
+	case *ast.Ident:
+		return x.resolveConstant(pkgPath, expr.Name)
+
+	case *ast.SelectorExpr:
+		alias, isIdent := expr.X.(*ast.Ident)
+		if !isIdent {
+			break
+		}
+		importPath, found := x.resolveImport(file, alias.Name)
+		if !found {
+			return "", nil, fmt.Errorf("package [%s] isn't imported", alias.Name)
+		}
+		return x.resolveConstant(importPath, expr.Sel.Name)
+	}
+	return "", nil, fmt.Errorf("unsupported constant expression at %s", x.fset.Position(expr.Pos()))
+}
+
+// Get the value of a string constant declared in the provided package
+func (x *sourceIndex) resolveConstant(pkgPath string, name string) (string, *constantDecl, error) {
+	pkg, err := x.getPackage(pkgPath)
+	if err != nil {
+		return "", nil, err
+	}
+	decl, exists := pkg.constants[name]
+	if !exists {
+		return "", nil, fmt.Errorf("constant [%s] isn't declared in [%s]", name, pkgPath)
+	}
+	value, _, err := x.resolveString(pkgPath, decl.file, decl.value)
+	if err != nil {
+		return "", nil, fmt.Errorf("error resolving constant [%s.%s]: %w", pkgPath, name, err)
+	}
+	return value, decl, nil
+}
+
+// Get the doc comment of a function
+func (x *sourceIndex) getFuncDoc(pkgPath string, function string) (string, error) {
+	pkg, err := x.getPackage(pkgPath)
+	if err != nil {
+		return "", err
+	}
+	decl, exists := pkg.funcs[function]
+	if !exists {
+		return "", fmt.Errorf("function [%s] isn't declared in [%s]", function, pkgPath)
+	}
+	return commentText(decl.Doc), nil
+}
+
+// Get the doc comment of a type, or an empty string if it isn't in the module
+func (x *sourceIndex) getTypeDoc(pkgPath string, typeName string) string {
+	if !x.isModulePackage(pkgPath) {
+		return ""
+	}
+	pkg, err := x.getPackage(pkgPath)
+	if err != nil {
+		return ""
+	}
+	return pkg.typeDocs[typeName]
+}
+
+// Get the doc comment of a struct field, or an empty string if it isn't in the module
+func (x *sourceIndex) getFieldDoc(pkgPath string, typeName string, fieldName string) string {
+	if !x.isModulePackage(pkgPath) {
+		return ""
+	}
+	pkg, err := x.getPackage(pkgPath)
+	if err != nil {
+		return ""
+	}
+	spec, exists := pkg.types[typeName]
+	if !exists {
+		return ""
+	}
+	structType, isStruct := spec.Type.(*ast.StructType)
+	if !isStruct {
+		return ""
+	}
+	for _, field := range structType.Fields.List {
+		for _, name := range field.Names {
+			if name.Name == fieldName {
+				if field.Doc != nil {
+					return commentText(field.Doc)
+				}
+				return commentText(field.Comment)
+			}
+		}
+	}
+	return ""
+}
+
+// Get the values of the string constants declared with a named type, or nil if it isn't in the module
+func (x *sourceIndex) getEnum(pkgPath string, typeName string) []string {
+	if !x.isModulePackage(pkgPath) {
+		return nil
+	}
+	pkg, err := x.getPackage(pkgPath)
+	if err != nil {
+		return nil
+	}
+	return pkg.enums[typeName]
+}
+
+// Get the description of an error key from the doc comment of its constant
+func (x *sourceIndex) getErrorDoc(pkgPath string, file *ast.File, expr ast.Expr) string {
+	_, decl, err := x.resolveString(pkgPath, file, expr)
+	if err != nil || decl == nil {
+		return ""
+	}
+	return decl.doc
+}
+
+// Derive what a client function expects from the server by walking its source, and the source of every module function it
+// calls. Responses come from its `switch code` statements and their nested `switch response.Error` statements; the method,
+// auth requirement, and response data type come from the call to common.SubmitRequest.
+func (x *sourceIndex) getClientContract(pkgPath string, function string) (*clientContract, map[string]string, error) {
+	contract := &clientContract{
+		responses: map[int][]string{},
+	}
+	errorDocs := map[string]string{}
+	foundRequest := false
+	foundData := false
+	visited := map[string]bool{}
+
+	var walk func(pkgPath string, function string, typeParams map[string]bool) error
+	walk = func(pkgPath string, function string, typeParams map[string]bool) error {
+		key := pkgPath + "." + function
+		if visited[key] {
+			return nil
+		}
+		visited[key] = true
+
+		pkg, err := x.getPackage(pkgPath)
+		if err != nil {
+			return err
+		}
+		decl, exists := pkg.funcs[function]
+		if !exists {
+			return fmt.Errorf("function [%s] isn't declared in [%s]", function, pkgPath)
+		}
+		file := pkg.funcFiles[function]
+		if decl.Type.TypeParams != nil {
+			typeParams = map[string]bool{}
+			for _, field := range decl.Type.TypeParams.List {
+				for _, name := range field.Names {
+					typeParams[name.Name] = true
+				}
+			}
+		}
+
+		var walkErr error
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			if walkErr != nil {
+				return false
+			}
+			switch node := node.(type) {
+			case *ast.SwitchStmt:
+				if tag, isIdent := node.Tag.(*ast.Ident); isIdent && tag.Name == "code" {
+					walkErr = x.addResponses(contract, errorDocs, pkgPath, file, node)
+				}
+
+			case *ast.CallExpr:
+				callee, typeArg := splitCall(node.Fun)
+				selector, isSelector := callee.(*ast.SelectorExpr)
+				if !isSelector {
+					return true
+				}
+				alias, isIdent := selector.X.(*ast.Ident)
+				if !isIdent {
+					return true
+				}
+				importPath, found := x.resolveImport(file, alias.Name)
+				if !found || !x.isModulePackage(importPath) {
+					return true
+				}
+
+				// Get the response data type from the first generic call that names a concrete type
+				if typeArg != nil && !foundData {
+					dataPackage, dataType, concrete := x.resolveTypeArg(pkgPath, file, typeArg, typeParams)
+					if concrete {
+						contract.dataPackage = dataPackage
+						contract.dataType = dataType
+						foundData = true
+					}
+				}
+
+				// Get the method and auth requirement from the request itself
+				if selector.Sel.Name == "SubmitRequest" && !foundRequest {
+					if len(node.Args) < 5 {
+						walkErr = fmt.Errorf("unexpected SubmitRequest call at %s", x.fset.Position(node.Pos()))
+						return false
+					}
+					auth, isIdent := node.Args[3].(*ast.Ident)
+					if !isIdent || (auth.Name != "true" && auth.Name != "false") {
+						walkErr = fmt.Errorf("SubmitRequest at %s doesn't have a literal auth requirement", x.fset.Position(node.Pos()))
+						return false
+					}
+					method, isSelector := node.Args[4].(*ast.SelectorExpr)
+					if !isSelector || !strings.HasPrefix(method.Sel.Name, "Method") {
+						walkErr = fmt.Errorf("SubmitRequest at %s doesn't use a net/http method constant", x.fset.Position(node.Pos()))
+						return false
+					}
+					contract.requireAuth = auth.Name == "true"
+					contract.method = strings.ToUpper(strings.TrimPrefix(method.Sel.Name, "Method"))
+					foundRequest = true
+					return true
+				}
+
+				// Follow calls into the module's helpers, which handle the errors that are common to several routes. Anything
+				// that isn't a function, like a type conversion, is skipped.
+				calledPkg, err := x.getPackage(importPath)
+				if err != nil {
+					walkErr = err
+					return false
+				}
+				if _, exists := calledPkg.funcs[selector.Sel.Name]; !exists {
+					return true
+				}
+				walkErr = walk(importPath, selector.Sel.Name, typeParams)
+				return walkErr == nil
+			}
+			return true
+		})
+		return walkErr
+	}
+
+	err := walk(pkgPath, function, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !foundRequest {
+		return nil, nil, fmt.Errorf("[%s.%s] doesn't submit a request", pkgPath, function)
+	}
+	for status := range contract.responses {
+		sort.Strings(contract.responses[status])
+	}
+	return contract, errorDocs, nil
+}
+
+// Add the status codes and error keys handled by a `switch code` statement
+func (x *sourceIndex) addResponses(contract *clientContract, errorDocs map[string]string, pkgPath string, file *ast.File, node *ast.SwitchStmt) error {
+	for _, stmt := range node.Body.List {
+		clause := stmt.(*ast.CaseClause)
+		for _, expr := range clause.List {
+			selector, isSelector := expr.(*ast.SelectorExpr)
+			if !isSelector {
+				return fmt.Errorf("unsupported status code at %s", x.fset.Position(expr.Pos()))
+			}
+			status, known := httpStatuses[selector.Sel.Name]
+			if !known {
+				return fmt.Errorf("unknown status code [%s] at %s", selector.Sel.Name, x.fset.Position(expr.Pos()))
+			}
+			if _, exists := contract.responses[status]; !exists {
+				contract.responses[status] = []string{}
+			}
+
+			// Get the error keys handled for this status code
+			for _, body := range clause.Body {
+				inner, isSwitch := body.(*ast.SwitchStmt)
+				if !isSwitch {
+					continue
+				}
+				tag, isSelector := inner.Tag.(*ast.SelectorExpr)
+				if !isSelector || tag.Sel.Name != "Error" {
+					continue
+				}
+				for _, innerStmt := range inner.Body.List {
+					for _, keyExpr := range innerStmt.(*ast.CaseClause).List {
+						key, _, err := x.resolveString(pkgPath, file, keyExpr)
+						if err != nil {
+							return fmt.Errorf("error resolving error key: %w", err)
+						}
+						if !contains(contract.responses[status], key) {
+							contract.responses[status] = append(contract.responses[status], key)
+						}
+						if errorDocs[key] == "" {
+							errorDocs[key] = x.getErrorDoc(pkgPath, file, keyExpr)
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Split a call's function expression into the callee and its type argument, if it has one
+func splitCall(fun ast.Expr) (ast.Expr, ast.Expr) {
+	switch fun := fun.(type) {
+	case *ast.IndexExpr:
+		return fun.X, fun.Index
+	case *ast.IndexListExpr:
+		return fun.X, fun.Indices[0]
+	}
+	return fun, nil
+}
+
+// Resolve a type argument to its package and name. Returns false if the argument is one of the enclosing function's type
+// parameters, which only the caller knows.
+func (x *sourceIndex) resolveTypeArg(pkgPath string, file *ast.File, expr ast.Expr, typeParams map[string]bool) (string, string, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if typeParams[expr.Name] {
+			return "", "", false
+		}
+		return pkgPath, expr.Name, true
+
+	case *ast.SelectorExpr:
+		alias, isIdent := expr.X.(*ast.Ident)
+		if !isIdent {
+			return "", "", false
+		}
+		importPath, found := x.resolveImport(file, alias.Name)
+		if !found {
+			return "", "", false
+		}
+		return importPath, expr.Sel.Name, true
+
+	case *ast.StructType:
+		// Requests without response data use struct{}
+		return "", "", len(expr.Fields.List) == 0
+	}
+	return "", "", false
+}
+
+// Check if a slice contains a string
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
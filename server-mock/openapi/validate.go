@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Find the operation for a request, where the path includes the document's base path like [/api/v3/core/nonce]
+func (d *Document) FindOperation(method string, path string) (*Operation, error) {
+	basePath := d.GetBasePath()
+	if !strings.HasPrefix(path, basePath+"/") {
+		return nil, fmt.Errorf("path [%s] isn't under the document's base path [%s]", path, basePath)
+	}
+	segments := strings.Split(strings.TrimPrefix(path, basePath), "/")
+
+	// Find the matching template with the most literal segments, so fixed routes win over parameterized ones
+	var bestItem *PathItem
+	bestLiterals := -1
+	for template, item := range d.Paths {
+		templateSegments := strings.Split(template, "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+		literals := 0
+		matches := true
+		for i, templateSegment := range templateSegments {
+			if strings.HasPrefix(templateSegment, "{") {
+				if segments[i] == "" {
+					matches = false
+					break
+				}
+				continue
+			}
+			if templateSegment != segments[i] {
+				matches = false
+				break
+			}
+			literals++
+		}
+		if matches && literals > bestLiterals {
+			bestItem = item
+			bestLiterals = literals
+		}
+	}
+	if bestItem == nil {
+		return nil, fmt.Errorf("path [%s] isn't documented", path)
+	}
+	operation := bestItem.GetOperation(method)
+	if operation == nil {
+		return nil, fmt.Errorf("%s isn't documented for path [%s]", method, path)
+	}
+	return operation, nil
+}
+
+// Make sure a response from the server matches the document. Returns the operation the request was for.
+func (d *Document) ValidateResponse(method string, path string, statusCode int, body []byte) (*Operation, error) {
+	operation, err := d.FindOperation(method, path)
+	if err != nil {
+		return nil, err
+	}
+	response, exists := operation.Responses[strconv.Itoa(statusCode)]
+	if !exists {
+		return operation, fmt.Errorf("%s responded with undocumented status code %d: %s", operation.OperationID, statusCode, string(body))
+	}
+	media, exists := response.Content[jsonContentType]
+	if !exists {
+		return operation, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	err = decoder.Decode(&value)
+	if err != nil {
+		return operation, fmt.Errorf("%s responded with %d and a body that isn't JSON: %s", operation.OperationID, statusCode, string(body))
+	}
+	err = d.validateValue(value, media.Schema, "$")
+	if err != nil {
+		return operation, fmt.Errorf("%s responded with %d and a body that doesn't match the document: %w", operation.OperationID, statusCode, err)
+	}
+	return operation, nil
+}
+
+// Make sure a decoded JSON value matches a schema
+func (d *Document) validateValue(value any, schema *Schema, location string) error {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+		component, exists := d.Components.Schemas[name]
+		if !exists {
+			return fmt.Errorf("%s refers to missing schema [%s]", location, schema.Ref)
+		}
+		return d.validateValue(value, component, location)
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s is null", location)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, isObject := value.(map[string]any)
+		if !isObject {
+			return fmt.Errorf("%s is %T, not an object", location, value)
+		}
+		for _, name := range schema.Required {
+			if _, exists := object[name]; !exists {
+				return fmt.Errorf("%s is missing required property [%s]", location, name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertySchema, exists := schema.Properties[name]
+			if !exists {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					return fmt.Errorf("%s has undocumented property [%s]", location, name)
+				}
+				continue
+			}
+			err := d.validateValue(object[name], propertySchema, location+"."+name)
+			if err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		array, isArray := value.([]any)
+		if !isArray {
+			return fmt.Errorf("%s is %T, not an array", location, value)
+		}
+		if schema.Items == nil {
+			return nil
+		}
+		for i, item := range array {
+			err := d.validateValue(item, schema.Items, fmt.Sprintf("%s[%d]", location, i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		str, isString := value.(string)
+		if !isString {
+			return fmt.Errorf("%s is %T, not a string", location, value)
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, str) {
+			return fmt.Errorf("%s is [%s], which isn't one of %v", location, str, schema.Enum)
+		}
+		if schema.Pattern != "" {
+			pattern, err := regexp.Compile(schema.Pattern)
+			if err != nil {
+				return fmt.Errorf("%s has invalid pattern [%s]: %w", location, schema.Pattern, err)
+			}
+			if !pattern.MatchString(str) {
+				return fmt.Errorf("%s is [%s], which doesn't match [%s]", location, str, schema.Pattern)
+			}
+		}
+		if schema.Format == "date-time" {
+			_, err := time.Parse(time.RFC3339Nano, str)
+			if err != nil {
+				return fmt.Errorf("%s is [%s], which isn't a date-time", location, str)
+			}
+		}
+
+	case "integer":
+		number, isNumber := value.(json.Number)
+		if !isNumber {
+			return fmt.Errorf("%s is %T, not an integer", location, value)
+		}
+		integer, isInteger := new(big.Int).SetString(number.String(), 10)
+		if !isInteger {
+			return fmt.Errorf("%s is [%s], not an integer", location, number)
+		}
+		if schema.Minimum != nil && integer.Cmp(big.NewInt(*schema.Minimum)) < 0 {
+			return fmt.Errorf("%s is %s, which is less than %d", location, integer, *schema.Minimum)
+		}
+
+	case "number":
+		number, isNumber := value.(json.Number)
+		if !isNumber {
+			return fmt.Errorf("%s is %T, not a number", location, value)
+		}
+		_, err := number.Float64()
+		if err != nil {
+			return fmt.Errorf("%s is [%s], not a number", location, number)
+		}
+
+	case "boolean":
+		if _, isBool := value.(bool); !isBool {
+			return fmt.Errorf("%s is %T, not a boolean", location, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+package scenario
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+)
+
+const (
+	// How often the runner checks for steps that are due and new API requests
+	pollInterval time.Duration = 20 * time.Millisecond
+
+	// The max number of executions kept for status reports
+	maxExecutions int = 1000
+
+	// Prefix of the admin routes that control scenarios, which scenarios can't call themselves
+	scenarioRoutePrefix string = "scenario/"
+)
+
+// Runs scenarios against a mock's admin routes. Only one scenario runs at a time.
+type Runner struct {
+	logger     *slog.Logger
+	router     *mux.Router
+	pathPrefix string
+	journal    *manager.RequestJournal
+
+	// The scenario that's running, or the last one that ran
+	current *run
+	lock    sync.Mutex
+}
+
+// A single run of a scenario
+type run struct {
+	scenario   api.Scenario
+	steps      []*stepState
+	startTime  time.Time
+	running    bool
+	lastID     uint64
+	executions []api.ScenarioExecution
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// The progress of a step during a run
+type stepState struct {
+	step      api.ScenarioStep
+	templates []map[string]*template.Template
+	runs      int
+	nextRun   time.Time
+	pending   []pendingRun
+}
+
+// A run of a step that was triggered by a request but hasn't happened yet
+type pendingRun struct {
+	time time.Time
+	data *eventData
+}
+
+// The template data for a step's actions
+type eventData struct {
+	api.JournalEntry
+
+	// The request body, decoded from JSON
+	Body any
+}
+
+// Creates a new runner that calls admin routes with the router
+// pathPrefix: The path the router serves the admin routes under, like [/admin]
+func NewRunner(logger *slog.Logger, router *mux.Router, pathPrefix string, journal *manager.RequestJournal) *Runner {
+	return &Runner{
+		logger:     logger,
+		router:     router,
+		pathPrefix: pathPrefix,
+		journal:    journal,
+	}
+}
+
+// Start running a scenario, stopping the one that's already running if there is one
+func (r *Runner) Start(scenario api.Scenario) error {
+	steps, err := r.prepareSteps(scenario)
+	if err != nil {
+		return err
+	}
+	r.Stop()
+
+	now := time.Now()
+	current := &run{
+		scenario:   scenario,
+		steps:      steps,
+		startTime:  now,
+		running:    true,
+		lastID:     r.journal.GetLastID(),
+		executions: []api.ScenarioExecution{},
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, state := range steps {
+		switch {
+		case state.step.On != nil:
+			continue
+		case state.step.Every > 0 && state.step.After == 0:
+			state.nextRun = now.Add(time.Duration(state.step.Every))
+		default:
+			state.nextRun = now.Add(time.Duration(state.step.After))
+		}
+	}
+
+	r.lock.Lock()
+	r.current = current
+	r.lock.Unlock()
+	r.logger.Info("Started scenario", "name", scenario.Name, "steps", len(steps))
+	go r.loop(current)
+	return nil
+}
+
+// Stop the scenario that's running, if there is one
+func (r *Runner) Stop() {
+	r.lock.Lock()
+	current := r.current
+	r.lock.Unlock()
+	if current == nil {
+		return
+	}
+
+	select {
+	case <-current.done:
+		return
+	default:
+	}
+	close(current.stop)
+	<-current.done
+	r.logger.Info("Stopped scenario", "name", current.scenario.Name)
+}
+
+// Get the progress of the scenario that's running, or the last one that ran
+func (r *Runner) GetStatus() api.ScenarioStatusData {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	status := api.ScenarioStatusData{
+		Steps:      []api.ScenarioStepStatus{},
+		Executions: []api.ScenarioExecution{},
+	}
+	if r.current == nil {
+		return status
+	}
+	startTime := r.current.startTime
+	status.Name = r.current.scenario.Name
+	status.Running = r.current.running
+	status.StartTime = &startTime
+	for _, state := range r.current.steps {
+		stepStatus := api.ScenarioStepStatus{
+			Name:    state.step.Name,
+			Runs:    state.runs,
+			Pending: len(state.pending),
+			Done:    state.isDone(),
+		}
+		if !state.nextRun.IsZero() {
+			nextRun := state.nextRun
+			stepStatus.NextRun = &nextRun
+		} else if len(state.pending) > 0 {
+			nextRun := state.pending[0].time
+			stepStatus.NextRun = &nextRun
+		}
+		status.Steps = append(status.Steps, stepStatus)
+	}
+	status.Executions = append(status.Executions, r.current.executions...)
+	return status
+}
+
+// Validate a scenario's steps and parse their templates
+func (r *Runner) prepareSteps(scenario api.Scenario) ([]*stepState, error) {
+	if len(scenario.Steps) == 0 {
+		return nil, fmt.Errorf("scenario [%s] doesn't have any steps", scenario.Name)
+	}
+
+	steps := make([]*stepState, len(scenario.Steps))
+	for i, step := range scenario.Steps {
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+		if step.On != nil && step.Every != 0 {
+			return nil, fmt.Errorf("step [%s] can't have both on and every", step.Name)
+		}
+		if step.After < 0 || step.Every < 0 {
+			return nil, fmt.Errorf("step [%s] can't have a negative after or every", step.Name)
+		}
+		if step.Times < 0 {
+			return nil, fmt.Errorf("step [%s] can't have a negative times", step.Name)
+		}
+		if step.On == nil && step.Every == 0 {
+			step.Times = 1
+		}
+		if len(step.Actions) == 0 {
+			return nil, fmt.Errorf("step [%s] doesn't have any actions", step.Name)
+		}
+
+		state := &stepState{
+			step:      step,
+			templates: make([]map[string]*template.Template, len(step.Actions)),
+		}
+		for j, action := range step.Actions {
+			err := r.checkRoute(action.Route)
+			if err != nil {
+				return nil, fmt.Errorf("action %d of step [%s] is invalid: %w", j+1, step.Name, err)
+			}
+			state.templates[j] = map[string]*template.Template{}
+			for name, value := range action.Params {
+				tmpl, err := template.New(name).Parse(value)
+				if err != nil {
+					return nil, fmt.Errorf("parameter [%s] of action %d of step [%s] isn't a valid template: %w", name, j+1, step.Name, err)
+				}
+				state.templates[j][name] = tmpl
+			}
+		}
+		steps[i] = state
+	}
+	return steps, nil
+}
+
+// Make sure an action's route is an admin route that a scenario can call
+func (r *Runner) checkRoute(route string) error {
+	route = strings.TrimPrefix(route, "/")
+	if route == "" {
+		return fmt.Errorf("missing route")
+	}
+	if strings.HasPrefix(route, scenarioRoutePrefix) {
+		return fmt.Errorf("scenarios can't call the scenario routes")
+	}
+	request := httptest.NewRequest(http.MethodGet, r.pathPrefix+"/"+route, nil)
+	var match mux.RouteMatch
+	if !r.router.Match(request, &match) {
+		return fmt.Errorf("[%s] isn't an admin route", route)
+	}
+	return nil
+}
+
+// Run the scenario until it's stopped or all of its steps are done
+func (r *Runner) loop(current *run) {
+	ticker := time.NewTicker(pollInterval)
+	defer func() {
+		ticker.Stop()
+		r.lock.Lock()
+		current.running = false
+		r.lock.Unlock()
+		close(current.done)
+	}()
+
+	for {
+		select {
+		case <-current.stop:
+			return
+		case now := <-ticker.C:
+			if r.tick(current, now) {
+				r.logger.Info("Finished scenario", "name", current.scenario.Name)
+				return
+			}
+		}
+	}
+}
+
+// Queue the steps triggered by new requests and run the steps that are due. Returns true if every step is done.
+func (r *Runner) tick(current *run, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Check for new requests
+	for _, entry := range r.journal.GetEntriesAfter(current.lastID) {
+		current.lastID = entry.ID
+		for _, state := range current.steps {
+			if state.step.On == nil || !state.canTrigger() || !state.step.On.Matches(entry) {
+				continue
+			}
+			state.pending = append(state.pending, pendingRun{
+				time: now.Add(time.Duration(state.step.After)),
+				data: newEventData(entry),
+			})
+		}
+	}
+
+	// Run the steps that are due
+	allDone := true
+	for _, state := range current.steps {
+		if !state.nextRun.IsZero() && !now.Before(state.nextRun) {
+			r.runStep(current, state, &eventData{}, now)
+			state.nextRun = time.Time{}
+			if state.step.Every > 0 && !state.isDone() {
+				state.nextRun = now.Add(time.Duration(state.step.Every))
+			}
+		}
+		for len(state.pending) > 0 && !now.Before(state.pending[0].time) {
+			data := state.pending[0].data
+			state.pending = state.pending[1:]
+			r.runStep(current, state, data, now)
+		}
+		if !state.isDone() {
+			allDone = false
+		}
+	}
+	return allDone
+}
+
+// Run a step's actions in order, stopping at the first one that fails
+func (r *Runner) runStep(current *run, state *stepState, data *eventData, now time.Time) {
+	state.runs++
+	for i, action := range state.step.Actions {
+		execution := r.runAction(action, state.templates[i], data)
+		execution.Time = now
+		execution.Step = state.step.Name
+		current.executions = append(current.executions, execution)
+		if len(current.executions) > maxExecutions {
+			current.executions = current.executions[len(current.executions)-maxExecutions:]
+		}
+		if execution.StatusCode != http.StatusOK {
+			r.logger.Warn("Scenario action failed", "step", state.step.Name, "route", action.Route, "code", execution.StatusCode, "message", execution.Message)
+			return
+		}
+		r.logger.Info("Ran scenario action", "step", state.step.Name, "route", action.Route)
+	}
+}
+
+// Call an action's admin route
+func (r *Runner) runAction(action api.ScenarioAction, templates map[string]*template.Template, data *eventData) api.ScenarioExecution {
+	execution := api.ScenarioExecution{
+		Route:  strings.TrimPrefix(action.Route, "/"),
+		Params: map[string]string{},
+	}
+
+	// Fill in the parameters
+	query := url.Values{}
+	for name, tmpl := range templates {
+		var value bytes.Buffer
+		err := tmpl.Execute(&value, data)
+		if err != nil {
+			execution.Message = fmt.Sprintf("error filling in parameter [%s]: %s", name, err.Error())
+			return execution
+		}
+		execution.Params[name] = value.String()
+		query.Set(name, value.String())
+	}
+
+	// Call the route
+	request := httptest.NewRequest(http.MethodGet, r.pathPrefix+"/"+execution.Route+"?"+query.Encode(), nil)
+	recorder := httptest.NewRecorder()
+	r.router.ServeHTTP(recorder, request)
+	execution.StatusCode = recorder.Code
+	var response common.NodeSetResponse[json.RawMessage]
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	if err == nil {
+		execution.Message = response.Message
+	}
+	return execution
+}
+
+// Create the template data for a step triggered by a request
+func newEventData(entry api.JournalEntry) *eventData {
+	data := &eventData{
+		JournalEntry: entry,
+	}
+	if len(entry.Body) > 0 {
+		_ = json.Unmarshal(entry.Body, &data.Body)
+	}
+	return data
+}
+
+// Check if a request can trigger another run of a step
+func (s *stepState) canTrigger() bool {
+	return s.step.Times == 0 || s.runs+len(s.pending) < s.step.Times
+}
+
+// Check if a step has run as many times as it ever will
+func (s *stepState) isDone() bool {
+	return s.step.Times > 0 && s.runs >= s.step.Times
+}
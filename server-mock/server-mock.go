@@ -3,12 +3,17 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
-	"github.com/nodeset-org/nodeset-client-go/server-mock/server"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	nsserver "github.com/nodeset-org/nodeset-client-go/server-mock/server"
+	"github.com/rocket-pool/node-manager-core/log"
 	"github.com/urfave/cli/v2"
 )
 
@@ -46,22 +51,124 @@ func main() {
 		Value:   50512,
 	}
 
+	tlsCertFlag := &cli.StringFlag{
+		Name:  "tls-cert",
+		Usage: "Path to a PEM-encoded certificate to serve HTTPS with; requires --tls-key",
+	}
+	tlsKeyFlag := &cli.StringFlag{
+		Name:  "tls-key",
+		Usage: "Path to the PEM-encoded private key of the --tls-cert certificate",
+	}
+	tlsSelfSignedFlag := &cli.BoolFlag{
+		Name:  "tls-self-signed",
+		Usage: "Serve HTTPS with a newly generated self-signed certificate, writing its CA certificate to --tls-ca-out so clients can trust it",
+	}
+	tlsHostFlag := &cli.StringSliceFlag{
+		Name:  "tls-host",
+		Usage: "An extra DNS name or IP address for the self-signed certificate to be valid for, such as the server's docker-compose service name. localhost and the loopback addresses are always included.",
+	}
+	tlsCaOutFlag := &cli.StringFlag{
+		Name:  "tls-ca-out",
+		Usage: "The path to write the self-signed CA certificate to",
+		Value: "nodeset-mock-ca.pem",
+	}
+	drainTimeoutFlag := &cli.DurationFlag{
+		Name:  "drain-timeout",
+		Usage: "How long to wait for requests in progress to finish when shutting down before closing their connections",
+		Value: 10 * time.Second,
+	}
+	readinessGracePeriodFlag := &cli.DurationFlag{
+		Name:  "readiness-grace-period",
+		Usage: "How long to keep serving requests after the readiness probe starts failing when shutting down, before draining begins",
+		Value: 5 * time.Second,
+	}
+	scenarioFlag := &cli.StringFlag{
+		Name:  "scenario",
+		Usage: "Path to a JSON scenario file to start running as soon as the server starts",
+	}
+	logLevelFlag := &cli.StringFlag{
+		Name:  "log-level",
+		Usage: "The minimum level of messages to log (debug, info, warn, or error)",
+		Value: "info",
+	}
+	logFormatFlag := &cli.StringFlag{
+		Name:  "log-format",
+		Usage: fmt.Sprintf("The format of log messages (%s or %s)", log.LogFormat_Logfmt, log.LogFormat_Json),
+		Value: string(log.LogFormat_Logfmt),
+	}
+
 	app.Flags = []cli.Flag{
 		ipFlag,
 		portFlag,
+		tlsCertFlag,
+		tlsKeyFlag,
+		tlsSelfSignedFlag,
+		tlsHostFlag,
+		tlsCaOutFlag,
+		drainTimeoutFlag,
+		readinessGracePeriodFlag,
+		scenarioFlag,
+		logLevelFlag,
+		logFormatFlag,
 	}
 	app.Action = func(c *cli.Context) error {
-		logger := slog.Default()
+		// Create the logger
+		logger, err := createLogger(c.String(logLevelFlag.Name), log.LogFormat(c.String(logFormatFlag.Name)))
+		if err != nil {
+			return err
+		}
 
 		// Create the server
-		var err error
 		ip := c.String(ipFlag.Name)
 		port := uint16(c.Uint(portFlag.Name))
-		server, err := server.NewNodeSetMockServer(logger, ip, port)
+		server, err := nsserver.NewNodeSetMockServer(logger, ip, port)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error creating server: %v", err)
 			os.Exit(1)
 		}
+		server.SetReadinessGracePeriod(c.Duration(readinessGracePeriodFlag.Name))
+
+		// Set up TLS
+		certFile := c.String(tlsCertFlag.Name)
+		keyFile := c.String(tlsKeyFlag.Name)
+		selfSigned := c.Bool(tlsSelfSignedFlag.Name)
+		switch {
+		case selfSigned && (certFile != "" || keyFile != ""):
+			return fmt.Errorf("--%s can't be used with --%s or --%s", tlsSelfSignedFlag.Name, tlsCertFlag.Name, tlsKeyFlag.Name)
+		case (certFile == "") != (keyFile == ""):
+			return fmt.Errorf("--%s and --%s have to be provided together", tlsCertFlag.Name, tlsKeyFlag.Name)
+		case certFile != "":
+			tlsConfig, err := nsserver.LoadCertificate(certFile, keyFile)
+			if err != nil {
+				return err
+			}
+			server.SetTLSConfig(tlsConfig)
+		case selfSigned:
+			hosts := c.StringSlice(tlsHostFlag.Name)
+			if ip != "" && net.ParseIP(ip) != nil && !net.ParseIP(ip).IsUnspecified() {
+				hosts = append(hosts, ip)
+			}
+			tlsConfig, caPem, err := nsserver.GenerateSelfSignedCertificate(hosts)
+			if err != nil {
+				return err
+			}
+			caPath := c.String(tlsCaOutFlag.Name)
+			err = os.WriteFile(caPath, caPem, 0644)
+			if err != nil {
+				return fmt.Errorf("error writing CA certificate to [%s]: %w", caPath, err)
+			}
+			logger.Info("Wrote the self-signed CA certificate", "path", caPath)
+			server.SetTLSConfig(tlsConfig)
+		}
+
+		// Start the scenario
+		scenarioPath := c.String(scenarioFlag.Name)
+		if scenarioPath != "" {
+			err = startScenario(server, scenarioPath)
+			if err != nil {
+				return err
+			}
+		}
 
 		// Start it
 		wg := &sync.WaitGroup{}
@@ -78,7 +185,7 @@ func main() {
 		go func() {
 			<-termListener
 			fmt.Println("Shutting down...")
-			err := server.Stop()
+			err := server.StopWithTimeout(c.Duration(drainTimeoutFlag.Name))
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error stopping server: %v", err)
 				os.Exit(1)
@@ -86,7 +193,11 @@ func main() {
 		}()
 
 		// Run the daemon until closed
-		logger.Info(fmt.Sprintf("Started nodeset.io mock server on %s:%d", ip, port))
+		scheme := "http"
+		if server.IsTLS() {
+			scheme = "https"
+		}
+		logger.Info(fmt.Sprintf("Started nodeset.io mock server on %s://%s:%d", scheme, ip, port))
 		wg.Wait()
 		fmt.Println("Server stopped.")
 		return nil
@@ -98,3 +209,43 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// Load a scenario file and start running it on the root of the server
+func startScenario(server *nsserver.NodeSetMockServer, path string) error {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading scenario file [%s]: %w", path, err)
+	}
+	var scenario api.Scenario
+	err = json.Unmarshal(bytes, &scenario)
+	if err != nil {
+		return fmt.Errorf("error deserializing scenario file [%s]: %w", path, err)
+	}
+	err = server.GetScenarioRunner().Start(scenario)
+	if err != nil {
+		return fmt.Errorf("error starting scenario [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Create a logger that writes to the terminal with the provided level and format
+func createLogger(level string, format log.LogFormat) (*slog.Logger, error) {
+	var logLevel slog.Level
+	err := logLevel.UnmarshalText([]byte(level))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level [%s]", level)
+	}
+	options := &slog.HandlerOptions{
+		Level:       logLevel,
+		ReplaceAttr: log.ReplaceTime,
+	}
+
+	switch format {
+	case log.LogFormat_Logfmt:
+		return slog.New(slog.NewTextHandler(os.Stdout, options)), nil
+	case log.LogFormat_Json:
+		return slog.New(slog.NewJSONHandler(os.Stdout, options)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format [%s]", format)
+	}
+}
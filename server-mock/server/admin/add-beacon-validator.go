@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Add a validator to the simulated Beacon chain as pending
+func (s *AdminServer) addBeaconValidator(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	pubkeyString := query.Get("pubkey")
+	if pubkeyString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing pubkey query parameter"))
+		return
+	}
+	pubkey, err := beacon.HexToValidatorPubkey(pubkeyString)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid pubkey query parameter"))
+		return
+	}
+
+	// Add the validator
+	db := s.manager.GetDatabase()
+	validator := db.Beacon.AddValidator(pubkey)
+	s.logger.Info("Added validator to Beacon",
+		"pubkey", pubkey.Hex(),
+		"index", validator.Index,
+		"activationEpoch", validator.ActivationEpoch,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Advance the simulated Beacon chain's clock by a number of slots or epochs
+func (s *AdminServer) advanceBeaconClock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	slotsString := query.Get("slots")
+	epochsString := query.Get("epochs")
+	if slotsString == "" && epochsString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing slots or epochs query parameter"))
+		return
+	}
+	if slotsString != "" && epochsString != "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("only one of slots or epochs can be provided"))
+		return
+	}
+
+	// Advance the clock
+	db := s.manager.GetDatabase()
+	if slotsString != "" {
+		slots, err := strconv.ParseUint(slotsString, 10, 64)
+		if err != nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("error parsing slots: %w", err))
+			return
+		}
+		db.Beacon.AdvanceSlots(slots)
+	} else {
+		epochs, err := strconv.ParseUint(epochsString, 10, 64)
+		if err != nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("error parsing epochs: %w", err))
+			return
+		}
+		db.Beacon.AdvanceEpochs(epochs)
+	}
+	s.logger.Info("Advanced Beacon clock",
+		"slot", db.Beacon.GetCurrentSlot(),
+		"epoch", db.Beacon.GetCurrentEpoch(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
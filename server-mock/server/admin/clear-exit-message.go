@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Remove the exit message uploaded for a validator in a StakeWise vault
+func (s *AdminServer) clearStakeWiseExitMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	vaultString := query.Get("vault")
+	if vaultString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing vault query parameter"))
+		return
+	}
+	vaultAddress := ethcommon.HexToAddress(vaultString)
+	pubkey, err := beacon.HexToValidatorPubkey(query.Get("pubkey"))
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid pubkey query parameter"))
+		return
+	}
+
+	// Clear the exit message
+	db := s.manager.GetDatabase()
+	deployment := db.StakeWise.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	vault := deployment.GetVault(vaultAddress)
+	if vault == nil {
+		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
+		return
+	}
+	validator := vault.GetStakeWiseValidator(pubkey)
+	if validator == nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("validator [%s] not found", pubkey.HexWithPrefix()))
+		return
+	}
+	validator.ClearExitMessage()
+	s.logger.Info("Cleared StakeWise exit message",
+		"deployment", deploymentID,
+		"vault", vaultAddress.Hex(),
+		"pubkey", pubkey.HexWithPrefix(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Remove the exit message uploaded for a Constellation validator
+func (s *AdminServer) clearConstellationExitMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	pubkey, err := beacon.HexToValidatorPubkey(query.Get("pubkey"))
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid pubkey query parameter"))
+		return
+	}
+
+	// Clear the exit message
+	db := s.manager.GetDatabase()
+	deployment := db.Constellation.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	err = deployment.ClearExitMessage(pubkey)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Cleared Constellation exit message",
+		"deployment", deploymentID,
+		"pubkey", pubkey.HexWithPrefix(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
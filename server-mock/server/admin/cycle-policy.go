@@ -0,0 +1,143 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Set the policy for cycling a StakeWise vault's deposit data sets automatically
+func (s *AdminServer) setStakeWiseCyclePolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID, vaultAddress, err := getCyclePolicyVault(query.Get("deployment"), query.Get("vault"))
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	intervalString := query.Get("interval")
+	if intervalString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing interval query parameter"))
+		return
+	}
+	interval, err := time.ParseDuration(intervalString)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("error parsing interval: %w", err))
+		return
+	}
+	userLimitString := query.Get("user-limit")
+	if userLimitString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing user-limit query parameter"))
+		return
+	}
+	userLimit, err := strconv.ParseInt(userLimitString, 10, 32)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("error parsing user-limit: %w", err))
+		return
+	}
+	var minPending int64
+	minPendingString := query.Get("min-pending")
+	if minPendingString != "" {
+		minPending, err = strconv.ParseInt(minPendingString, 10, 32)
+		if err != nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("error parsing min-pending: %w", err))
+			return
+		}
+	}
+	var activationDelay time.Duration
+	activationDelayString := query.Get("activation-delay")
+	if activationDelayString != "" {
+		activationDelay, err = time.ParseDuration(activationDelayString)
+		if err != nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("error parsing activation-delay: %w", err))
+			return
+		}
+	}
+
+	// Make sure the vault exists
+	db := s.manager.GetDatabase()
+	deployment := db.StakeWise.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	if deployment.GetVault(vaultAddress) == nil {
+		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
+		return
+	}
+
+	// Set the policy
+	err = s.manager.GetDepositDataCycler().SetPolicy(deploymentID, vaultAddress, api.StakeWiseCyclePolicy{
+		Interval:        api.Duration(interval),
+		UserLimit:       int(userLimit),
+		MinPending:      int(minPending),
+		ActivationDelay: api.Duration(activationDelay),
+	})
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Set deposit data set cycle policy",
+		"deployment", deploymentID,
+		"vault", vaultAddress.Hex(),
+		"interval", interval,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Stop cycling a StakeWise vault's deposit data sets automatically
+func (s *AdminServer) clearStakeWiseCyclePolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID, vaultAddress, err := getCyclePolicyVault(query.Get("deployment"), query.Get("vault"))
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+
+	err = s.manager.GetDepositDataCycler().ClearPolicy(deploymentID, vaultAddress)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Cleared deposit data set cycle policy", "deployment", deploymentID, "vault", vaultAddress.Hex())
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Get the progress of every vault's deposit data set cycle policy
+func (s *AdminServer) getStakeWiseCyclePolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	common.HandleSuccess(w, s.logger, api.StakeWiseCyclePoliciesData{
+		Policies: s.manager.GetDepositDataCycler().GetPolicies(),
+	})
+}
+
+// Parse the vault a cycle policy request is for
+func getCyclePolicyVault(deploymentID string, vaultAddressString string) (string, ethcommon.Address, error) {
+	if deploymentID == "" {
+		return "", ethcommon.Address{}, fmt.Errorf("missing deployment query parameter")
+	}
+	if vaultAddressString == "" {
+		return "", ethcommon.Address{}, fmt.Errorf("missing vault query parameter")
+	}
+	return deploymentID, ethcommon.HexToAddress(vaultAddressString), nil
+}
@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Make the Beacon deposit for a validator registered with a StakeWise vault
+func (s *AdminServer) depositValidator(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	vaultAddressString := query.Get("vault")
+	if vaultAddressString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing vault query parameter"))
+		return
+	}
+	vaultAddress := ethcommon.HexToAddress(vaultAddressString)
+	pubkeyString := query.Get("pubkey")
+	if pubkeyString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing pubkey query parameter"))
+		return
+	}
+	pubkey, err := beacon.HexToValidatorPubkey(pubkeyString)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid pubkey query parameter"))
+		return
+	}
+
+	// Make the deposit
+	db := s.manager.GetDatabase()
+	deployment := db.StakeWise.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	vault := deployment.GetVault(vaultAddress)
+	if vault == nil {
+		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
+		return
+	}
+	err = vault.DepositValidator(pubkey)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Deposited validator",
+		"vault", vaultAddress.Hex(),
+		"pubkey", pubkey.Hex(),
+		"depositRoot", db.Eth.GetDepositRoot().Hex(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
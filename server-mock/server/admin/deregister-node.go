@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Deregister a node, leaving it whitelisted on its user account
+func (s *AdminServer) deregisterNode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	addressString := query.Get("address")
+	if addressString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing address query parameter"))
+		return
+	}
+	address := ethcommon.HexToAddress(addressString)
+
+	// Deregister the node
+	db := s.manager.GetDatabase()
+	node, _ := db.Core.GetNode(address)
+	if node == nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("node [%s] not found", address.Hex()))
+		return
+	}
+	err := node.Deregister()
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Deregistered node", "address", address.Hex())
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -0,0 +1,20 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Expire all of the sessions, so every node has to log in again
+func (s *AdminServer) expireSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	db := s.manager.GetDatabase()
+	count := db.Core.ExpireSessions()
+	s.logger.Info("Expired sessions", "count", count)
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -0,0 +1,24 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Get the Beacon deposit contract's current deposit root
+func (s *AdminServer) getDepositRoot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	db := s.manager.GetDatabase()
+	data := api.AdminDepositRootData{
+		DepositRoot:  db.Eth.GetDepositRoot(),
+		DepositCount: db.Eth.GetDepositCount(),
+		BlockNumber:  db.Eth.GetBlockNumber(),
+	}
+	common.HandleSuccess(w, s.logger, data)
+}
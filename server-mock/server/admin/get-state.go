@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Get the state of the database. The user, node, vault, and pubkey query parameters are optional and restrict the
+// result to the entities that match them.
+func (s *AdminServer) getState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	filter := api.StateFilter{
+		User: query.Get("user"),
+	}
+	if nodeString := query.Get("node"); nodeString != "" {
+		if !ethcommon.IsHexAddress(nodeString) {
+			common.HandleInputError(w, s.logger, fmt.Errorf("invalid node query parameter"))
+			return
+		}
+		node := ethcommon.HexToAddress(nodeString)
+		filter.Node = &node
+	}
+	if vaultString := query.Get("vault"); vaultString != "" {
+		if !ethcommon.IsHexAddress(vaultString) {
+			common.HandleInputError(w, s.logger, fmt.Errorf("invalid vault query parameter"))
+			return
+		}
+		vault := ethcommon.HexToAddress(vaultString)
+		filter.Vault = &vault
+	}
+	if pubkeyString := query.Get("pubkey"); pubkeyString != "" {
+		pubkey, err := beacon.HexToValidatorPubkey(pubkeyString)
+		if err != nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("invalid pubkey query parameter"))
+			return
+		}
+		filter.Pubkey = &pubkey
+	}
+
+	// Get the state
+	db := s.manager.GetDatabase()
+	state, err := db.GetState(filter)
+	if err != nil {
+		common.HandleServerError(w, s.logger, err)
+		return
+	}
+	common.HandleSuccess(w, s.logger, state)
+}
@@ -36,7 +36,11 @@ func (s *AdminServer) incrementSuperNodeNonce(w http.ResponseWriter, r *http.Req
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
-	deployment.IncrementSuperNodeNonce(address)
+	err := deployment.TryIncrementSuperNodeNonce(address)
+	if err != nil {
+		common.HandleServerError(w, s.logger, fmt.Errorf("error incrementing supernode nonce: %w", err))
+		return
+	}
 	s.logger.Info("SuperNode nonce incremented", "address", address.Hex())
 	common.HandleSuccess(w, s.logger, "")
 }
@@ -36,7 +36,11 @@ func (s *AdminServer) incrementWhitelistNonce(w http.ResponseWriter, r *http.Req
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
-	deployment.IncrementWhitelistNonce(address)
+	err := deployment.TryIncrementWhitelistNonce(address)
+	if err != nil {
+		common.HandleServerError(w, s.logger, fmt.Errorf("error incrementing whitelist nonce: %w", err))
+		return
+	}
 	s.logger.Info("Whitelist nonce incremented", "address", address.Hex())
 	common.HandleSuccess(w, s.logger, "")
 }
@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Get the API requests recorded in the journal. The method, route, node, status, and error query parameters are
+// optional and restrict the result to the requests that match them.
+func (s *AdminServer) getJournal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	filter := api.JournalFilter{
+		Method: query.Get("method"),
+		Route:  query.Get("route"),
+		Error:  query.Get("error"),
+	}
+	if nodeString := query.Get("node"); nodeString != "" {
+		if !ethcommon.IsHexAddress(nodeString) {
+			common.HandleInputError(w, s.logger, fmt.Errorf("invalid node query parameter"))
+			return
+		}
+		node := ethcommon.HexToAddress(nodeString)
+		filter.Node = &node
+	}
+	if statusString := query.Get("status"); statusString != "" {
+		status, err := strconv.Atoi(statusString)
+		if err != nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("invalid status query parameter"))
+			return
+		}
+		filter.StatusCode = status
+	}
+
+	// Get the entries
+	journal := s.manager.GetJournal()
+	data := api.JournalData{
+		Capacity: journal.GetCapacity(),
+		Entries:  journal.GetEntries(filter),
+	}
+	common.HandleSuccess(w, s.logger, data)
+}
+
+// Remove all of the API requests recorded in the journal. The optional capacity query parameter sets the max number
+// of requests the journal holds from now on.
+func (s *AdminServer) clearJournal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	capacityString := query.Get("capacity")
+	var capacity int
+	if capacityString != "" {
+		var err error
+		capacity, err = strconv.Atoi(capacityString)
+		if err != nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("invalid capacity query parameter"))
+			return
+		}
+	}
+
+	// Clear the journal
+	journal := s.manager.GetJournal()
+	if capacityString != "" {
+		err := journal.SetCapacity(capacity)
+		if err != nil {
+			common.HandleInputError(w, s.logger, err)
+			return
+		}
+	}
+	journal.Clear()
+	s.logger.Info("Cleared request journal", "capacity", journal.GetCapacity())
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Remove a user's node from a Constellation deployment's whitelist
+func (s *AdminServer) removeConstellationWhitelistedNode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	email := query.Get("email")
+	if email == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing email query parameter"))
+		return
+	}
+
+	// Remove the whitelist entry
+	db := s.manager.GetDatabase()
+	deployment := db.Constellation.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	err := deployment.RemoveWhitelistedUser(email)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Removed node from Constellation whitelist",
+		"deployment", deploymentID,
+		"email", email,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Remove a StakeWise deployment and all of its vaults
+func (s *AdminServer) removeStakeWiseDeployment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	id := query.Get("id")
+	if id == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing id query parameter"))
+		return
+	}
+
+	// Remove the deployment
+	db := s.manager.GetDatabase()
+	err := db.StakeWise.RemoveDeployment(id)
+	if err != nil {
+		common.HandleInvalidDeployment(w, s.logger, id)
+		return
+	}
+	s.logger.Info("Removed StakeWise deployment", "id", id)
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Remove a Constellation deployment
+func (s *AdminServer) removeConstellationDeployment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	id := query.Get("id")
+	if id == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing id query parameter"))
+		return
+	}
+
+	// Remove the deployment
+	db := s.manager.GetDatabase()
+	err := db.Constellation.RemoveDeployment(id)
+	if err != nil {
+		common.HandleInvalidDeployment(w, s.logger, id)
+		return
+	}
+	s.logger.Info("Removed Constellation deployment", "id", id)
+	common.HandleSuccess(w, s.logger, "")
+}
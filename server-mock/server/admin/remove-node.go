@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Remove a node from a user account's whitelist
+func (s *AdminServer) removeNode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	email := query.Get("email")
+	if email == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing email query parameter"))
+		return
+	}
+	addressString := query.Get("address")
+	if addressString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing address query parameter"))
+		return
+	}
+	address := ethcommon.HexToAddress(addressString)
+
+	// Remove the node
+	db := s.manager.GetDatabase()
+	user := db.Core.GetUser(email)
+	if user == nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("user [%s] not found", email))
+		return
+	}
+	err := user.RemoveNode(address)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Removed node account",
+		"email", email,
+		"address", address.Hex(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Remove a StakeWise vault and all of its validators
+func (s *AdminServer) removeStakeWiseVault(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	addressString := query.Get("address")
+	if addressString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing address query parameter"))
+		return
+	}
+	address := ethcommon.HexToAddress(addressString)
+
+	// Remove the vault
+	db := s.manager.GetDatabase()
+	deployment := db.StakeWise.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	err := deployment.RemoveVault(address)
+	if err != nil {
+		common.HandleInvalidVault(w, s.logger, deploymentID, address)
+		return
+	}
+	s.logger.Info("Removed StakeWise vault",
+		"deployment", deploymentID,
+		"address", address.Hex(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
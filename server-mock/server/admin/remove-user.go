@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Remove a user account, along with its nodes and their sessions
+func (s *AdminServer) removeUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	email := query.Get("email")
+	if email == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing email query parameter"))
+		return
+	}
+
+	// Remove the user
+	db := s.manager.GetDatabase()
+	err := db.Core.RemoveUser(email)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Removed user", "email", email)
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Start running the scenario in the request body, replacing the one that's running
+func (s *AdminServer) startScenario(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("error reading request body: %w", err))
+		return
+	}
+	var scenario api.Scenario
+	err = json.Unmarshal(bodyBytes, &scenario)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("error deserializing scenario: %w", err))
+		return
+	}
+
+	err = s.scenarioRunner.Start(scenario)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Stop the scenario that's running
+func (s *AdminServer) stopScenario(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	s.scenarioRunner.Stop()
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Get the progress of the scenario that's running, or the last one that ran
+func (s *AdminServer) getScenarioStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	common.HandleSuccess(w, s.logger, s.scenarioRunner.GetStatus())
+}
@@ -6,12 +6,21 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/scenario"
+)
+
+const (
+	// The path the admin routes are registered under
+	adminPathPrefix string = "/admin"
 )
 
 // Admin routes for the server mock
 type AdminServer struct {
 	logger  *slog.Logger
 	manager *manager.NodeSetMockManager
+
+	// Runs scenarios against the admin routes; created when the routes are registered
+	scenarioRunner *scenario.Runner
 }
 
 // Creates a new API v0 server mock
@@ -32,8 +41,14 @@ func (s *AdminServer) GetManager() *manager.NodeSetMockManager {
 	return s.manager
 }
 
-// Registers the routes for the server
+// Gets the scenario runner
+func (s *AdminServer) GetScenarioRunner() *scenario.Runner {
+	return s.scenarioRunner
+}
+
+// Registers the routes for the server. The router has to serve them under [/admin], so scenarios can call them.
 func (s *AdminServer) RegisterRoutes(adminRouter *mux.Router) {
+	s.scenarioRunner = scenario.NewRunner(s.logger, adminRouter, adminPathPrefix, s.manager.GetJournal())
 	adminRouter.HandleFunc("/"+api.AdminAddConstellationDeploymentPath, s.addConstellationDeployment)
 	adminRouter.HandleFunc("/"+api.AdminAddStakeWiseDeploymentPath, s.addStakeWiseDeployment)
 	adminRouter.HandleFunc("/"+api.AdminAddStakeWiseVaultPath, s.addStakeWiseVault)
@@ -48,4 +63,40 @@ func (s *AdminServer) RegisterRoutes(adminRouter *mux.Router) {
 	adminRouter.HandleFunc("/"+api.AdminIncrementSuperNodeNoncePath, s.incrementSuperNodeNonce)
 	adminRouter.HandleFunc("/"+api.AdminSetEncryptionKeyPath, s.setNodeSetEncryptionKey)
 	adminRouter.HandleFunc("/"+api.AdminConstellationSetValidatorForMinipool, s.setValidatorForMinipool)
+	adminRouter.HandleFunc("/"+api.AdminBeaconAdvanceClockPath, s.advanceBeaconClock)
+	adminRouter.HandleFunc("/"+api.AdminBeaconAddValidatorPath, s.addBeaconValidator)
+	adminRouter.HandleFunc("/"+api.AdminBeaconExitValidatorPath, s.exitBeaconValidator)
+	adminRouter.HandleFunc("/"+api.AdminBeaconSlashValidatorPath, s.slashBeaconValidator)
+	adminRouter.HandleFunc("/"+api.AdminEthDepositRootPath, s.getDepositRoot)
+	adminRouter.HandleFunc("/"+api.AdminEthDepositPath, s.depositValidator)
+	adminRouter.HandleFunc("/"+api.AdminSetConstellationChainBackendPath, s.setConstellationChainBackend)
+	adminRouter.HandleFunc("/"+api.AdminStatePath, s.getState)
+	adminRouter.HandleFunc("/"+api.AdminJournalPath, s.getJournal)
+	adminRouter.HandleFunc("/"+api.AdminClearJournalPath, s.clearJournal)
+	adminRouter.HandleFunc("/"+api.AdminListSnapshotsPath, s.listSnapshots)
+	adminRouter.HandleFunc("/"+api.AdminDeleteSnapshotPath, s.deleteSnapshot)
+	adminRouter.HandleFunc("/"+api.AdminExportSnapshotPath, s.exportSnapshot)
+	adminRouter.HandleFunc("/"+api.AdminImportSnapshotPath, s.importSnapshot)
+	adminRouter.HandleFunc("/"+api.AdminDiffSnapshotsPath, s.diffSnapshots)
+	adminRouter.HandleFunc("/"+api.AdminRemoveUserPath, s.removeUser)
+	adminRouter.HandleFunc("/"+api.AdminRemoveNodePath, s.removeNode)
+	adminRouter.HandleFunc("/"+api.AdminDeregisterNodePath, s.deregisterNode)
+	adminRouter.HandleFunc("/"+api.AdminRemoveStakeWiseDeploymentPath, s.removeStakeWiseDeployment)
+	adminRouter.HandleFunc("/"+api.AdminRemoveConstellationDeploymentPath, s.removeConstellationDeployment)
+	adminRouter.HandleFunc("/"+api.AdminRemoveStakeWiseVaultPath, s.removeStakeWiseVault)
+	adminRouter.HandleFunc("/"+api.AdminSetMaxValidatorsPerUserPath, s.setMaxValidatorsPerUser)
+	adminRouter.HandleFunc("/"+api.AdminStakeWiseClearExitMessagePath, s.clearStakeWiseExitMessage)
+	adminRouter.HandleFunc("/"+api.AdminRemoveConstellationWhitelistedNodePath, s.removeConstellationWhitelistedNode)
+	adminRouter.HandleFunc("/"+api.AdminConstellationClearExitMessagePath, s.clearConstellationExitMessage)
+	adminRouter.HandleFunc("/"+api.AdminSetPermissionPath, s.setPermission)
+	adminRouter.HandleFunc("/"+api.AdminSetStakeWiseVaultBalancePath, s.setStakeWiseVaultBalance)
+	adminRouter.HandleFunc("/"+api.AdminSetMinipoolLimitPath, s.setMinipoolLimit)
+	adminRouter.HandleFunc("/"+api.AdminSetRequireExitMessagesPath, s.setRequireExitMessages)
+	adminRouter.HandleFunc("/"+api.AdminExpireSessionsPath, s.expireSessions)
+	adminRouter.HandleFunc("/"+api.AdminStartScenarioPath, s.startScenario)
+	adminRouter.HandleFunc("/"+api.AdminStopScenarioPath, s.stopScenario)
+	adminRouter.HandleFunc("/"+api.AdminScenarioStatusPath, s.getScenarioStatus)
+	adminRouter.HandleFunc("/"+api.AdminSetStakeWiseCyclePolicyPath, s.setStakeWiseCyclePolicy)
+	adminRouter.HandleFunc("/"+api.AdminClearStakeWiseCyclePolicyPath, s.clearStakeWiseCyclePolicy)
+	adminRouter.HandleFunc("/"+api.AdminStakeWiseCyclePoliciesPath, s.getStakeWiseCyclePolicies)
 }
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Set the backend a Constellation deployment uses to read state from the Execution layer.
+// Without an rpc query parameter, the deployment goes back to the in-memory backend.
+func (s *AdminServer) setConstellationChainBackend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	rpcUrl := query.Get("rpc")
+	minipoolManagerString := query.Get("minipool-manager")
+	if rpcUrl != "" && minipoolManagerString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing minipool-manager query parameter"))
+		return
+	}
+
+	// Set the backend
+	nsDB := s.manager.GetDatabase()
+	deployment := nsDB.Constellation.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	if rpcUrl == "" {
+		deployment.SetChainBackend(db.NewMemoryConstellationBackend())
+		s.logger.Info("Constellation deployment now uses the in-memory chain backend", "deployment", deploymentID)
+		common.HandleSuccess(w, s.logger, "")
+		return
+	}
+	minipoolManager := ethcommon.HexToAddress(minipoolManagerString)
+	backend, err := db.NewRpcConstellationBackend(r.Context(), rpcUrl, deployment.WhitelistAddress, deployment.SuperNodeAddress, minipoolManager)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	deployment.SetChainBackend(backend)
+	s.logger.Info("Constellation deployment now uses an RPC chain backend",
+		"deployment", deploymentID,
+		"rpc", rpcUrl,
+		"minipoolManager", minipoolManager.Hex(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -3,6 +3,7 @@ package admin
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
@@ -21,7 +22,7 @@ func (s *AdminServer) setConstellationAdminPrivateKey(w http.ResponseWriter, r *
 	_, _ = common.ProcessApiRequest(s, w, r, &request)
 
 	// Decode the key
-	privateKey, err := crypto.HexToECDSA(request.PrivateKey)
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(request.PrivateKey, "0x"))
 	if err != nil {
 		common.HandleInputError(w, s.logger, fmt.Errorf("invalid private key"))
 		return
@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Set the max number of validators each user can have in a StakeWise vault
+func (s *AdminServer) setMaxValidatorsPerUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	vaultString := query.Get("vault")
+	if vaultString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing vault query parameter"))
+		return
+	}
+	vaultAddress := ethcommon.HexToAddress(vaultString)
+	maxString := query.Get("max")
+	if maxString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing max query parameter"))
+		return
+	}
+	max, err := strconv.Atoi(maxString)
+	if err != nil || max < 0 {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid max query parameter"))
+		return
+	}
+
+	// Set the limit
+	db := s.manager.GetDatabase()
+	deployment := db.StakeWise.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	vault := deployment.GetVault(vaultAddress)
+	if vault == nil {
+		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
+		return
+	}
+	vault.MaxValidatorsPerUser = max
+	s.logger.Info("Set max validators per user",
+		"deployment", deploymentID,
+		"vault", vaultAddress.Hex(),
+		"max", max,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
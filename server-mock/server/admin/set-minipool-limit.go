@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Set the max number of minipools nodes can create on a Constellation deployment. With an email or node, the limit only
+// applies to that user or node, and leaving the limit out removes their override.
+func (s *AdminServer) setMinipoolLimit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	email := query.Get("email")
+	nodeString := query.Get("node")
+	if email != "" && nodeString != "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("only one of the email and node query parameters can be provided"))
+		return
+	}
+	limitString := query.Get("limit")
+	if limitString == "" && email == "" && nodeString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing limit query parameter"))
+		return
+	}
+	limit := 0
+	if limitString != "" {
+		var err error
+		limit, err = strconv.Atoi(limitString)
+		if err != nil || limit < 0 {
+			common.HandleInputError(w, s.logger, fmt.Errorf("invalid limit query parameter"))
+			return
+		}
+	}
+
+	// Set the limit
+	db := s.manager.GetDatabase()
+	deployment := db.Constellation.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	switch {
+	case email != "":
+		if db.Core.GetUser(email) == nil {
+			common.HandleInputError(w, s.logger, fmt.Errorf("user [%s] doesn't exist", email))
+			return
+		}
+		if limitString == "" {
+			deployment.RemoveUserMinipoolLimit(email)
+		} else {
+			deployment.SetUserMinipoolLimit(email, limit)
+		}
+	case nodeString != "":
+		nodeAddress := ethcommon.HexToAddress(nodeString)
+		if limitString == "" {
+			deployment.RemoveNodeMinipoolLimit(nodeAddress)
+		} else {
+			deployment.SetNodeMinipoolLimit(nodeAddress, limit)
+		}
+	default:
+		deployment.MinipoolLimit = limit
+	}
+	s.logger.Info("Set Constellation minipool limit",
+		"deployment", deploymentID,
+		"email", email,
+		"node", nodeString,
+		"limit", limitString,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
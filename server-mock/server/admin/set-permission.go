@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Grant or revoke a user's permission to use a module, one of its deployments, or a StakeWise vault
+func (s *AdminServer) setPermission(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	email := query.Get("email")
+	if email == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing email query parameter"))
+		return
+	}
+	permission := api.Permission{
+		Module:     query.Get("module"),
+		Deployment: query.Get("deployment"),
+	}
+	if permission.Module == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing module query parameter"))
+		return
+	}
+	vaultString := query.Get("vault")
+	if vaultString != "" {
+		vaultAddress := ethcommon.HexToAddress(vaultString)
+		permission.Vault = &vaultAddress
+	}
+	permittedString := query.Get("permitted")
+	if permittedString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing permitted query parameter"))
+		return
+	}
+	permitted, err := strconv.ParseBool(permittedString)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid permitted query parameter"))
+		return
+	}
+
+	// Set the permission
+	db := s.manager.GetDatabase()
+	user := db.Core.GetUser(email)
+	if user == nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("user [%s] doesn't exist", email))
+		return
+	}
+	err = user.SetPermission(permission, permitted)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Set user permission",
+		"email", email,
+		"module", permission.Module,
+		"deployment", permission.Deployment,
+		"vault", vaultString,
+		"permitted", permitted,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
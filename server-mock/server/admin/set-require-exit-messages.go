@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Set whether Constellation nodes need signed exits for their previous minipools before they can create a new one
+func (s *AdminServer) setRequireExitMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	requiredString := query.Get("required")
+	if requiredString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing required query parameter"))
+		return
+	}
+	required, err := strconv.ParseBool(requiredString)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid required query parameter"))
+		return
+	}
+
+	// Set the rule
+	db := s.manager.GetDatabase()
+	deployment := db.Constellation.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	deployment.RequireExitMessages = required
+	s.logger.Info("Set Constellation exit message requirement",
+		"deployment", deploymentID,
+		"required", required,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
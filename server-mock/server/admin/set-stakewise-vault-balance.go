@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Set the ETH balance, in wei, that a StakeWise vault has available for new validator deposits
+func (s *AdminServer) setStakeWiseVaultBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	deploymentID := query.Get("deployment")
+	if deploymentID == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing deployment query parameter"))
+		return
+	}
+	vaultString := query.Get("vault")
+	if vaultString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing vault query parameter"))
+		return
+	}
+	vaultAddress := ethcommon.HexToAddress(vaultString)
+	balanceString := query.Get("balance")
+	if balanceString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing balance query parameter"))
+		return
+	}
+	balance, success := new(big.Int).SetString(balanceString, 10)
+	if !success || balance.Sign() < 0 {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid balance query parameter"))
+		return
+	}
+
+	// Set the balance
+	db := s.manager.GetDatabase()
+	deployment := db.StakeWise.GetDeployment(deploymentID)
+	if deployment == nil {
+		common.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	vault := deployment.GetVault(vaultAddress)
+	if vault == nil {
+		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
+		return
+	}
+	vault.Balance = balance
+	s.logger.Info("Set StakeWise vault balance",
+		"deployment", deploymentID,
+		"vault", vaultAddress.Hex(),
+		"balance", balance.String(),
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
@@ -47,7 +47,11 @@ func (s *AdminServer) setValidatorForMinipool(w http.ResponseWriter, r *http.Req
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
-	deployment.SetValidatorInfoForMinipool(minipool, pubkey)
+	err = deployment.TrySetValidatorInfoForMinipool(minipool, pubkey)
+	if err != nil {
+		common.HandleServerError(w, s.logger, fmt.Errorf("error setting validator for minipool: %w", err))
+		return
+	}
 	s.logger.Info("Validator is now owned by minipool",
 		"deployment", deploymentID,
 		"minipool", minipool.Hex(),
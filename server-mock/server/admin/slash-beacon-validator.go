@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+	"github.com/rocket-pool/node-manager-core/beacon"
+)
+
+// Slash a validator on the simulated Beacon chain
+func (s *AdminServer) slashBeaconValidator(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	pubkeyString := query.Get("pubkey")
+	if pubkeyString == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing pubkey query parameter"))
+		return
+	}
+	pubkey, err := beacon.HexToValidatorPubkey(pubkeyString)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("invalid pubkey query parameter"))
+		return
+	}
+
+	// Slash the validator
+	db := s.manager.GetDatabase()
+	err = db.Beacon.SlashValidator(pubkey)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	validator := db.Beacon.GetValidator(pubkey)
+	s.logger.Info("Slashed validator on Beacon",
+		"pubkey", pubkey.Hex(),
+		"index", validator.Index,
+		"exitEpoch", validator.ExitEpoch,
+	)
+	common.HandleSuccess(w, s.logger, "")
+}
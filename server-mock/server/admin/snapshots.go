@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// List the snapshots, oldest first
+func (s *AdminServer) listSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	data := api.SnapshotsData{
+		Snapshots: s.manager.GetSnapshots(),
+	}
+	common.HandleSuccess(w, s.logger, data)
+}
+
+// Delete a snapshot
+func (s *AdminServer) deleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	snapshotName := r.URL.Query().Get("name")
+	if snapshotName == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing snapshot name"))
+		return
+	}
+	err := s.manager.DeleteSnapshot(snapshotName)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Write a snapshot to a file on the server's machine
+func (s *AdminServer) exportSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	snapshotName := query.Get("name")
+	if snapshotName == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing snapshot name"))
+		return
+	}
+	path := query.Get("path")
+	if path == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing path query parameter"))
+		return
+	}
+
+	err := s.manager.ExportSnapshot(snapshotName, path)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Load a snapshot from a file on the server's machine
+func (s *AdminServer) importSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	// Input validation
+	query := r.URL.Query()
+	snapshotName := query.Get("name")
+	if snapshotName == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing snapshot name"))
+		return
+	}
+	path := query.Get("path")
+	if path == "" {
+		common.HandleInputError(w, s.logger, fmt.Errorf("missing path query parameter"))
+		return
+	}
+
+	err := s.manager.ImportSnapshot(snapshotName, path)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Get the changes between two snapshots. If from or to is omitted, the live database is used in its place.
+func (s *AdminServer) diffSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	query := r.URL.Query()
+	diff, err := s.manager.DiffSnapshots(query.Get("from"), query.Get("to"))
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	common.HandleSuccess(w, s.logger, diff)
+}
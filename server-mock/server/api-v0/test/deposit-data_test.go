@@ -8,8 +8,7 @@ import (
 	apiv0 "github.com/nodeset-org/nodeset-client-go/api-v0"
 	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	idb "github.com/nodeset-org/nodeset-client-go/server-mock/internal/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/stretchr/testify/require"
 )
@@ -26,14 +25,14 @@ func TestGetDepositData(t *testing.T) {
 	}()
 
 	// Provision the database
-	db := idb.ProvisionFullDatabase(t, logger, true)
+	db := testkit.ProvisionFullDatabase(t, logger, true)
 	mgr.SetDatabase(db)
 
 	// Run a get deposit data request
 	data := runGetDepositDataRequest(t, db.Core.GetSessions()[0])
 
 	// Make sure the response is correct
-	vault := db.StakeWise.GetDeployment(test.Network).GetVault(test.StakeWiseVaultAddress)
+	vault := db.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress)
 	require.Equal(t, vault.LatestDepositDataSetIndex, data.Version)
 	require.Equal(t, vault.LatestDepositDataSet, data.DepositData)
 	require.Greater(t, len(data.DepositData), 0)
@@ -52,7 +51,7 @@ func TestUploadDepositData(t *testing.T) {
 	}()
 
 	// Provision the database
-	db := idb.ProvisionFullDatabase(t, logger, false)
+	db := testkit.ProvisionFullDatabase(t, logger, false)
 	mgr.SetDatabase(db)
 	session := db.Core.GetSessions()[0]
 
@@ -63,9 +62,9 @@ func TestUploadDepositData(t *testing.T) {
 
 	// Generate new deposit data
 	depositData := []beacon.ExtendedDepositData{
-		idb.GenerateDepositData(t, 0, test.StakeWiseVaultAddress),
-		idb.GenerateDepositData(t, 1, test.StakeWiseVaultAddress),
-		idb.GenerateDepositData(t, 2, test.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 1, testkit.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 2, testkit.StakeWiseVaultAddress),
 	}
 	t.Log("Generated deposit data")
 
@@ -110,7 +109,7 @@ func runGetDepositDataRequest(t *testing.T, session *db.Session) stakewise.Depos
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.DepositData_Get(context.Background(), logger, test.StakeWiseVaultAddress, test.Network)
+	data, err := client.DepositData_Get(context.Background(), logger, testkit.StakeWiseVaultAddress, testkit.Network)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
@@ -12,7 +12,7 @@ import (
 	"github.com/nodeset-org/nodeset-client-go/common/core"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	nsutil "github.com/nodeset-org/nodeset-client-go/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -30,14 +30,14 @@ func TestLogin(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	//deployment := db.StakeWise.AddDeployment(test.Network, test.ChainIDBig)
-	node0Key, err := test.GetEthPrivateKey(0)
+	//deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node0Pubkey)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node0Pubkey, node0Key, apiv0.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node0Pubkey, node0Key, apiv0.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, apiv0.NodeAddressMessageFormat)
 	require.NoError(t, err)
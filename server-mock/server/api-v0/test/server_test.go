@@ -18,9 +18,9 @@ import (
 	"github.com/nodeset-org/nodeset-client-go/common/core"
 	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/server"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/rocket-pool/node-manager-core/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -162,7 +162,7 @@ func TestUnregisteredNode(t *testing.T) {
 	// Create a session
 	db := mgr.GetDatabase()
 	session := db.Core.CreateSession()
-	node0Key, err := test.GetEthPrivateKey(0)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
 	loginSig, err := auth.GetSignatureForLogin(session.Nonce, node0Pubkey, node0Key)
@@ -11,8 +11,7 @@ import (
 	clientcommon "github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	idb "github.com/nodeset-org/nodeset-client-go/server-mock/internal/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/stretchr/testify/require"
 )
@@ -30,15 +29,15 @@ func TestGetValidators(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.StakeWise.AddDeployment(test.Network, test.ChainIDBig)
-	_ = deployment.AddVault(test.StakeWiseVaultName, test.StakeWiseVaultAddress)
-	node0Key, err := test.GetEthPrivateKey(0)
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	_ = deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node0Pubkey)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node0Pubkey, node0Key, apiv0.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node0Pubkey, node0Key, apiv0.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, apiv0.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -70,7 +69,7 @@ func TestUploadSignedExits(t *testing.T) {
 	}()
 
 	// Provision the database
-	db := idb.ProvisionFullDatabase(t, logger, false)
+	db := testkit.ProvisionFullDatabase(t, logger, false)
 	mgr.SetDatabase(db)
 	session := db.Core.GetSessions()[0]
 
@@ -81,8 +80,8 @@ func TestUploadSignedExits(t *testing.T) {
 
 	// Generate new deposit data
 	depositData := []beacon.ExtendedDepositData{
-		idb.GenerateDepositData(t, 0, test.StakeWiseVaultAddress),
-		idb.GenerateDepositData(t, 1, test.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 1, testkit.StakeWiseVaultAddress),
 	}
 	t.Log("Generated deposit data")
 
@@ -115,7 +114,7 @@ func TestUploadSignedExits(t *testing.T) {
 	t.Logf("Received matching response")
 
 	// Generate a signed exit for validator 1
-	signedExit1 := idb.GenerateSignedExit(t, 1)
+	signedExit1 := testkit.GenerateSignedExit(t, 1)
 	t.Log("Generated signed exit")
 
 	// Upload it
@@ -142,7 +141,7 @@ func runGetValidatorsRequest(t *testing.T, session *db.Session) apiv0.Validators
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.Validators_Get(context.Background(), logger, test.Network)
+	data, err := client.Validators_Get(context.Background(), logger, testkit.Network)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
@@ -155,7 +154,7 @@ func runUploadSignedExitsRequest(t *testing.T, session *db.Session, signedExits
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	err := client.Validators_Patch(context.Background(), logger, signedExits, test.Network)
+	err := client.Validators_Patch(context.Background(), logger, signedExits, testkit.Network)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 }
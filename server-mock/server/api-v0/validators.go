@@ -1,6 +1,7 @@
 package v0server
 
 import (
+	"errors"
 	"net/http"
 
 	apiv0 "github.com/nodeset-org/nodeset-client-go/api-v0"
@@ -50,7 +51,7 @@ func (s *V0Server) getValidators(w http.ResponseWriter, r *http.Request) {
 		for _, validator := range validatorsForVault {
 			validatorStatuses = append(validatorStatuses, apiv0.ValidatorStatus{
 				Pubkey:              validator.Pubkey,
-				Status:              validator.GetStatusV0(),
+				Status:              validator.GetChainStatusV0(db.Beacon),
 				ExitMessageUploaded: validator.ExitMessageUploaded,
 			})
 		}
@@ -99,6 +100,10 @@ func (s *V0Server) patchValidators(w http.ResponseWriter, r *http.Request) {
 	// Handle the upload
 	err := vault.HandleSignedExitUpload(node, exitData)
 	if err != nil {
+		if errors.Is(err, db.ErrInvalidExitMessage) {
+			common.HandleInvalidExitMessage(w, s.logger, err)
+			return
+		}
 		common.HandleServerError(w, s.logger, err)
 		return
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gorilla/mux"
 	v2constellation "github.com/nodeset-org/nodeset-client-go/api-v2/constellation"
+	"github.com/nodeset-org/nodeset-client-go/common"
 
 	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
 )
@@ -35,6 +36,7 @@ func (s *V2ConstellationServer) GetManager() *manager.NodeSetMockManager {
 
 // Registers the routes for the server
 func (s *V2ConstellationServer) RegisterRoutes(versionRouter *mux.Router) {
+	versionRouter.HandleFunc("/"+v2constellation.ConstellationPrefix+common.DeploymentsPath, s.handleDeployments)
 	constellationPrefix := "/" + v2constellation.ConstellationPrefix + "{deployment}/"
 	versionRouter.HandleFunc(constellationPrefix+v2constellation.WhitelistPath, s.handleWhitelist)
 	versionRouter.HandleFunc(constellationPrefix+v2constellation.MinipoolDepositSignaturePath, s.minipoolDepositSignature)
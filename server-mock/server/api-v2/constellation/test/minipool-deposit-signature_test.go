@@ -13,7 +13,7 @@ import (
 	v2core "github.com/nodeset-org/nodeset-client-go/api-v2/core"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,15 +35,15 @@ func TestConstellationDeposit(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.Constellation.AddDeployment(test.Network, test.ChainIDBig, test.WhitelistAddress, test.SuperNodeAddress)
-	node4Key, err := test.GetEthPrivateKey(4)
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node4Key, err := testkit.GetEthPrivateKey(4)
 	require.NoError(t, err)
 	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node4Pubkey)
 	require.NoError(t, err)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -56,7 +56,7 @@ func TestConstellationDeposit(t *testing.T) {
 	require.NoError(t, err)
 
 	// Set the admin private key (just the first Hardhat address)
-	adminKey, err := test.GetEthPrivateKey(0)
+	adminKey, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	deployment.SetAdminPrivateKey(adminKey)
 
@@ -79,7 +79,7 @@ func runMinipoolDepositSignatureRequest(t *testing.T, session *db.Session, minip
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.Constellation.MinipoolDepositSignature(context.Background(), logger, test.Network, minipoolAddress, salt)
+	data, err := client.Constellation.MinipoolDepositSignature(context.Background(), logger, testkit.Network, minipoolAddress, salt)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
@@ -16,7 +16,7 @@ import (
 	"github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/stretchr/testify/require"
 )
@@ -33,15 +33,15 @@ func TestGetValidators_Empty(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.Constellation.AddDeployment(test.Network, test.ChainIDBig, test.WhitelistAddress, test.SuperNodeAddress)
-	node4Key, err := test.GetEthPrivateKey(4)
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node4Key, err := testkit.GetEthPrivateKey(4)
 	require.NoError(t, err)
 	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node4Pubkey)
 	require.NoError(t, err)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -54,7 +54,7 @@ func TestGetValidators_Empty(t *testing.T) {
 	require.NoError(t, err)
 
 	// Set the admin private key (just the first Hardhat address)
-	adminKey, err := test.GetEthPrivateKey(0)
+	adminKey, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	deployment.SetAdminPrivateKey(adminKey)
 
@@ -79,15 +79,15 @@ func TestPatchValidators(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.Constellation.AddDeployment(test.Network, test.ChainIDBig, test.WhitelistAddress, test.SuperNodeAddress)
-	node4Key, err := test.GetEthPrivateKey(4)
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node4Key, err := testkit.GetEthPrivateKey(4)
 	require.NoError(t, err)
 	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node4Pubkey)
 	require.NoError(t, err)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -100,7 +100,7 @@ func TestPatchValidators(t *testing.T) {
 	require.NoError(t, err)
 
 	// Set the admin private key (just the first Hardhat address)
-	adminKey, err := test.GetEthPrivateKey(0)
+	adminKey, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	deployment.SetAdminPrivateKey(adminKey)
 
@@ -208,15 +208,15 @@ func TestSignedExitAlreadyExists(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.Constellation.AddDeployment(test.Network, test.ChainIDBig, test.WhitelistAddress, test.SuperNodeAddress)
-	node4Key, err := test.GetEthPrivateKey(4)
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node4Key, err := testkit.GetEthPrivateKey(4)
 	require.NoError(t, err)
 	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node4Pubkey)
 	require.NoError(t, err)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -229,7 +229,7 @@ func TestSignedExitAlreadyExists(t *testing.T) {
 	require.NoError(t, err)
 
 	// Set the admin private key (just the first Hardhat address)
-	adminKey, err := test.GetEthPrivateKey(0)
+	adminKey, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	deployment.SetAdminPrivateKey(adminKey)
 
@@ -289,7 +289,7 @@ func TestSignedExitAlreadyExists(t *testing.T) {
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	err = client.Constellation.Validators_Patch(context.Background(), logger, test.Network, exitData)
+	err = client.Constellation.Validators_Patch(context.Background(), logger, testkit.Network, exitData)
 	require.Error(t, err)
 	require.True(t, errors.Is(err, v2constellation.ErrExitMessageExists))
 	t.Logf("Received correct exit-message-exists error code")
@@ -302,7 +302,7 @@ func runGetValidatorsRequest(t *testing.T, session *db.Session) v2constellation.
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.Constellation.Validators_Get(context.Background(), logger, test.Network)
+	data, err := client.Constellation.Validators_Get(context.Background(), logger, testkit.Network)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
@@ -315,7 +315,7 @@ func runPatchValidatorsRequest(t *testing.T, session *db.Session, exitData []com
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	err := client.Constellation.Validators_Patch(context.Background(), logger, test.Network, exitData)
+	err := client.Constellation.Validators_Patch(context.Background(), logger, testkit.Network, exitData)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 }
@@ -43,14 +43,21 @@ func (s *V2ConstellationServer) getValidators(w http.ResponseWriter, r *http.Req
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
+	if !common.CheckConstellationPermission(s, w, node, deploymentID) {
+		return
+	}
 
 	// Get the validators
-	validators := deployment.GetValidatorsForNode(node)
+	validators, err := deployment.TryGetValidatorsForNode(node)
+	if err != nil {
+		common.HandleServerError(w, s.logger, err)
+		return
+	}
 	statuses := make([]v2constellation.ValidatorStatus, len(validators))
 	for i, validator := range validators {
 		statuses[i] = v2constellation.ValidatorStatus{
 			Pubkey:              validator.Pubkey,
-			RequiresExitMessage: validator.GetExitMessage() == nil,
+			RequiresExitMessage: deployment.RequiresExitMessage(validator),
 		}
 	}
 
@@ -83,15 +90,8 @@ func (s *V2ConstellationServer) patchValidators(w http.ResponseWriter, r *http.R
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
-
-	// Get the validators
-	validators := deployment.GetValidatorsForNode(node)
-	statuses := make([]v2constellation.ValidatorStatus, len(validators))
-	for i, validator := range validators {
-		statuses[i] = v2constellation.ValidatorStatus{
-			Pubkey:              validator.Pubkey,
-			RequiresExitMessage: validator.GetExitMessage() == nil,
-		}
+	if !common.CheckConstellationPermission(s, w, node, deploymentID) {
+		return
 	}
 
 	// Handle the upload
@@ -108,6 +108,10 @@ func (s *V2ConstellationServer) patchValidators(w http.ResponseWriter, r *http.R
 			common.HandleExitAlreadyExists(w, s.logger)
 			return
 		}
+		if errors.Is(err, db.ErrInvalidExitMessage) {
+			common.HandleInvalidExitMessage(w, s.logger, err)
+			return
+		}
 		common.HandleServerError(w, s.logger, err)
 		return
 	}
@@ -42,6 +42,9 @@ func (s *V2ConstellationServer) getWhitelist(w http.ResponseWriter, r *http.Requ
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
+	if !common.CheckConstellationPermission(s, w, node, deploymentID) {
+		return
+	}
 
 	// Get the registered address
 	email := node.GetUser().Email
@@ -78,6 +81,9 @@ func (s *V2ConstellationServer) postWhitelist(w http.ResponseWriter, r *http.Req
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
+	if !common.CheckConstellationPermission(s, w, node, deploymentID) {
+		return
+	}
 
 	// Get the signature
 	signature, err := deployment.GetWhitelistSignature(node.Address)
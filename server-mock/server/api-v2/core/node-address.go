@@ -1,11 +1,13 @@
 package v2server_core
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	v2core "github.com/nodeset-org/nodeset-client-go/api-v2/core"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 	"github.com/rocket-pool/node-manager-core/utils"
 )
@@ -42,6 +44,10 @@ func (s *V2CoreServer) nodeAddress(w http.ResponseWriter, r *http.Request) {
 	// Register the node
 	err = node.Register(sig, v2core.NodeAddressMessageFormat)
 	if err != nil {
+		if errors.Is(err, auth.ErrInvalidSignature) {
+			common.HandleInvalidSignature(w, s.logger, err)
+			return
+		}
 		common.HandleServerError(w, s.logger, err)
 		return
 	}
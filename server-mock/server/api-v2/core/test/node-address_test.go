@@ -9,7 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	apiv2 "github.com/nodeset-org/nodeset-client-go/api-v2"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	nsutil "github.com/nodeset-org/nodeset-client-go/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -27,15 +27,15 @@ func TestRegisterNode(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	node0Key, err := test.GetEthPrivateKey(0)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node0Pubkey)
 
 	// Send the request
-	runNodeAddressRequest(t, test.User0Email, node0Pubkey, node0Key)
+	runNodeAddressRequest(t, testkit.User0Email, node0Pubkey, node0Key)
 	require.True(t, node.IsRegistered())
 	t.Logf("Node registered successfully")
 }
@@ -40,6 +40,9 @@ func (s *V2StakeWiseServer) depositDataMeta(w http.ResponseWriter, r *http.Reque
 		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
 		return
 	}
+	if !common.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
 
 	// Write the response
 	data := stakewise.DepositDataMetaData{
@@ -50,6 +50,9 @@ func (s *V2StakeWiseServer) getDepositData(w http.ResponseWriter, r *http.Reques
 		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
 		return
 	}
+	if !common.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
 
 	// Write the data
 	data := stakewise.DepositDataData{
@@ -93,6 +96,9 @@ func (s *V2StakeWiseServer) uploadDepositData(w http.ResponseWriter, r *http.Req
 		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
 		return
 	}
+	if !common.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
 
 	// Handle the request
 	castedDepositData := make([]beacon.ExtendedDepositData, len(body.Validators))
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gorilla/mux"
 	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
 
 	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
@@ -36,6 +37,9 @@ func (s *V2StakeWiseServer) GetManager() *manager.NodeSetMockManager {
 
 // Registers the routes for the server
 func (s *V2StakeWiseServer) RegisterRoutes(versionRouter *mux.Router) {
+	versionRouter.HandleFunc("/"+v2stakewise.StakeWisePrefix+common.DeploymentsPath, s.handleDeployments)
+	versionRouter.HandleFunc("/"+v2stakewise.StakeWisePrefix+"{deployment}/"+v2stakewise.VaultsPath, s.handleVaults)
+
 	stakeWisePrefix := "/" + v2stakewise.StakeWisePrefix + "{deployment}/{vault}/"
 	versionRouter.HandleFunc(stakeWisePrefix+stakewise.DepositDataMetaPath, s.depositDataMeta)
 	versionRouter.HandleFunc(stakeWisePrefix+stakewise.DepositDataPath, s.handleDepositData)
@@ -11,7 +11,7 @@ import (
 	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,18 +30,18 @@ func TestDepositDataMeta(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.StakeWise.AddDeployment(test.Network, test.ChainIDBig)
-	node0Key, err := test.GetEthPrivateKey(0)
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node0Pubkey)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node0Pubkey, node0Key, v2core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node0Pubkey, node0Key, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
-	vault := deployment.AddVault(test.StakeWiseVaultName, test.StakeWiseVaultAddress)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
 	vault.LatestDepositDataSetIndex = depositDataSet
 
 	// Create a session
@@ -66,7 +66,7 @@ func runDepositDataMetaRequest(t *testing.T, session *db.Session) stakewise.Depo
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.StakeWise.DepositDataMeta(context.Background(), logger, test.Network, test.StakeWiseVaultAddress)
+	data, err := client.StakeWise.DepositDataMeta(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
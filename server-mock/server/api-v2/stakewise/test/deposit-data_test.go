@@ -9,8 +9,7 @@ import (
 	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/common/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	idb "github.com/nodeset-org/nodeset-client-go/server-mock/internal/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/stretchr/testify/require"
 )
@@ -27,14 +26,14 @@ func TestGetDepositData(t *testing.T) {
 	}()
 
 	// Provision the database
-	db := idb.ProvisionFullDatabase(t, logger, true)
+	db := testkit.ProvisionFullDatabase(t, logger, true)
 	mgr.SetDatabase(db)
 
 	// Run a get deposit data request
 	data := runGetDepositDataRequest(t, db.Core.GetSessions()[0])
 
 	// Make sure the response is correct
-	vault := db.StakeWise.GetDeployment(test.Network).GetVault(test.StakeWiseVaultAddress)
+	vault := db.StakeWise.GetDeployment(testkit.Network).GetVault(testkit.StakeWiseVaultAddress)
 	require.Equal(t, vault.LatestDepositDataSetIndex, data.Version)
 	require.Equal(t, vault.LatestDepositDataSet, data.DepositData)
 	require.Greater(t, len(data.DepositData), 0)
@@ -53,7 +52,7 @@ func TestUploadDepositData(t *testing.T) {
 	}()
 
 	// Provision the database
-	db := idb.ProvisionFullDatabase(t, logger, false)
+	db := testkit.ProvisionFullDatabase(t, logger, false)
 	mgr.SetDatabase(db)
 	session := db.Core.GetSessions()[0]
 
@@ -64,9 +63,9 @@ func TestUploadDepositData(t *testing.T) {
 
 	// Generate new deposit data
 	depositData := []beacon.ExtendedDepositData{
-		idb.GenerateDepositData(t, 0, test.StakeWiseVaultAddress),
-		idb.GenerateDepositData(t, 1, test.StakeWiseVaultAddress),
-		idb.GenerateDepositData(t, 2, test.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 1, testkit.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 2, testkit.StakeWiseVaultAddress),
 	}
 	t.Log("Generated deposit data")
 
@@ -111,7 +110,7 @@ func runGetDepositDataRequest(t *testing.T, session *db.Session) stakewise.Depos
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.StakeWise.DepositData_Get(context.Background(), logger, test.Network, test.StakeWiseVaultAddress)
+	data, err := client.StakeWise.DepositData_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
@@ -124,7 +123,7 @@ func runUploadDepositDataRequest(t *testing.T, session *db.Session, depositData
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	err := client.StakeWise.DepositData_Post(context.Background(), logger, test.Network, test.StakeWiseVaultAddress, depositData)
+	err := client.StakeWise.DepositData_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, depositData)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 }
@@ -14,8 +14,7 @@ import (
 	"github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	idb "github.com/nodeset-org/nodeset-client-go/server-mock/internal/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/stretchr/testify/require"
 )
@@ -33,15 +32,15 @@ func TestGetValidators(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.StakeWise.AddDeployment(test.Network, test.ChainIDBig)
-	_ = deployment.AddVault(test.StakeWiseVaultName, test.StakeWiseVaultAddress)
-	node0Key, err := test.GetEthPrivateKey(0)
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	_ = deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node0Pubkey)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node0Pubkey, node0Key, v2core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node0Pubkey, node0Key, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v2core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -73,7 +72,7 @@ func TestUploadSignedExits(t *testing.T) {
 	}()
 
 	// Provision the database
-	db := idb.ProvisionFullDatabase(t, logger, false)
+	db := testkit.ProvisionFullDatabase(t, logger, false)
 	mgr.SetDatabase(db)
 	session := db.Core.GetSessions()[0]
 
@@ -89,8 +88,8 @@ func TestUploadSignedExits(t *testing.T) {
 
 	// Generate new deposit data
 	depositData := []beacon.ExtendedDepositData{
-		idb.GenerateDepositData(t, 0, test.StakeWiseVaultAddress),
-		idb.GenerateDepositData(t, 1, test.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 1, testkit.StakeWiseVaultAddress),
 	}
 	t.Log("Generated deposit data")
 
@@ -123,7 +122,7 @@ func TestUploadSignedExits(t *testing.T) {
 	t.Logf("Received matching response")
 
 	// Generate a signed exit for validator 1
-	signedExit1 := idb.GenerateSignedExit(t, 1)
+	signedExit1 := testkit.GenerateSignedExit(t, 1)
 	t.Log("Generated signed exit")
 
 	// Encrypt it
@@ -153,6 +152,87 @@ func TestUploadSignedExits(t *testing.T) {
 	t.Logf("Received matching response")
 }
 
+// Make sure validator statuses and exit message validation follow the simulated Beacon chain
+func TestValidatorsFollowBeacon(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	session := db.Core.GetSessions()[0]
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	db.SetSecretEncryptionIdentity(id)
+
+	// Upload deposit data and put the validators on Beacon
+	depositData := []beacon.ExtendedDepositData{
+		testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress),
+		testkit.GenerateDepositData(t, 1, testkit.StakeWiseVaultAddress),
+	}
+	runUploadDepositDataRequest(t, session, depositData)
+	pubkeys := make([]beacon.ValidatorPubkey, len(depositData))
+	for i, data := range depositData {
+		pubkeys[i] = beacon.ValidatorPubkey(data.PublicKey)
+		validator := db.Beacon.AddValidator(pubkeys[i])
+		require.Equal(t, uint64(i), validator.Index)
+	}
+	requireValidatorStatuses(t, session, map[beacon.ValidatorPubkey]v2stakewise.StakeWiseStatus{
+		pubkeys[0]: v2stakewise.StakeWiseStatus_Uploaded,
+		pubkeys[1]: v2stakewise.StakeWiseStatus_Uploaded,
+	})
+	t.Log("Pending validators are reported as uploaded")
+
+	// Activate them
+	db.Beacon.AdvanceEpochs(db.Beacon.ActivationDelayEpochs)
+	requireValidatorStatuses(t, session, map[beacon.ValidatorPubkey]v2stakewise.StakeWiseStatus{
+		pubkeys[0]: v2stakewise.StakeWiseStatus_Registered,
+		pubkeys[1]: v2stakewise.StakeWiseStatus_Registered,
+	})
+	t.Log("Active validators are reported as registered")
+
+	// Exit messages for a future epoch should be rejected
+	signedExit := testkit.GenerateSignedExit(t, 1)
+	encryptedMessage, err := common.EncryptSignedExitMessage(signedExit.ExitMessage, id.Recipient().String())
+	require.NoError(t, err)
+	exitData := []common.EncryptedExitData{
+		{
+			Pubkey:      signedExit.Pubkey,
+			ExitMessage: encryptedMessage,
+		},
+	}
+	client := apiv2.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	err = client.StakeWise.Validators_Patch(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, exitData)
+	require.ErrorIs(t, err, common.ErrInvalidExitMessage)
+	t.Logf("Exit message for epoch %d was rejected at epoch %d", testkit.ExitEpoch, db.Beacon.GetCurrentEpoch())
+
+	// Once the chain reaches the exit epoch it should be accepted
+	db.Beacon.AdvanceEpochs(testkit.ExitEpoch - db.Beacon.GetCurrentEpoch())
+	runUploadSignedExitsRequest(t, session, exitData)
+	t.Logf("Exit message was accepted at epoch %d", db.Beacon.GetCurrentEpoch())
+
+	// Exit the first validator
+	err = db.Beacon.ExitValidator(pubkeys[0])
+	require.NoError(t, err)
+	requireValidatorStatuses(t, session, map[beacon.ValidatorPubkey]v2stakewise.StakeWiseStatus{
+		pubkeys[0]: v2stakewise.StakeWiseStatus_Registered,
+		pubkeys[1]: v2stakewise.StakeWiseStatus_Registered,
+	})
+	db.Beacon.AdvanceEpochs(db.Beacon.ExitDelayEpochs)
+	requireValidatorStatuses(t, session, map[beacon.ValidatorPubkey]v2stakewise.StakeWiseStatus{
+		pubkeys[0]: v2stakewise.StakeWiseStatus_Removed,
+		pubkeys[1]: v2stakewise.StakeWiseStatus_Registered,
+	})
+	t.Log("Exited validator is reported as removed")
+}
+
 // Run a GET api/validators request
 func runGetValidatorsRequest(t *testing.T, session *db.Session) v2stakewise.ValidatorsData {
 	// Create the client
@@ -160,7 +240,7 @@ func runGetValidatorsRequest(t *testing.T, session *db.Session) v2stakewise.Vali
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.StakeWise.Validators_Get(context.Background(), logger, test.Network, test.StakeWiseVaultAddress)
+	data, err := client.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
@@ -173,7 +253,17 @@ func runUploadSignedExitsRequest(t *testing.T, session *db.Session, signedExits
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	err := client.StakeWise.Validators_Patch(context.Background(), logger, test.Network, test.StakeWiseVaultAddress, signedExits)
+	err := client.StakeWise.Validators_Patch(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, signedExits)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 }
+
+// Make sure the validators have the expected statuses
+func requireValidatorStatuses(t *testing.T, session *db.Session, expected map[beacon.ValidatorPubkey]v2stakewise.StakeWiseStatus) {
+	validatorsData := runGetValidatorsRequest(t, session)
+	statuses := map[beacon.ValidatorPubkey]v2stakewise.StakeWiseStatus{}
+	for _, validator := range validatorsData.Validators {
+		statuses[validator.Pubkey] = validator.Status
+	}
+	require.Equal(t, expected, statuses)
+}
@@ -1,11 +1,13 @@
 package v2server_stakewise
 
 import (
+	"errors"
 	"net/http"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
 	clientcommon "github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 )
 
@@ -48,6 +50,9 @@ func (s *V2StakeWiseServer) getValidators(w http.ResponseWriter, r *http.Request
 		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
 		return
 	}
+	if !common.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
 
 	// Find the validator
 	validatorStatuses := []v2stakewise.ValidatorStatus{}
@@ -55,7 +60,7 @@ func (s *V2StakeWiseServer) getValidators(w http.ResponseWriter, r *http.Request
 	for _, validator := range validators {
 		validatorStatuses = append(validatorStatuses, v2stakewise.ValidatorStatus{
 			Pubkey:              validator.Pubkey,
-			Status:              validator.GetStatusV2(),
+			Status:              validator.GetChainStatusV2(db.Beacon),
 			ExitMessageUploaded: validator.ExitMessageUploaded,
 		})
 	}
@@ -82,9 +87,9 @@ func (s *V2StakeWiseServer) patchValidators(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Input validation
-	db := s.manager.GetDatabase()
+	nsDB := s.manager.GetDatabase()
 	deploymentID := pathArgs["deployment"]
-	deployment := db.StakeWise.GetDeployment(deploymentID)
+	deployment := nsDB.StakeWise.GetDeployment(deploymentID)
 	if deployment == nil {
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
@@ -95,6 +100,9 @@ func (s *V2StakeWiseServer) patchValidators(w http.ResponseWriter, r *http.Reque
 		common.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
 		return
 	}
+	if !common.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
 
 	// Handle the upload
 	castedExitData := make([]clientcommon.EncryptedExitData, len(body.ExitData))
@@ -106,6 +114,10 @@ func (s *V2StakeWiseServer) patchValidators(w http.ResponseWriter, r *http.Reque
 	}
 	err := vault.HandleEncryptedSignedExitUpload(node, castedExitData)
 	if err != nil {
+		if errors.Is(err, db.ErrInvalidExitMessage) {
+			common.HandleInvalidExitMessage(w, s.logger, err)
+			return
+		}
 		common.HandleServerError(w, s.logger, err)
 		return
 	}
@@ -0,0 +1,60 @@
+package v2server_stakewise
+
+import (
+	"net/http"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	v2stakewise "github.com/nodeset-org/nodeset-client-go/api-v2/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+
+	servermockcommon "github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Handler for api/v2/modules/stakewise/{deployment}/vaults
+func (s *V2StakeWiseServer) handleVaults(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.getVaults(w, r)
+	default:
+		servermockcommon.HandleInvalidMethod(w, s.logger)
+	}
+}
+
+func (s *V2StakeWiseServer) getVaults(w http.ResponseWriter, r *http.Request) {
+	// Parse deployment ID from URL
+	_, pathArgs := servermockcommon.ProcessApiRequest(s, w, r, nil)
+	session := servermockcommon.ProcessAuthHeader(s, w, r)
+	if session == nil {
+		return
+	}
+	node := servermockcommon.GetNodeForSession(s, w, session)
+	if node == nil {
+		return
+	}
+	deploymentID := pathArgs["deployment"]
+
+	// Validate deployment
+	db := s.manager.GetDatabase()
+	deployment := db.StakeWise.GetDeployment(deploymentID)
+	if deployment == nil {
+		servermockcommon.HandleInvalidDeployment(w, s.logger, deploymentID)
+		return
+	}
+	if !servermockcommon.CheckStakeWisePermission(s, w, node, deploymentID) {
+		return
+	}
+
+	// Collect the vaults the user can use
+	user := node.GetUser()
+	vaults := []ethcommon.Address{}
+	for _, vault := range deployment.Vaults {
+		vaultAddress := vault.Address
+		if !user.HasPermission(api.Permission{Module: api.PermissionModule_StakeWise, Deployment: deploymentID, Vault: &vaultAddress}) {
+			continue
+		}
+		vaults = append(vaults, vault.Address)
+	}
+
+	// Return as JSON
+	servermockcommon.HandleSuccess(w, s.logger, v2stakewise.VaultsData{Vaults: vaults})
+}
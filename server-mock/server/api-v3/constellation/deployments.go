@@ -0,0 +1,54 @@
+package v3server_constellation
+
+import (
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	servermockcommon "github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Handler for api/v3/modules/constellation/deployments
+func (s *V3ConstellationServer) handleDeployments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.getDeployments(w, r)
+	default:
+		servermockcommon.HandleInvalidMethod(w, s.logger)
+	}
+}
+
+func (s *V3ConstellationServer) getDeployments(w http.ResponseWriter, r *http.Request) {
+	// Get the requesting node
+	session := servermockcommon.ProcessAuthHeader(s, w, r)
+	if session == nil {
+		return
+	}
+	node := servermockcommon.GetNodeForSession(s, w, session)
+	if node == nil {
+		return
+	}
+	if !servermockcommon.CheckConstellationPermission(s, w, node, "") {
+		return
+	}
+
+	// Collect the deployments the user can use
+	db := s.manager.GetDatabase()
+	user := node.GetUser()
+	deployments := []common.Deployment{}
+	for _, deployment := range db.Constellation.GetDeployments() {
+		if !user.HasPermission(api.Permission{Module: api.PermissionModule_Constellation, Deployment: deployment.ID}) {
+			continue
+		}
+		deployments = append(deployments, common.Deployment{
+			ChainID: deployment.ChainID.String(),
+			Name:    deployment.ID,
+		})
+	}
+
+	// Return the deployments
+	resp := common.DeploymentsData{
+		Deployments: deployments,
+	}
+	servermockcommon.HandleSuccess(w, s.logger, resp)
+}
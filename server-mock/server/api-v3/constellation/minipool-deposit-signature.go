@@ -1,6 +1,7 @@
 package v3server_constellation
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"github.com/goccy/go-json"
 
 	v3constellation "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 	"github.com/rocket-pool/node-manager-core/utils"
 )
@@ -28,9 +30,9 @@ func (s *V3ConstellationServer) minipoolDepositSignature(w http.ResponseWriter,
 	_, pathArgs := common.ProcessApiRequest(s, w, r, nil)
 
 	// Input validation
-	db := s.manager.GetDatabase()
+	nsDB := s.manager.GetDatabase()
 	deploymentID := pathArgs["deployment"]
-	deployment := db.Constellation.GetDeployment(deploymentID)
+	deployment := nsDB.Constellation.GetDeployment(deploymentID)
 	if deployment == nil {
 		common.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
@@ -52,10 +54,25 @@ func (s *V3ConstellationServer) minipoolDepositSignature(w http.ResponseWriter,
 	if node == nil {
 		return
 	}
+	if !common.CheckConstellationPermission(s, w, node, deploymentID) {
+		return
+	}
 
 	// Get the signature
 	signature, err := deployment.GetMinipoolDepositSignature(node.Address, request.MinipoolAddress, salt)
 	if err != nil {
+		if errors.Is(err, db.ErrMinipoolLimitReached) {
+			common.HandleMinipoolLimitReached(w, s.logger, node.Address, deployment.GetMinipoolLimit(node))
+			return
+		}
+		if errors.Is(err, db.ErrMissingExitMessage) {
+			common.HandleMissingExitMessage(w, s.logger, node.Address)
+			return
+		}
+		if errors.Is(err, db.ErrMinipoolAddressAlreadyRegistered) {
+			common.HandleAddressAlreadyRegistered(w, s.logger, request.MinipoolAddress)
+			return
+		}
 		common.HandleServerError(w, s.logger, fmt.Errorf("error creating signature: %w", err))
 		return
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gorilla/mux"
 	v3constellation "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
+	"github.com/nodeset-org/nodeset-client-go/common"
 
 	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
 )
@@ -35,6 +36,7 @@ func (s *V3ConstellationServer) GetManager() *manager.NodeSetMockManager {
 
 // Registers the routes for the server
 func (s *V3ConstellationServer) RegisterRoutes(versionRouter *mux.Router) {
+	versionRouter.HandleFunc("/"+v3constellation.ConstellationPrefix+common.DeploymentsPath, s.handleDeployments)
 	constellationPrefix := "/" + v3constellation.ConstellationPrefix + "{deployment}/"
 	versionRouter.HandleFunc(constellationPrefix+v3constellation.WhitelistPath, s.handleWhitelist)
 	versionRouter.HandleFunc(constellationPrefix+v3constellation.MinipoolDepositSignaturePath, s.minipoolDepositSignature)
@@ -0,0 +1,271 @@
+package v3server_constellation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/common/exits"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileExitMessages(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	numValidators := 4
+	db := mgr.GetDatabase()
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	session, node, id, pubkeys := provisionMinipoolValidators(t, deployment, numValidators)
+
+	// Upload the first exit manually so the reconciler skips it
+	encryptedMessage, err := common.EncryptSignedExitMessage(createTestExitMessage(0, testkit.ExitEpoch), id.Recipient().String())
+	require.NoError(t, err)
+	runPatchValidatorsRequest(t, session, []common.EncryptedExitData{
+		{
+			Pubkey:      pubkeys[0].Hex(),
+			ExitMessage: encryptedMessage,
+		},
+	})
+
+	// Make a signer that fails for the last validator
+	errSigner := errors.New("signer failure")
+	signer := func(ctx context.Context, pubkey beacon.ValidatorPubkey, epoch uint64) (common.ExitMessage, error) {
+		if pubkey == pubkeys[numValidators-1] {
+			return common.ExitMessage{}, errSigner
+		}
+		return createTestExitMessage(int(pubkey[1]), epoch), nil
+	}
+
+	// Run the reconciler
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	reconciler := exits.NewExitMessageReconciler(client.Constellation.NewExitMessageClient(testkit.Network), signer, getTestExitEpoch, id.Recipient().String(), 2)
+	result, err := reconciler.Reconcile(context.Background(), logger)
+	require.NoError(t, err)
+	require.Equal(t, testkit.ExitEpoch, result.Epoch)
+	require.ElementsMatch(t, pubkeys[1:numValidators-1], result.Uploaded())
+	failures := result.Failures()
+	require.Len(t, failures, 1)
+	require.Equal(t, pubkeys[numValidators-1], failures[0].Pubkey)
+	require.Equal(t, exits.ExitUploadStatus_SigningFailed, failures[0].Status)
+	require.ErrorIs(t, result.Err(), errSigner)
+	t.Log("Reconciler uploaded the missing exits and reported the signing failure")
+
+	// Make sure the server has the exits, and the exit epoch is correct
+	data := runGetValidatorsRequest(t, session)
+	for _, validator := range data.Validators {
+		require.Equal(t, validator.Pubkey == pubkeys[numValidators-1], validator.RequiresExitMessage)
+	}
+	for i := 1; i < numValidators-1; i++ {
+		validator := deployment.GetValidator(node, pubkeys[i])
+		require.Equal(t, strconv.FormatUint(testkit.ExitEpoch, 10), validator.GetExitMessage().Message.Epoch)
+	}
+	t.Log("Server has the reconciled exit messages")
+}
+
+// Create a fake exit message for the validator index and epoch
+func createTestExitMessage(index int, epoch uint64) common.ExitMessage {
+	return common.ExitMessage{
+		Message: common.ExitMessageDetails{
+			Epoch:          strconv.FormatUint(epoch, 10),
+			ValidatorIndex: strconv.Itoa(index),
+		},
+		Signature: fmt.Sprintf("0x%x", index),
+	}
+}
+
+// Make sure a rejected batch is retried one message at a time, so only the bad message fails
+func TestReconcileExitMessagesBadBatch(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	numValidators := 4
+	db := mgr.GetDatabase()
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	session, node, id, pubkeys := provisionMinipoolValidators(t, deployment, numValidators)
+
+	// Reject any batch with the second validator in it
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	exitClient := &faultyExitMessageClient{
+		ExitMessageClient: client.Constellation.NewExitMessageClient(testkit.Network),
+		badPubkey:         pubkeys[1],
+	}
+	reconciler := exits.NewExitMessageReconciler(exitClient, createTestExitSigner, getTestExitEpoch, id.Recipient().String(), numValidators)
+	result, err := reconciler.Reconcile(context.Background(), logger)
+	require.NoError(t, err)
+
+	// The full batch should have been tried once, then each message on its own
+	require.Len(t, exitClient.batches, numValidators+1)
+	require.Len(t, exitClient.batches[0], numValidators)
+	for _, batch := range exitClient.batches[1:] {
+		require.Len(t, batch, 1)
+	}
+	require.ElementsMatch(t, []beacon.ValidatorPubkey{pubkeys[0], pubkeys[2], pubkeys[3]}, result.Uploaded())
+	failures := result.Failures()
+	require.Len(t, failures, 1)
+	require.Equal(t, pubkeys[1], failures[0].Pubkey)
+	require.Equal(t, exits.ExitUploadStatus_UploadFailed, failures[0].Status)
+	require.ErrorIs(t, failures[0].Error, common.ErrInvalidExitMessage)
+	t.Log("Reconciler retried the rejected batch individually and reported the bad message")
+
+	// Make sure the server only has the good exits
+	for i, pubkey := range pubkeys {
+		validator := deployment.GetValidator(node, pubkey)
+		require.Equal(t, i != 1, validator.GetExitMessage() != nil)
+	}
+	t.Log("Server has the good exit messages")
+}
+
+// Make sure the reconciler stops as soon as the session becomes invalid instead of retrying the batch
+func TestReconcileExitMessagesInvalidSession(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	numValidators := 4
+	db := mgr.GetDatabase()
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	session, node, id, pubkeys := provisionMinipoolValidators(t, deployment, numValidators)
+
+	// Invalidate the session after the first batch is uploaded
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	exitClient := &faultyExitMessageClient{
+		ExitMessageClient: client.Constellation.NewExitMessageClient(testkit.Network),
+		afterUpload: func() {
+			client.SetSessionToken("bogus")
+		},
+	}
+	reconciler := exits.NewExitMessageReconciler(exitClient, createTestExitSigner, getTestExitEpoch, id.Recipient().String(), 2)
+	result, err := reconciler.Reconcile(context.Background(), logger)
+	require.ErrorIs(t, err, common.ErrInvalidSession)
+
+	// Only the first batch should have made it, and the second shouldn't have been retried
+	require.Len(t, exitClient.batches, 2)
+	require.Len(t, result.Uploaded(), 2)
+	require.Empty(t, result.Failures())
+	uploadedCount := 0
+	for _, pubkey := range pubkeys {
+		if deployment.GetValidator(node, pubkey).GetExitMessage() != nil {
+			uploadedCount++
+		}
+	}
+	require.Equal(t, 2, uploadedCount)
+	t.Log("Reconciler stopped when the session became invalid")
+}
+
+// Whitelist a new node and create minipools with validators for it, returning its session, the node, the encryption
+// identity the exits are uploaded with, and the validator pubkeys
+func provisionMinipoolValidators(t *testing.T, deployment *db.ConstellationDeployment, numValidators int) (*db.Session, *db.Node, *age.X25519Identity, []beacon.ValidatorPubkey) {
+	nsDB := mgr.GetDatabase()
+	node4Key, err := testkit.GetEthPrivateKey(4)
+	require.NoError(t, err)
+	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
+	user, err := nsDB.Core.AddUser(testkit.User0Email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(node4Pubkey)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+	err = node.Register(regSig, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+
+	// Create a session
+	session := nsDB.Core.CreateSession()
+	loginSig, err := auth.GetSignatureForLogin(session.Nonce, node4Pubkey, node4Key)
+	require.NoError(t, err)
+	err = nsDB.Core.Login(node4Pubkey, session.Nonce, loginSig)
+	require.NoError(t, err)
+
+	// Set the admin private key and encryption identity
+	adminKey, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	deployment.SetAdminPrivateKey(adminKey)
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	nsDB.SetSecretEncryptionIdentity(id)
+
+	// Whitelist the node and create some minipools
+	runPostWhitelistRequest(t, session)
+	pubkeys := make([]beacon.ValidatorPubkey, numValidators)
+	for i := 0; i < numValidators; i++ {
+		mpAddress := ethcommon.HexToAddress(fmt.Sprintf("0x90de%d", i))
+		pubkeys[i][0] = 0xbe
+		pubkeys[i][1] = byte(i)
+		runMinipoolDepositSignatureRequest(t, session, mpAddress, big.NewInt(int64(i)))
+		deployment.SetValidatorInfoForMinipool(mpAddress, pubkeys[i])
+		deployment.IncrementSuperNodeNonce(node.Address)
+	}
+	return session, node, id, pubkeys
+}
+
+// Exit message client that records each batch it's asked to upload, rejects any batch with badPubkey in it, and runs
+// afterUpload after each successful upload
+type faultyExitMessageClient struct {
+	exits.ExitMessageClient
+	badPubkey   beacon.ValidatorPubkey
+	afterUpload func()
+	batches     [][]common.EncryptedExitData
+}
+
+func (c *faultyExitMessageClient) UploadExitMessages(ctx context.Context, logger *slog.Logger, exitData []common.EncryptedExitData) error {
+	c.batches = append(c.batches, exitData)
+	for _, data := range exitData {
+		if data.Pubkey == c.badPubkey.HexWithPrefix() {
+			return common.ErrInvalidExitMessage
+		}
+	}
+	err := c.ExitMessageClient.UploadExitMessages(ctx, logger, exitData)
+	if err != nil {
+		return err
+	}
+	if c.afterUpload != nil {
+		c.afterUpload()
+	}
+	return nil
+}
+
+// Sign a fake exit message for a test validator
+func createTestExitSigner(ctx context.Context, pubkey beacon.ValidatorPubkey, epoch uint64) (common.ExitMessage, error) {
+	return createTestExitMessage(int(pubkey[1]), epoch), nil
+}
+
+// Get the exit epoch for test exit messages
+func getTestExitEpoch(ctx context.Context) (uint64, error) {
+	return testkit.ExitEpoch, nil
+}
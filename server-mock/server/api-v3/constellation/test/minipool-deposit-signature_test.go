@@ -11,9 +11,12 @@ import (
 	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
 	v3constellation "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
 	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	"github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
+	mockclient "github.com/nodeset-org/nodeset-client-go/server-mock/client"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,15 +38,15 @@ func TestConstellationDeposit(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.Constellation.AddDeployment(test.Network, test.ChainIDBig, test.WhitelistAddress, test.SuperNodeAddress)
-	node4Key, err := test.GetEthPrivateKey(4)
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node4Key, err := testkit.GetEthPrivateKey(4)
 	require.NoError(t, err)
 	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node4Pubkey)
 	require.NoError(t, err)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node4Pubkey, node4Key, v3core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v3core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v3core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -56,7 +59,7 @@ func TestConstellationDeposit(t *testing.T) {
 	require.NoError(t, err)
 
 	// Set the admin private key (just the first Hardhat address)
-	adminKey, err := test.GetEthPrivateKey(0)
+	adminKey, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	deployment.SetAdminPrivateKey(adminKey)
 
@@ -72,6 +75,83 @@ func TestConstellationDeposit(t *testing.T) {
 	t.Logf("Received correct response:\nSignature = %s", data.Signature)
 }
 
+// Make sure the minipool limits, exit message requirement, and duplicate address checks are enforced
+func TestMinipoolRules(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	db := mgr.GetDatabase()
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node4Key, err := testkit.GetEthPrivateKey(4)
+	require.NoError(t, err)
+	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
+	user, err := db.Core.AddUser(testkit.User0Email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(node4Pubkey)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+	require.NoError(t, node.Register(regSig, v3core.NodeAddressMessageFormat))
+	session := db.Core.CreateSession()
+	require.NoError(t, db.Core.LoginWithoutSignature(node4Pubkey, session.Nonce))
+	adminKey, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	deployment.SetAdminPrivateKey(adminKey)
+	runPostWhitelistRequest(t, session)
+
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	adminClient := mockclient.NewAdminClient(fmt.Sprintf("http://localhost:%d/admin", port), timeout)
+	requestSignature := func(minipoolAddress ethcommon.Address) error {
+		_, err := client.Constellation.MinipoolDepositSignature(context.Background(), logger, testkit.Network, minipoolAddress, big.NewInt(1))
+		return err
+	}
+	minipools := []ethcommon.Address{
+		ethcommon.HexToAddress("0x90de0"),
+		ethcommon.HexToAddress("0x90de1"),
+		ethcommon.HexToAddress("0x90de2"),
+	}
+	createMinipool := func(index int) {
+		require.NoError(t, requestSignature(minipools[index]))
+		pubkey := beacon.ValidatorPubkey{0xbe, byte(index)}
+		deployment.SetValidatorInfoForMinipool(minipools[index], pubkey)
+		deployment.IncrementSuperNodeNonce(node4Pubkey)
+	}
+
+	// Create the first minipool and make sure its address can't be reused
+	require.NoError(t, adminClient.SetMinipoolLimit(context.Background(), logger, testkit.Network, 2))
+	createMinipool(0)
+	require.ErrorIs(t, requestSignature(minipools[0]), common.ErrAddressAlreadyRegistered)
+	t.Log("Duplicate minipool address was rejected")
+
+	// The next minipool needs the first one's exit message once exits are required
+	require.NoError(t, adminClient.SetRequireExitMessages(context.Background(), logger, testkit.Network, true))
+	require.ErrorIs(t, requestSignature(minipools[1]), common.ErrMissingExitMessage)
+	validator := deployment.GetValidator(node, beacon.ValidatorPubkey{0xbe, 0})
+	validator.SetExitMessage(&common.ExitMessage{})
+	createMinipool(1)
+	validator = deployment.GetValidator(node, beacon.ValidatorPubkey{0xbe, 1})
+	validator.SetExitMessage(&common.ExitMessage{})
+	t.Log("Missing exit message was enforced")
+
+	// The deployment's limit has been reached, but user and node limits override it
+	require.ErrorIs(t, requestSignature(minipools[2]), common.ErrMinipoolLimitReached)
+	userLimit := 3
+	require.NoError(t, adminClient.SetUserMinipoolLimit(context.Background(), logger, testkit.Network, testkit.User0Email, &userLimit))
+	nodeLimit := 2
+	require.NoError(t, adminClient.SetNodeMinipoolLimit(context.Background(), logger, testkit.Network, node4Pubkey, &nodeLimit))
+	require.ErrorIs(t, requestSignature(minipools[2]), common.ErrMinipoolLimitReached)
+	require.NoError(t, adminClient.SetNodeMinipoolLimit(context.Background(), logger, testkit.Network, node4Pubkey, nil))
+	createMinipool(2)
+	t.Log("Minipool limits were enforced")
+}
+
 // Run a GET api/v2/modules/constellation/{deployment}/minipool/deposit-signature request
 func runMinipoolDepositSignatureRequest(t *testing.T, session *db.Session, minipoolAddress ethcommon.Address, salt *big.Int) v3constellation.MinipoolDepositSignatureData {
 	// Create the client
@@ -79,7 +159,7 @@ func runMinipoolDepositSignatureRequest(t *testing.T, session *db.Session, minip
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.Constellation.MinipoolDepositSignature(context.Background(), logger, test.Network, minipoolAddress, salt)
+	data, err := client.Constellation.MinipoolDepositSignature(context.Background(), logger, testkit.Network, minipoolAddress, salt)
 	require.NoError(t, err)
 	t.Logf("Ran request")
 	return data
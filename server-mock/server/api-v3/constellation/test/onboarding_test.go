@@ -0,0 +1,162 @@
+package v3server_constellation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	v3constellation "github.com/nodeset-org/nodeset-client-go/api-v3/constellation"
+	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+var errSimulatedCrash = errors.New("simulated crash")
+
+// Fake Constellation contracts backed by the mock's deployment
+type fakeOnboardingChain struct {
+	deployment  *db.ConstellationDeployment
+	nodeAddress ethcommon.Address
+	chainID     *big.Int
+	whitelisted bool
+	created     []ethcommon.Address
+	submissions int
+
+	// Fail the next minipool submission, either before or after it lands on-chain
+	failBeforeSubmit bool
+	failAfterSubmit  bool
+}
+
+func (c *fakeOnboardingChain) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chainID, nil
+}
+
+func (c *fakeOnboardingChain) IsWhitelisted(ctx context.Context) (bool, error) {
+	return c.whitelisted, nil
+}
+
+func (c *fakeOnboardingChain) SubmitWhitelistSignature(ctx context.Context, signature []byte) error {
+	c.whitelisted = true
+	return nil
+}
+
+func (c *fakeOnboardingChain) GetSuperNodeNonce(ctx context.Context) (uint64, error) {
+	return c.deployment.TryGetSuperNodeNonce(c.nodeAddress)
+}
+
+func (c *fakeOnboardingChain) GetNextMinipool(ctx context.Context) (ethcommon.Address, *big.Int, error) {
+	index := len(c.created)
+	return ethcommon.HexToAddress(fmt.Sprintf("0x90de%d", index)), big.NewInt(int64(index)), nil
+}
+
+func (c *fakeOnboardingChain) SubmitMinipoolDeposit(ctx context.Context, minipool ethcommon.Address, salt *big.Int, signature []byte) error {
+	if c.failBeforeSubmit {
+		c.failBeforeSubmit = false
+		return errSimulatedCrash
+	}
+	c.submissions++
+	c.created = append(c.created, minipool)
+	err := c.deployment.TryIncrementSuperNodeNonce(c.nodeAddress)
+	if err != nil {
+		return err
+	}
+	if c.failAfterSubmit {
+		c.failAfterSubmit = false
+		return errSimulatedCrash
+	}
+	return nil
+}
+
+func TestOnboardingWorkflow(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	db := mgr.GetDatabase()
+	deployment := db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node4Key, err := testkit.GetEthPrivateKey(4)
+	require.NoError(t, err)
+	node4Pubkey := crypto.PubkeyToAddress(node4Key.PublicKey)
+	user, err := db.Core.AddUser(testkit.User0Email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(node4Pubkey)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node4Pubkey, node4Key, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+	err = node.Register(regSig, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+	adminKey, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	deployment.SetAdminPrivateKey(adminKey)
+
+	// Create a session
+	session := db.Core.CreateSession()
+	loginSig, err := auth.GetSignatureForLogin(session.Nonce, node4Pubkey, node4Key)
+	require.NoError(t, err)
+	err = db.Core.Login(node4Pubkey, session.Nonce, loginSig)
+	require.NoError(t, err)
+
+	// Make the workflow, failing before the first minipool is submitted
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	chain := &fakeOnboardingChain{
+		deployment:       deployment,
+		nodeAddress:      node4Pubkey,
+		chainID:          big.NewInt(1),
+		failBeforeSubmit: true,
+	}
+	store := v3constellation.NewFileOnboardingStore(filepath.Join(t.TempDir(), "onboarding.json"))
+	workflow := client.Constellation.NewOnboardingWorkflow(testkit.Network, node4Pubkey, chain, chain, store, nil)
+
+	// Make sure nothing happens while the Execution client is on a different chain
+	progress, err := workflow.Run(context.Background(), logger, 2)
+	var mismatch *common.ErrChainIDMismatch
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, v3constellation.OnboardingStage_CheckWhitelist, progress.Stage)
+	require.False(t, chain.whitelisted)
+	require.Nil(t, deployment.GetWhitelistedAddressForUser(testkit.User0Email))
+	chain.chainID = testkit.ChainIDBig
+	t.Log("Workflow refused to run on the wrong chain")
+
+	// Run on the right chain
+	progress, err = workflow.Run(context.Background(), logger, 2)
+	require.ErrorIs(t, err, errSimulatedCrash)
+	require.Equal(t, v3constellation.OnboardingStage_SubmitMinipool, progress.Stage)
+	require.True(t, chain.whitelisted)
+	require.Empty(t, progress.CreatedMinipools)
+	t.Log("Workflow whitelisted the node and stopped before creating the first minipool")
+
+	// Resume with a new workflow, failing after the first minipool lands on-chain
+	chain.failAfterSubmit = true
+	workflow = client.Constellation.NewOnboardingWorkflow(testkit.Network, node4Pubkey, chain, chain, store, nil)
+	progress, err = workflow.Run(context.Background(), logger, 2)
+	require.ErrorIs(t, err, errSimulatedCrash)
+	require.Equal(t, v3constellation.OnboardingStage_SubmitMinipool, progress.Stage)
+	require.Empty(t, progress.CreatedMinipools)
+	require.Equal(t, 1, chain.submissions)
+	t.Log("Workflow resumed and stopped after the first minipool was submitted")
+
+	// Resume again; the first minipool shouldn't be submitted twice
+	workflow = client.Constellation.NewOnboardingWorkflow(testkit.Network, node4Pubkey, chain, chain, store, nil)
+	progress, err = workflow.Run(context.Background(), logger, 2)
+	require.NoError(t, err)
+	require.Equal(t, v3constellation.OnboardingStage_Complete, progress.Stage)
+	require.Equal(t, chain.created, progress.CreatedMinipools)
+	require.Equal(t, 2, chain.submissions)
+	require.Equal(t, uint64(2), deployment.GetSuperNodeNonce(node4Pubkey))
+	t.Log("Workflow resumed and completed without resubmitting the first minipool")
+}
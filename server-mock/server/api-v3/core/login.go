@@ -7,6 +7,7 @@ import (
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/nodeset-org/nodeset-client-go/common/core"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 	"github.com/rocket-pool/node-manager-core/utils"
@@ -41,6 +42,10 @@ func (s *V3CoreServer) login(w http.ResponseWriter, r *http.Request) {
 			common.HandleUnregisteredNode(w, s.logger, address)
 			return
 		}
+		if errors.Is(err, auth.ErrInvalidSignature) {
+			common.HandleInvalidSignature(w, s.logger, err)
+			return
+		}
 		common.HandleServerError(w, s.logger, err)
 		return
 	}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 	servermockcommon "github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 )
 
@@ -18,13 +19,27 @@ func (s *V3StakeWiseServer) handleDeployments(w http.ResponseWriter, r *http.Req
 }
 
 func (s *V3StakeWiseServer) getDeployments(w http.ResponseWriter, r *http.Request) {
-	// Get the database
-	db := s.manager.GetDatabase()
-	servermockcommon.ProcessAuthHeader(s, w, r)
+	// Get the requesting node
+	session := servermockcommon.ProcessAuthHeader(s, w, r)
+	if session == nil {
+		return
+	}
+	node := servermockcommon.GetNodeForSession(s, w, session)
+	if node == nil {
+		return
+	}
+	if !servermockcommon.CheckStakeWisePermission(s, w, node, "") {
+		return
+	}
 
-	// Collect deployments
+	// Collect the deployments the user can use
+	db := s.manager.GetDatabase()
+	user := node.GetUser()
 	deployments := []common.Deployment{}
 	for _, deployment := range db.StakeWise.Deployments {
+		if !user.HasPermission(api.Permission{Module: api.PermissionModule_StakeWise, Deployment: deployment.ID}) {
+			continue
+		}
 		deployments = append(deployments, common.Deployment{
 			ChainID: deployment.ChainID.String(),
 			Name:    deployment.ID,
@@ -2,14 +2,19 @@ package v3server_stakewise_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
 	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,15 +28,15 @@ func TestGetDeployments(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.StakeWise.AddDeployment(test.Network, test.ChainIDBig)
-	_ = deployment.AddVault(test.StakeWiseVaultName, test.StakeWiseVaultAddress)
-	node0Key, err := test.GetEthPrivateKey(0)
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	_ = deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node0Pubkey)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node0Pubkey, node0Key, v3core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node0Pubkey, node0Key, v3core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v3core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -56,3 +61,154 @@ func TestGetDeployments(t *testing.T) {
 
 	t.Logf("Successfully fetched deployment: %s", resp.Deployments[0].Name)
 }
+
+func TestValidateDeployment(t *testing.T) {
+	// Take snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		require.NoError(t, err)
+	}()
+
+	// Provision the database
+	db := mgr.GetDatabase()
+	_ = db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	node0Key, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
+	user, err := db.Core.AddUser(testkit.User0Email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(node0Pubkey)
+	err = node.RegisterWithoutSignature()
+	require.NoError(t, err)
+	session := db.Core.CreateSession()
+	err = db.Core.LoginWithoutSignature(node0Pubkey, session.Nonce)
+	require.NoError(t, err)
+
+	// Connect to a stub Execution client on the right chain
+	stub := &executionClientStub{
+		chainID: testkit.ChainID,
+	}
+	rpcServer := httptest.NewServer(stub)
+	defer rpcServer.Close()
+	ec, err := ethclient.Dial(rpcServer.URL)
+	require.NoError(t, err)
+	defer ec.Close()
+
+	// Validate the deployment
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	deployment, err := common.ValidateDeployment(context.Background(), logger, client.StakeWise, ec, testkit.Network)
+	require.NoError(t, err)
+	require.Equal(t, testkit.Network, deployment.Name)
+	t.Log("Deployment matched the Execution client's chain")
+
+	// Check an unknown deployment
+	_, err = common.ValidateDeployment(context.Background(), logger, client.StakeWise, ec, "unknown")
+	require.ErrorIs(t, err, common.ErrInvalidDeployment)
+	t.Log("Unknown deployment was rejected")
+
+	// Switch the Execution client to a different chain
+	stub.chainID = 1
+	_, err = common.ValidateDeployment(context.Background(), logger, client.StakeWise, ec, testkit.Network)
+	var mismatch *common.ErrChainIDMismatch
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, testkit.ChainIDBig, mismatch.DeploymentChainID)
+	require.Equal(t, uint64(1), mismatch.ClientChainID.Uint64())
+	t.Log("Chain ID mismatch was rejected")
+}
+
+// Make sure the deployments list respects the user's permissions
+func TestGetDeployments_Permissions(t *testing.T) {
+	// Take snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		require.NoError(t, err)
+	}()
+
+	// Provision the database with a second deployment
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	_ = db.StakeWise.AddDeployment("second", testkit.ChainIDBig)
+	user := db.Core.GetUser(testkit.User1Email)
+	session := testkit.LoginNode(t, db, testkit.GetNodeAddress(t, 0))
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+
+	// Revoked deployments should be left out
+	require.NoError(t, user.SetPermission(api.Permission{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: testkit.Network,
+	}, false))
+	resp, err := client.StakeWise.Deployments(context.Background(), logger)
+	require.NoError(t, err)
+	require.Len(t, resp.Deployments, 1)
+	require.Equal(t, "second", resp.Deployments[0].Name)
+	t.Log("Revoked deployment was left out")
+
+	// Revoking the module should reject the request
+	require.NoError(t, user.SetPermission(api.Permission{
+		Module: api.PermissionModule_StakeWise,
+	}, false))
+	_, err = client.StakeWise.Deployments(context.Background(), logger)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	t.Log("Revoked module was rejected")
+}
+
+// Make sure Constellation deployments are validated the same way through the Constellation client
+func TestValidateConstellationDeployment(t *testing.T) {
+	// Take snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		require.NoError(t, err)
+	}()
+
+	// Provision the database
+	db := mgr.GetDatabase()
+	_ = db.Constellation.AddDeployment(testkit.Network, testkit.ChainIDBig, testkit.WhitelistAddress, testkit.SuperNodeAddress)
+	node0Key, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
+	user, err := db.Core.AddUser(testkit.User0Email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(node0Pubkey)
+	err = node.RegisterWithoutSignature()
+	require.NoError(t, err)
+	session := db.Core.CreateSession()
+	err = db.Core.LoginWithoutSignature(node0Pubkey, session.Nonce)
+	require.NoError(t, err)
+
+	// Connect to a stub Execution client on the right chain
+	stub := &executionClientStub{
+		chainID: testkit.ChainID,
+	}
+	rpcServer := httptest.NewServer(stub)
+	defer rpcServer.Close()
+	ec, err := ethclient.Dial(rpcServer.URL)
+	require.NoError(t, err)
+	defer ec.Close()
+
+	// Validate the deployment
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	deployment, err := common.ValidateDeployment(context.Background(), logger, client.Constellation, ec, testkit.Network)
+	require.NoError(t, err)
+	require.Equal(t, testkit.Network, deployment.Name)
+	t.Log("Deployment matched the Execution client's chain")
+
+	// The StakeWise module doesn't have the deployment
+	_, err = common.ValidateDeployment(context.Background(), logger, client.StakeWise, ec, testkit.Network)
+	require.ErrorIs(t, err, common.ErrInvalidDeployment)
+	t.Log("Deployment was only found in the Constellation module")
+
+	// Switch the Execution client to a different chain
+	stub.chainID = 1
+	_, err = common.ValidateDeployment(context.Background(), logger, client.Constellation, ec, testkit.Network)
+	var mismatch *common.ErrChainIDMismatch
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, testkit.ChainIDBig, mismatch.DeploymentChainID)
+	require.Equal(t, uint64(1), mismatch.ClientChainID.Uint64())
+	t.Log("Chain ID mismatch was rejected")
+}
@@ -0,0 +1,140 @@
+package v3server_stakewise_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+// Minimal Execution client stub that serves eth_chainId, eth_blockNumber, and get_deposit_root() calls
+type executionClientStub struct {
+	chainID uint64
+	block   uint64
+	root    ethcommon.Hash
+	calls   int
+}
+
+func (s *executionClientStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result any
+	switch request.Method {
+	case "eth_chainId":
+		result = fmt.Sprintf("0x%x", s.chainID)
+	case "eth_blockNumber":
+		result = fmt.Sprintf("0x%x", s.block)
+	case "eth_call":
+		s.calls++
+		result = s.root.Hex()
+	default:
+		http.Error(w, "unsupported method", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result":  result,
+	})
+}
+
+func TestRpcDepositRootProvider(t *testing.T) {
+	stub := &executionClientStub{
+		block: 10,
+		root:  ethcommon.HexToHash("0x01"),
+	}
+	rpcServer := httptest.NewServer(stub)
+	defer rpcServer.Close()
+
+	provider, err := v3stakewise.NewRpcDepositRootProvider(context.Background(), rpcServer.URL, ethcommon.HexToAddress("0xde9051"))
+	require.NoError(t, err)
+	defer provider.Close()
+
+	// Get the root
+	root, err := provider.GetDepositRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, stub.root, root)
+	require.Equal(t, 1, stub.calls)
+
+	// Change the root without a new block; the cached one should be returned
+	stub.root = ethcommon.HexToHash("0x02")
+	root, err = provider.GetDepositRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ethcommon.HexToHash("0x01"), root)
+	require.Equal(t, 1, stub.calls)
+	t.Log("Deposit root was cached for the same block")
+
+	// Produce a new block
+	stub.block++
+	root, err = provider.GetDepositRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, stub.root, root)
+	require.Equal(t, 2, stub.calls)
+	t.Log("Deposit root was refreshed for the new block")
+}
+
+// Make sure the mock's Execution client serves the simulated deposit contract's root
+func TestMockDepositContractRoot(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// The empty deposit contract's root matches the one on a freshly deployed contract
+	db := mgr.GetDatabase()
+	emptyRoot := ethcommon.HexToHash("0xd70a234731285c6804c2a4f56711ddb8c82c99740f207854891028af34e27e5e")
+	require.Equal(t, emptyRoot, db.Eth.GetDepositRoot())
+
+	provider, err := v3stakewise.NewRpcDepositRootProvider(context.Background(), fmt.Sprintf("http://localhost:%d/%s", port, api.EthRpcPath), ethcommon.HexToAddress("0xde9051"))
+	require.NoError(t, err)
+	defer provider.Close()
+	root, err := provider.GetDepositRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, emptyRoot, root)
+	t.Log("Mock Execution client returned the empty deposit root")
+
+	// Make a deposit and make sure the new root is served
+	depositData := testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress)
+	newRoot := db.Eth.AddDeposit(depositData)
+	require.NotEqual(t, emptyRoot, newRoot)
+	require.Equal(t, uint64(1), db.Eth.GetDepositCount())
+	require.NotNil(t, db.Beacon.GetValidator(beacon.ValidatorPubkey(depositData.PublicKey)))
+	root, err = provider.GetDepositRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, newRoot, root)
+	t.Log("Mock Execution client returned the new deposit root after a deposit")
+
+	// Pin the root, then make sure the next deposit replaces it with the tree's root again
+	pinnedRoot := ethcommon.HexToHash("0x01")
+	db.Eth.SetDepositRoot(pinnedRoot)
+	root, err = provider.GetDepositRoot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, pinnedRoot, root)
+	nextRoot := db.Eth.AddDeposit(testkit.GenerateDepositData(t, 1, testkit.StakeWiseVaultAddress))
+	require.NotEqual(t, pinnedRoot, nextRoot)
+	require.NotEqual(t, newRoot, nextRoot)
+	t.Log("Pinned deposit root was served until the next deposit")
+}
@@ -0,0 +1,145 @@
+package v3server_stakewise_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"filippo.io/age"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorRegistrar(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	db := mgr.GetDatabase()
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	vault.MaxValidatorsPerUser = 2
+	node0Key, err := testkit.GetEthPrivateKey(0)
+	require.NoError(t, err)
+	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
+	user, err := db.Core.AddUser(testkit.User0Email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(node0Pubkey)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node0Pubkey, node0Key, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+	err = node.Register(regSig, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	db.SetSecretEncryptionIdentity(id)
+	depositRoot := db.Eth.GetDepositRoot()
+
+	// Create a session
+	session := db.Core.CreateSession()
+	loginSig, err := auth.GetSignatureForLogin(session.Nonce, node0Pubkey, node0Key)
+	require.NoError(t, err)
+	err = db.Core.Login(node0Pubkey, session.Nonce, loginSig)
+	require.NoError(t, err)
+
+	// Generate the candidate validators
+	numCandidates := 3
+	candidates := make([]beacon.ValidatorPubkey, numCandidates)
+	depositData := map[beacon.ValidatorPubkey]beacon.ExtendedDepositData{}
+	for i := 0; i < numCandidates; i++ {
+		data := testkit.GenerateDepositData(t, uint(i), testkit.StakeWiseVaultAddress)
+		candidates[i] = beacon.ValidatorPubkey(data.PublicKey)
+		depositData[candidates[i]] = data
+	}
+
+	// Make the registrar
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	buildDepositData := func(ctx context.Context, pubkey beacon.ValidatorPubkey, vault ethcommon.Address) (beacon.ExtendedDepositData, error) {
+		return depositData[pubkey], nil
+	}
+	signExit := func(ctx context.Context, pubkey beacon.ValidatorPubkey, epoch uint64) (common.ExitMessage, error) {
+		return common.ExitMessage{
+			Message: common.ExitMessageDetails{
+				Epoch:          strconv.FormatUint(epoch, 10),
+				ValidatorIndex: "0",
+			},
+			Signature: pubkey.HexWithPrefix(),
+		}, nil
+	}
+	getExitEpoch := func(ctx context.Context) (uint64, error) {
+		return testkit.ExitEpoch, nil
+	}
+	depositRoots := v3stakewise.DepositRootProviderFunc(func(ctx context.Context) (ethcommon.Hash, error) {
+		return db.Eth.GetDepositRoot(), nil
+	})
+	stub := &executionClientStub{
+		chainID: 1,
+	}
+	rpcServer := httptest.NewServer(stub)
+	defer rpcServer.Close()
+	ec, err := ethclient.Dial(rpcServer.URL)
+	require.NoError(t, err)
+	defer ec.Close()
+	registrar := client.StakeWise.NewValidatorRegistrar(testkit.Network, testkit.StakeWiseVaultAddress, ec, buildDepositData, signExit, getExitEpoch, depositRoots, id.Recipient().String())
+	registrar.BatchSize = 1
+
+	// Make sure nothing is registered while the Execution client is on a different chain
+	_, err = registrar.Register(context.Background(), logger, candidates)
+	var mismatch *common.ErrChainIDMismatch
+	require.ErrorAs(t, err, &mismatch)
+	require.Empty(t, runGetValidatorsRequest(t, session).Validators)
+	require.Equal(t, 0, runGetValidatorsMetaRequest(t, session).Registered)
+	stub.chainID = testkit.ChainID
+	t.Log("Registrar refused to register validators on the wrong chain")
+
+	// Do a dry run and make sure nothing was registered
+	registrar.DryRun = true
+	result, err := registrar.Register(context.Background(), logger, candidates)
+	require.NoError(t, err)
+	require.True(t, result.DryRun)
+	require.Equal(t, 2, result.Available)
+	require.Equal(t, candidates[:2], result.Registered())
+	require.Len(t, result.Batches, 2)
+	require.Equal(t, depositRoot, result.Batches[0].BeaconDepositRoot)
+	require.Empty(t, result.Batches[0].Signature)
+	require.Empty(t, runGetValidatorsRequest(t, session).Validators)
+	t.Log("Dry run selected the first 2 candidates without registering them")
+
+	// Register for real
+	registrar.DryRun = false
+	result, err = registrar.Register(context.Background(), logger, candidates)
+	require.NoError(t, err)
+	require.Equal(t, candidates[:2], result.Registered())
+	for _, batch := range result.Batches {
+		require.NotEmpty(t, batch.Signature)
+	}
+	meta := runGetValidatorsMetaRequest(t, session)
+	require.Equal(t, 2, meta.Registered)
+	require.Equal(t, 0, meta.Available)
+	t.Log("Registered the first 2 candidates in 2 batches")
+
+	// Run again, all of the slots should be used and the registered ones skipped
+	result, err = registrar.Register(context.Background(), logger, candidates)
+	require.NoError(t, err)
+	require.Equal(t, 0, result.Available)
+	require.ElementsMatch(t, candidates[:2], result.AlreadyRegistered)
+	require.Empty(t, result.Batches)
+	t.Log("Registrar skipped registered validators and respected the slot limit")
+}
@@ -3,10 +3,12 @@ package v3server_stakewise_test
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"filippo.io/age"
 
+	apiv0 "github.com/nodeset-org/nodeset-client-go/api-v0"
 	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
 	stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/common"
@@ -17,7 +19,7 @@ import (
 	v3core "github.com/nodeset-org/nodeset-client-go/api-v3/core"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,17 +35,17 @@ func TestPostValidators(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.StakeWise.AddDeployment(test.Network, test.ChainIDBig)
-	vault := deployment.AddVault(test.StakeWiseVaultName, test.StakeWiseVaultAddress)
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
 	vault.MaxValidatorsPerUser = 10 // Set max validators
 
-	node0Key, err := test.GetEthPrivateKey(0)
+	node0Key, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	node0Pubkey := crypto.PubkeyToAddress(node0Key.PublicKey)
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 	node := user.WhitelistNode(node0Pubkey)
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, node0Pubkey, node0Key, v3core.NodeAddressMessageFormat)
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, node0Pubkey, node0Key, v3core.NodeAddressMessageFormat)
 	require.NoError(t, err)
 	err = node.Register(regSig, v3core.NodeAddressMessageFormat)
 	require.NoError(t, err)
@@ -101,8 +103,7 @@ func TestPostValidators(t *testing.T) {
 	}
 
 	// Submit the request
-	beaconDepositRoot := ethcommon.Hash{}
-	db.Eth.SetDepositRoot(beaconDepositRoot)
+	beaconDepositRoot := db.Eth.GetDepositRoot()
 	signature, err := runPostValidatorsRequest(t, session, validatorDetails, beaconDepositRoot)
 	require.NoError(t, err)
 	require.NotEmpty(t, signature, "Expected a valid signature from the backend")
@@ -132,25 +133,31 @@ func TestPostValidators(t *testing.T) {
 	t.Logf("Successfully registered %d validators. New count: %d",
 		numValidatorsToRegister, metaAfter.Registered)
 
-	// Change the current deposit root and verify the validator has increased
-	beaconDepositRoot[0] = 0x01
-	db.Eth.SetDepositRoot(beaconDepositRoot)
+	// Deposit the first validator, which changes the deposit root so only the deposited validator is still counted
+	err = vault.DepositValidator(beacon.ValidatorPubkey(validatorDetails[0].DepositData.PublicKey))
+	require.NoError(t, err)
+	require.NotEqual(t, beaconDepositRoot, db.Eth.GetDepositRoot())
 	metaAfter = runGetValidatorsMetaRequest(t, session)
-	require.Equal(t, metaAfter.Registered, 0) // Registered validators should be reset
-	require.Equal(t, metaAfter.Max, 10)       // Should stay the same
-	require.Equal(t, metaAfter.Available, 10)
-	t.Log("Deposit root changed, registered validator count is now 0 as expected")
-
-	// Mark the first 2 as used
-	vault.Validators[node0Pubkey][beacon.ValidatorPubkey(validatorDetails[0].DepositData.PublicKey)].IsActiveOnBeacon = true
-	vault.Validators[node0Pubkey][beacon.ValidatorPubkey(validatorDetails[1].DepositData.PublicKey)].HasDepositEvent = true
+	require.Equal(t, metaAfter.Registered, 1)
+	require.Equal(t, metaAfter.Max, 10)
+	require.Equal(t, metaAfter.Available, 9)
+	t.Log("Deposit root changed, only the deposited validator is still registered")
 
-	// Verify the new count
+	// Deposit the second one
+	err = vault.DepositValidator(beacon.ValidatorPubkey(validatorDetails[1].DepositData.PublicKey))
+	require.NoError(t, err)
 	metaAfter = runGetValidatorsMetaRequest(t, session)
-	require.Equal(t, metaAfter.Registered, 2) // Registered validators should be reset
-	require.Equal(t, metaAfter.Max, 10)       // Should stay the same
-	require.Equal(t, metaAfter.Available, 8)  // 10 - 2
-	t.Logf("Marked 2 validators as active on Beacon / have deposit events, new registered count: %d", metaAfter.Registered)
+	require.Equal(t, metaAfter.Registered, 2)
+	require.Equal(t, metaAfter.Max, 10)
+	require.Equal(t, metaAfter.Available, 8)
+	t.Logf("Deposited 2 validators, new registered count: %d", metaAfter.Registered)
+
+	// Make sure the stale deposit root is rejected
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	_, err = client.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, validatorDetails[2:], beaconDepositRoot)
+	require.ErrorIs(t, err, stakewise.ErrDepositRootAlreadyAssigned)
+	t.Log("Stale deposit root was rejected")
 }
 
 func runPostValidatorsRequest(t *testing.T, session *db.Session, validatorDetails []stakewise.ValidatorRegistrationDetails, beaconDepositRoot ethcommon.Hash) (string, error) {
@@ -162,8 +169,8 @@ func runPostValidatorsRequest(t *testing.T, session *db.Session, validatorDetail
 	response, err := client.StakeWise.Validators_Post(
 		context.Background(),
 		logger,
-		test.Network,
-		test.StakeWiseVaultAddress,
+		testkit.Network,
+		testkit.StakeWiseVaultAddress,
 		validatorDetails,
 		beaconDepositRoot,
 	)
@@ -179,8 +186,302 @@ func runGetValidatorsRequest(t *testing.T, session *db.Session) stakewise.Valida
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	data, err := client.StakeWise.Validators_Get(context.Background(), logger, test.Network, test.StakeWiseVaultAddress)
+	data, err := client.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
 	require.NoError(t, err)
 	t.Logf("Ran GET /validators request")
 	return data
 }
+
+// Make sure the vault's balance and deposit root assignments are enforced
+func TestPostValidatorsVaultRules(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database with a vault that can only fund one validator
+	nsDB := mgr.GetDatabase()
+	deployment := nsDB.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	vault.MaxValidatorsPerUser = 10
+	vault.Balance = big.NewInt(0).Mul(big.NewInt(40), big.NewInt(1e18))
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	nsDB.SetSecretEncryptionIdentity(id)
+	session0 := createLoggedInNode(t, nsDB, testkit.User0Email, 0)
+	session1 := createLoggedInNode(t, nsDB, testkit.User1Email, 1)
+	validatorDetails := createValidatorDetails(t, id, 3)
+	beaconDepositRoot := nsDB.Eth.GetDepositRoot()
+
+	// Registering two validators needs more ETH than the vault has
+	client0 := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client0.SetSessionToken(session0.Token)
+	_, err = client0.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, validatorDetails[:2], beaconDepositRoot)
+	require.ErrorIs(t, err, common.ErrInsufficientVaultBalance)
+	t.Log("Registration beyond the vault balance was rejected")
+
+	// One validator fits, and takes 32 ETH out of the vault
+	_, err = client0.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, validatorDetails[:1], beaconDepositRoot)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(0).Mul(big.NewInt(8), big.NewInt(1e18)), vault.Balance)
+	t.Log("Registered one validator")
+
+	// Another node operator can't reuse the deposit root
+	vault.Balance = big.NewInt(0).Mul(big.NewInt(100), big.NewInt(1e18))
+	client1 := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client1.SetSessionToken(session1.Token)
+	_, err = client1.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, validatorDetails[1:2], beaconDepositRoot)
+	require.ErrorIs(t, err, stakewise.ErrDepositRootAlreadyAssigned)
+	t.Log("Deposit root reuse by another node operator was rejected")
+
+	// The original node operator can keep using it
+	_, err = client0.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, validatorDetails[2:], beaconDepositRoot)
+	require.NoError(t, err)
+	t.Log("The assigned node operator could reuse its deposit root")
+}
+
+// Make sure a bad exit message anywhere in a batch rejects the whole batch without registering any of it
+func TestPostValidatorsBadExitMessage(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	nsDB := mgr.GetDatabase()
+	deployment := nsDB.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	vault.MaxValidatorsPerUser = 10
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	nsDB.SetSecretEncryptionIdentity(id)
+	session := createLoggedInNode(t, nsDB, testkit.User0Email, 0)
+	validatorDetails := createValidatorDetails(t, id, 3)
+
+	// Deposit the middle validator so the Beacon chain rejects its exit message's index
+	nsDB.Eth.AddDeposit(validatorDetails[1].DepositData)
+	beaconDepositRoot := nsDB.Eth.GetDepositRoot()
+	startingBalance := new(big.Int).Set(vault.Balance)
+
+	// The batch should be rejected without changing anything
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+	_, err = client.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, validatorDetails, beaconDepositRoot)
+	require.ErrorIs(t, err, common.ErrInvalidExitMessage)
+	require.Equal(t, startingBalance, vault.Balance)
+	for _, validators := range vault.Validators {
+		require.Empty(t, validators)
+	}
+	require.Nil(t, vault.GetDepositRootAssignee(beaconDepositRoot))
+	t.Log("Batch with a bad exit message was rejected without registering any validators")
+
+	// The rest of the batch should still go through on its own
+	goodDetails := []stakewise.ValidatorRegistrationDetails{validatorDetails[0], validatorDetails[2]}
+	_, err = client.StakeWise.Validators_Post(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress, goodDetails, beaconDepositRoot)
+	require.NoError(t, err)
+	require.Len(t, runGetValidatorsRequest(t, session).Validators, 2)
+	t.Log("Registered the rest of the batch")
+}
+
+// Create a registered node for a new user and log it in
+func createLoggedInNode(t *testing.T, nsDB *db.Database, email string, keyIndex uint) *db.Session {
+	nodeKey, err := testkit.GetEthPrivateKey(keyIndex)
+	require.NoError(t, err)
+	nodeAddress := crypto.PubkeyToAddress(nodeKey.PublicKey)
+	user, err := nsDB.Core.AddUser(email)
+	require.NoError(t, err)
+	node := user.WhitelistNode(nodeAddress)
+	regSig, err := auth.GetSignatureForRegistration(email, nodeAddress, nodeKey, v3core.NodeAddressMessageFormat)
+	require.NoError(t, err)
+	require.NoError(t, node.Register(regSig, v3core.NodeAddressMessageFormat))
+	session := nsDB.Core.CreateSession()
+	require.NoError(t, nsDB.Core.LoginWithoutSignature(nodeAddress, session.Nonce))
+	return session
+}
+
+// Create registration details for validators with unique pubkeys and encrypted exit messages
+func createValidatorDetails(t *testing.T, id *age.X25519Identity, count int) []stakewise.ValidatorRegistrationDetails {
+	validatorDetails := make([]stakewise.ValidatorRegistrationDetails, count)
+	for i := 0; i < count; i++ {
+		pubkey := make([]byte, 48)
+		pubkey[0] = byte(i + 1)
+		exitMessage := common.ExitMessage{
+			Message: common.ExitMessageDetails{
+				Epoch:          fmt.Sprintf("epoch_%d", i),
+				ValidatorIndex: fmt.Sprintf("validator_index_%d", i),
+			},
+			Signature: fmt.Sprintf("signature_%d", i),
+		}
+		encryptedMsg, err := common.EncryptSignedExitMessage(exitMessage, id.Recipient().String())
+		require.NoError(t, err)
+		validatorDetails[i] = stakewise.ValidatorRegistrationDetails{
+			DepositData: beacon.ExtendedDepositData{
+				PublicKey: pubkey,
+				Signature: make([]byte, 96),
+			},
+			ExitMessage: encryptedMsg,
+		}
+	}
+	return validatorDetails
+}
+
+// Make sure validators report their lifecycle status as they move through registration, deposit, activation, and exit
+func TestValidatorLifecycleStatus(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	nsDB := mgr.GetDatabase()
+	deployment := nsDB.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	vault.MaxValidatorsPerUser = 10
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	nsDB.SetSecretEncryptionIdentity(id)
+	session := createLoggedInNode(t, nsDB, testkit.User0Email, 0)
+	validatorDetails := createValidatorDetails(t, id, 2)
+	pubkeys := []beacon.ValidatorPubkey{
+		beacon.ValidatorPubkey(validatorDetails[0].DepositData.PublicKey),
+		beacon.ValidatorPubkey(validatorDetails[1].DepositData.PublicKey),
+	}
+	getStatus := func(pubkey beacon.ValidatorPubkey) stakewise.ValidatorStatus {
+		for _, status := range runGetValidatorsRequest(t, session).Validators {
+			if status.Pubkey == pubkey {
+				return status
+			}
+		}
+		t.Fatalf("validator [%s] not found", pubkey.Hex())
+		return stakewise.ValidatorStatus{}
+	}
+
+	// Register the validators
+	nsDB.Beacon.AdvanceSlots(5)
+	beaconDepositRoot := nsDB.Eth.GetDepositRoot()
+	_, err = runPostValidatorsRequest(t, session, validatorDetails, beaconDepositRoot)
+	require.NoError(t, err)
+	status := getStatus(pubkeys[0])
+	require.Equal(t, common.ValidatorLifecycleStatus_Registered, status.Status)
+	require.Equal(t, beaconDepositRoot, status.BeaconDepositRoot)
+	require.True(t, nsDB.Beacon.GetSlotTime(5).Equal(status.RegistrationTime))
+	t.Log("Validators were registered")
+
+	// Deposit and activate them
+	require.NoError(t, vault.DepositValidator(pubkeys[0]))
+	require.NoError(t, vault.DepositValidator(pubkeys[1]))
+	require.Equal(t, common.ValidatorLifecycleStatus_Deposited, getStatus(pubkeys[0]).Status)
+	nsDB.Beacon.AdvanceEpochs(nsDB.Beacon.ActivationDelayEpochs)
+	require.Equal(t, common.ValidatorLifecycleStatus_Active, getStatus(pubkeys[0]).Status)
+	t.Log("Validators were deposited and activated")
+
+	// Exit one and slash the other
+	require.NoError(t, nsDB.Beacon.ExitValidator(pubkeys[0]))
+	require.NoError(t, nsDB.Beacon.SlashValidator(pubkeys[1]))
+	require.Equal(t, common.ValidatorLifecycleStatus_Exiting, getStatus(pubkeys[0]).Status)
+	require.Equal(t, common.ValidatorLifecycleStatus_Slashed, getStatus(pubkeys[1]).Status)
+	nsDB.Beacon.AdvanceEpochs(nsDB.Beacon.ExitDelayEpochs)
+	require.Equal(t, common.ValidatorLifecycleStatus_Exited, getStatus(pubkeys[0]).Status)
+	require.Equal(t, common.ValidatorLifecycleStatus_Slashed, getStatus(pubkeys[1]).Status)
+	t.Log("Validators were exited and slashed")
+}
+
+// Make sure validators marked as active on Beacon are treated as active until they're seen on the simulated chain
+func TestIsActiveOnBeaconOverride(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	nsDB := mgr.GetDatabase()
+	deployment := nsDB.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	vault.MaxValidatorsPerUser = 10
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	nsDB.SetSecretEncryptionIdentity(id)
+	session := createLoggedInNode(t, nsDB, testkit.User0Email, 0)
+	validatorDetails := createValidatorDetails(t, id, 2)
+	pubkeys := []beacon.ValidatorPubkey{
+		beacon.ValidatorPubkey(validatorDetails[0].DepositData.PublicKey),
+		beacon.ValidatorPubkey(validatorDetails[1].DepositData.PublicKey),
+	}
+	_, err = runPostValidatorsRequest(t, session, validatorDetails, nsDB.Eth.GetDepositRoot())
+	require.NoError(t, err)
+
+	// Mark the first one as active and deposit the second, which changes the deposit root
+	validator := vault.Validators[session.NodeAddress][pubkeys[0]]
+	validator.IsActiveOnBeacon = true
+	require.NoError(t, vault.DepositValidator(pubkeys[1]))
+	meta := runGetValidatorsMetaRequest(t, session)
+	require.Equal(t, 2, meta.Registered)
+	for _, status := range runGetValidatorsRequest(t, session).Validators {
+		if status.Pubkey == pubkeys[0] {
+			require.Equal(t, common.ValidatorLifecycleStatus_Active, status.Status)
+		}
+	}
+	require.Equal(t, apiv0.StakeWiseStatus_Registered, validator.GetChainStatusV0(nsDB.Beacon))
+	t.Log("Override was used for the validator that isn't on the simulated chain")
+
+	// Once it's on the chain, its state there takes over
+	require.NoError(t, vault.DepositValidator(pubkeys[0]))
+	for _, status := range runGetValidatorsRequest(t, session).Validators {
+		if status.Pubkey == pubkeys[0] {
+			require.Equal(t, common.ValidatorLifecycleStatus_Deposited, status.Status)
+		}
+	}
+	require.Equal(t, apiv0.StakeWiseStatus_Uploaded, validator.GetChainStatusV0(nsDB.Beacon))
+	t.Log("Simulated chain took over once the validator was deposited")
+}
+
+// Make sure validators that only have deposit data uploaded aren't listed until they're registered with NodeSet
+func TestUnregisteredValidatorsNotListed(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		if err != nil {
+			t.Fatalf("error reverting to snapshot: %v", err)
+		}
+	}()
+
+	// Provision the database
+	nsDB := mgr.GetDatabase()
+	deployment := nsDB.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	vault := deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
+	session := createLoggedInNode(t, nsDB, testkit.User0Email, 0)
+	node, _ := nsDB.Core.GetNode(session.NodeAddress)
+
+	// Upload deposit data the v2 way
+	depositData := testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress)
+	pubkey := beacon.ValidatorPubkey(depositData.PublicKey)
+	require.NoError(t, vault.HandleDepositDataUpload(node, []beacon.ExtendedDepositData{depositData}))
+	require.Empty(t, runGetValidatorsRequest(t, session).Validators)
+	require.Empty(t, vault.Validators[session.NodeAddress][pubkey].GetStatusV3(nsDB.Beacon))
+	t.Log("Validator with only deposit data wasn't listed")
+
+	// Once it's active, it should be listed as registered
+	vault.Validators[session.NodeAddress][pubkey].MarkActive()
+	validators := runGetValidatorsRequest(t, session).Validators
+	require.Len(t, validators, 1)
+	require.Equal(t, pubkey, validators[0].Pubkey)
+	require.Equal(t, common.ValidatorLifecycleStatus_Registered, validators[0].Status)
+	t.Log("Validator was listed once it was registered")
+}
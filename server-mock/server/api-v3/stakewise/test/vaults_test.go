@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"testing"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/internal/test"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,19 +25,19 @@ func TestGetVaults(t *testing.T) {
 
 	// Provision the database
 	db := mgr.GetDatabase()
-	deployment := db.StakeWise.AddDeployment(test.Network, test.ChainIDBig)
-	deployment.AddVault(test.StakeWiseVaultName, test.StakeWiseVaultAddress)
+	deployment := db.StakeWise.AddDeployment(testkit.Network, testkit.ChainIDBig)
+	deployment.AddVault(testkit.StakeWiseVaultName, testkit.StakeWiseVaultAddress)
 
-	nodeKey, err := test.GetEthPrivateKey(0)
+	nodeKey, err := testkit.GetEthPrivateKey(0)
 	require.NoError(t, err)
 	nodePubkey := crypto.PubkeyToAddress(nodeKey.PublicKey)
 
-	user, err := db.Core.AddUser(test.User0Email)
+	user, err := db.Core.AddUser(testkit.User0Email)
 	require.NoError(t, err)
 
 	node := user.WhitelistNode(nodePubkey)
 
-	regSig, err := auth.GetSignatureForRegistration(test.User0Email, nodePubkey, nodeKey, "address")
+	regSig, err := auth.GetSignatureForRegistration(testkit.User0Email, nodePubkey, nodeKey, "address")
 	require.NoError(t, err)
 	require.NoError(t, node.Register(regSig, "address"))
 
@@ -48,11 +51,52 @@ func TestGetVaults(t *testing.T) {
 	client.SetSessionToken(session.Token)
 
 	// Run the request
-	vaults, err := client.StakeWise.Vaults(context.Background(), logger, test.Network)
+	vaults, err := client.StakeWise.Vaults(context.Background(), logger, testkit.Network)
 	require.NoError(t, err)
 	require.Len(t, vaults.Vaults, 1)
-	require.Equal(t, vaults.Vaults[0].Name, test.StakeWiseVaultName)
-	require.Equal(t, vaults.Vaults[0].Address, test.StakeWiseVaultAddress)
+	require.Equal(t, vaults.Vaults[0].Name, testkit.StakeWiseVaultName)
+	require.Equal(t, vaults.Vaults[0].Address, testkit.StakeWiseVaultAddress)
 
 	t.Logf("Successfully fetched %d vault(s)", len(vaults.Vaults))
 }
+
+// Make sure the vaults list respects the user's permissions
+func TestGetVaults_Permissions(t *testing.T) {
+	// Take a snapshot
+	mgr.TakeSnapshot("test")
+	defer func() {
+		err := mgr.RevertToSnapshot("test")
+		require.NoError(t, err)
+	}()
+
+	// Provision the database with a second vault
+	db := testkit.ProvisionFullDatabase(t, logger, false)
+	mgr.SetDatabase(db)
+	secondVaultAddress := ethcommon.HexToAddress("0x57ace215")
+	db.StakeWise.GetDeployment(testkit.Network).AddVault("Second Vault", secondVaultAddress)
+	user := db.Core.GetUser(testkit.User1Email)
+	session := testkit.LoginNode(t, db, testkit.GetNodeAddress(t, 0))
+	client := apiv3.NewNodeSetClient(fmt.Sprintf("http://localhost:%d/api", port), timeout)
+	client.SetSessionToken(session.Token)
+
+	// Revoked vaults should be left out
+	require.NoError(t, user.SetPermission(api.Permission{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: testkit.Network,
+		Vault:      &testkit.StakeWiseVaultAddress,
+	}, false))
+	vaults, err := client.StakeWise.Vaults(context.Background(), logger, testkit.Network)
+	require.NoError(t, err)
+	require.Len(t, vaults.Vaults, 1)
+	require.Equal(t, secondVaultAddress, vaults.Vaults[0].Address)
+	t.Log("Revoked vault was left out")
+
+	// Revoking the deployment should reject the request
+	require.NoError(t, user.SetPermission(api.Permission{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: testkit.Network,
+	}, false))
+	_, err = client.StakeWise.Vaults(context.Background(), logger, testkit.Network)
+	require.ErrorIs(t, err, common.ErrInvalidPermissions)
+	t.Log("Revoked deployment was rejected")
+}
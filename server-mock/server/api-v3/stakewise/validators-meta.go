@@ -59,6 +59,9 @@ func (s *V3StakeWiseServer) getValidatorsMeta(w http.ResponseWriter, r *http.Req
 	if node == nil {
 		return
 	}
+	if !common.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
 	user := node.GetUser()
 	registered := vault.GetRegisteredValidatorsPerUser(user)
 	data := stakewise.ValidatorsMetaData{
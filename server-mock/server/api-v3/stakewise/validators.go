@@ -2,6 +2,7 @@ package v3server_stakewise
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
 	servermockcommon "github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 )
 
@@ -49,9 +51,9 @@ func (s *V3StakeWiseServer) postValidators(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Input validation
-	db := s.manager.GetDatabase()
+	nsDB := s.manager.GetDatabase()
 	deploymentID := pathArgs["deployment"]
-	deployment := db.StakeWise.GetDeployment(deploymentID)
+	deployment := nsDB.StakeWise.GetDeployment(deploymentID)
 	if deployment == nil {
 		servermockcommon.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
@@ -62,6 +64,14 @@ func (s *V3StakeWiseServer) postValidators(w http.ResponseWriter, r *http.Reques
 		servermockcommon.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
 		return
 	}
+	if !servermockcommon.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
+	currentRoot := nsDB.Eth.GetDepositRoot()
+	if body.BeaconDepositRoot != currentRoot {
+		servermockcommon.HandleStaleDepositRoot(w, s.logger, body.BeaconDepositRoot, currentRoot)
+		return
+	}
 
 	// Filter out validators with empty public keys
 	validValidators := make([]v3stakewise.ValidatorRegistrationDetails, 0, len(body.Validators))
@@ -80,20 +90,30 @@ func (s *V3StakeWiseServer) postValidators(w http.ResponseWriter, r *http.Reques
 	available := vault.MaxValidatorsPerUser - active
 	numToRegister := len(validValidators)
 	if numToRegister > available {
-		servermockcommon.HandleServerError(w, s.logger, fmt.Errorf(
+		servermockcommon.HandleInputError(w, s.logger, fmt.Errorf(
 			"not enough available slots: requested %d, available %d",
 			numToRegister, available))
 		return
 	}
+	err := vault.CheckValidatorRegistration(user, body.BeaconDepositRoot, numToRegister)
+	if err != nil {
+		if errors.Is(err, db.ErrDepositRootAlreadyAssigned) {
+			servermockcommon.HandleDepositRootAlreadyAssigned(w, s.logger, body.BeaconDepositRoot)
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientVaultBalance) {
+			servermockcommon.HandleInsufficientVaultBalance(w, s.logger, vault.Address, vault.Balance, numToRegister)
+			return
+		}
+		servermockcommon.HandleServerError(w, s.logger, err)
+		return
+	}
 
-	// Must add validator to struct + exit message
-	secret := db.GetSecretEncryptionIdentity()
-	for _, validator := range validValidators {
+	// Decrypt and check every exit message before changing anything, so a bad one doesn't leave the batch partially registered
+	secret := nsDB.GetSecretEncryptionIdentity()
+	exitMessages := make([]common.ExitMessage, len(validValidators))
+	for i, validator := range validValidators {
 		pubkey := beacon.ValidatorPubkey(validator.DepositData.PublicKey)
-
-		// Add the validator if not already present
-		vault.AddStakeWiseDepositData(node, validator.DepositData)
-
 		decodedHex, err := nsutils.DecodeHex(validator.ExitMessage)
 		if err != nil {
 			servermockcommon.HandleServerError(w, s.logger, fmt.Errorf("error decoding exit message hex: %w", err))
@@ -118,13 +138,12 @@ func (s *V3StakeWiseServer) postValidators(w http.ResponseWriter, r *http.Reques
 			servermockcommon.HandleServerError(w, s.logger, fmt.Errorf("error parsing decrypted exit message: %w", err))
 			return
 		}
-		// Get validator reference
-		nodeValidators := vault.GetStakeWiseValidatorsForNode(node)
-		if vInfo, exists := nodeValidators[pubkey]; exists {
-			vInfo.SetExitMessage(exitMessage)
-			vInfo.MarkActive()
-			vInfo.BeaconDepositRoot = body.BeaconDepositRoot
+		err = nsDB.Beacon.ValidateExitMessage(pubkey, exitMessage)
+		if err != nil {
+			servermockcommon.HandleInvalidExitMessage(w, s.logger, err)
+			return
 		}
+		exitMessages[i] = exitMessage
 	}
 
 	// https://github.com/stakewise/v3-core/blob/main/contracts/validators/ValidatorsChecker.sol#L187
@@ -182,6 +201,25 @@ func (s *V3StakeWiseServer) postValidators(w http.ResponseWriter, r *http.Reques
 	finalDigestBytes = append(finalDigestBytes, hashStruct.Bytes()...)
 	finalDigest := crypto.Keccak256Hash(finalDigestBytes)
 
+	// Register the validators and take their deposits out of the vault
+	err = vault.ConsumeValidatorDeposits(numToRegister)
+	if err != nil {
+		servermockcommon.HandleServerError(w, s.logger, err)
+		return
+	}
+	registrationTime := nsDB.Beacon.GetSlotTime(nsDB.Beacon.GetCurrentSlot())
+	for i, validator := range validValidators {
+		pubkey := beacon.ValidatorPubkey(validator.DepositData.PublicKey)
+
+		// Add the validator if not already present
+		vault.AddStakeWiseDepositData(node, validator.DepositData)
+		nodeValidators := vault.GetStakeWiseValidatorsForNode(node)
+		if vInfo, exists := nodeValidators[pubkey]; exists {
+			vInfo.SetExitMessage(exitMessages[i])
+			vInfo.Register(body.BeaconDepositRoot, registrationTime)
+		}
+	}
+
 	resp := v3stakewise.PostValidatorData{
 		Signature: finalDigest.Hex(), //solidity code for stakewise
 	}
@@ -215,14 +253,23 @@ func (s *V3StakeWiseServer) getValidators(w http.ResponseWriter, r *http.Request
 		servermockcommon.HandleInvalidVault(w, s.logger, deploymentID, vaultAddress)
 		return
 	}
+	if !servermockcommon.CheckStakeWiseVaultPermission(s, w, node, deploymentID, vaultAddress) {
+		return
+	}
 
-	// Find the validator
+	// Find the validators registered with NodeSet; ones that only have deposit data uploaded aren't included
 	validatorStatuses := []v3stakewise.ValidatorStatus{}
 	validators := vault.GetStakeWiseValidatorsForNode(node)
 	for _, validator := range validators {
+		if !validator.IsRegistered() {
+			continue
+		}
 		validatorStatuses = append(validatorStatuses, v3stakewise.ValidatorStatus{
 			Pubkey:              validator.Pubkey,
 			ExitMessageUploaded: validator.ExitMessageUploaded,
+			Status:              validator.GetStatusV3(db.Beacon),
+			BeaconDepositRoot:   validator.BeaconDepositRoot,
+			RegistrationTime:    validator.RegistrationTime,
 		})
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 
 	servermockcommon "github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 )
@@ -21,6 +22,14 @@ func (s *V3StakeWiseServer) handleVaults(w http.ResponseWriter, r *http.Request)
 func (s *V3StakeWiseServer) getVaults(w http.ResponseWriter, r *http.Request) {
 	// Parse deployment ID from URL
 	_, pathArgs := servermockcommon.ProcessApiRequest(s, w, r, nil)
+	session := servermockcommon.ProcessAuthHeader(s, w, r)
+	if session == nil {
+		return
+	}
+	node := servermockcommon.GetNodeForSession(s, w, session)
+	if node == nil {
+		return
+	}
 	deploymentID := pathArgs["deployment"]
 
 	// Validate deployment
@@ -30,9 +39,18 @@ func (s *V3StakeWiseServer) getVaults(w http.ResponseWriter, r *http.Request) {
 		servermockcommon.HandleInvalidDeployment(w, s.logger, deploymentID)
 		return
 	}
+	if !servermockcommon.CheckStakeWisePermission(s, w, node, deploymentID) {
+		return
+	}
 
+	// Collect the vaults the user can use
+	user := node.GetUser()
 	vaults := []v3stakewise.VaultInfo{}
 	for _, vault := range deployment.Vaults {
+		vaultAddress := vault.Address
+		if !user.HasPermission(api.Permission{Module: api.PermissionModule_StakeWise, Deployment: deploymentID, Vault: &vaultAddress}) {
+			continue
+		}
 		vaults = append(vaults, v3stakewise.VaultInfo{
 			Name:    vault.Name,
 			Address: vault.Address,
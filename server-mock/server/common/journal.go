@@ -0,0 +1,85 @@
+package common
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+)
+
+// Response writer that keeps a copy of the status code and body so they can be journaled
+type journalResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+// Record the status code before writing it
+func (w *journalResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Record the body before writing it
+func (w *journalResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// Creates a middleware that records every request handled by the router, and its response, in the manager's journal
+func JournalMiddleware(mgr *manager.NodeSetMockManager) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			entry := api.JournalEntry{
+				Time:     time.Now(),
+				Method:   r.Method,
+				Path:     r.URL.Path,
+				Query:    r.URL.RawQuery,
+				PathArgs: mux.Vars(r),
+			}
+			if route := mux.CurrentRoute(r); route != nil {
+				entry.Route, _ = route.GetPathTemplate()
+			}
+
+			// Copy the body so the handler can still read it
+			if r.Body != nil {
+				bodyBytes, err := io.ReadAll(r.Body)
+				if err == nil && json.Valid(bodyBytes) {
+					entry.Body = bodyBytes
+				}
+				r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+			}
+
+			// Get the node that owns the session, if there is one
+			token, err := auth.GetSessionTokenFromRequest(r)
+			if err == nil {
+				session := mgr.GetDatabase().Core.GetSessionByToken(token)
+				if session != nil {
+					nodeAddress := session.NodeAddress
+					entry.NodeAddress = &nodeAddress
+				}
+			}
+
+			// Run the handler and record its response
+			recorder := &journalResponseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+			next.ServeHTTP(recorder, r)
+			entry.StatusCode = recorder.statusCode
+			var response common.NodeSetResponse[json.RawMessage]
+			if json.Unmarshal(recorder.body.Bytes(), &response) == nil {
+				entry.Error = response.Error
+				entry.Message = response.Message
+			}
+			mgr.GetJournal().Record(entry)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+)
+
+// Creates a middleware that holds the manager's lock while each request is handled, so handlers don't race with the
+// manager's background tasks. Routes with the provided path templates are handled without the lock.
+func LockMiddleware(mgr *manager.NodeSetMockManager, unlockedRoutes ...string) mux.MiddlewareFunc {
+	unlocked := map[string]bool{}
+	for _, route := range unlockedRoutes {
+		unlocked[route] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if route := mux.CurrentRoute(r); route != nil {
+				template, _ := route.GetPathTemplate()
+				if unlocked[template] {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			mgr.Lock()
+			defer mgr.Unlock()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
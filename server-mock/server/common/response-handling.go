@@ -3,13 +3,16 @@ package common
 import (
 	"fmt"
 	"log/slog"
+	"math/big"
 	"net/http"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/goccy/go-json"
 	v2constellation "github.com/nodeset-org/nodeset-client-go/api-v2/constellation"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
 	"github.com/nodeset-org/nodeset-client-go/common"
 	"github.com/nodeset-org/nodeset-client-go/common/core"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 	"github.com/rocket-pool/node-manager-core/log"
 )
 
@@ -46,6 +49,13 @@ func HandleInvalidSessionError(w http.ResponseWriter, logger *slog.Logger, err e
 	writeResponse(w, logger, http.StatusUnauthorized, bytes)
 }
 
+// Write an error if the signature provided with the request can't be verified
+func HandleInvalidSignature(w http.ResponseWriter, logger *slog.Logger, err error) {
+	msg := err.Error()
+	bytes := formatError(msg, common.InvalidSignatureKey)
+	writeResponse(w, logger, http.StatusBadRequest, bytes)
+}
+
 // Write an error if the node providing the request isn't registered
 func HandleUnregisteredNode(w http.ResponseWriter, logger *slog.Logger, address ethcommon.Address) {
 	msg := fmt.Sprintf("No user found with authorized address %s", address.Hex())
@@ -81,6 +91,20 @@ func HandleInvalidVault(w http.ResponseWriter, logger *slog.Logger, deployment s
 	writeResponse(w, logger, http.StatusBadRequest, bytes)
 }
 
+// Handles a request from a user that isn't permitted to use a module, deployment, or vault
+func HandleInvalidPermissions(w http.ResponseWriter, logger *slog.Logger, email string, permission api.Permission) {
+	resource := fmt.Sprintf("module [%s]", permission.Module)
+	if permission.Deployment != "" {
+		resource = fmt.Sprintf("deployment [%s] of %s", permission.Deployment, resource)
+	}
+	if permission.Vault != nil {
+		resource = fmt.Sprintf("vault [%s] on %s", permission.Vault.Hex(), resource)
+	}
+	msg := fmt.Sprintf("user [%s] doesn't have permission to use %s", email, resource)
+	bytes := formatError(msg, common.InvalidPermissionsKey)
+	writeResponse(w, logger, http.StatusForbidden, bytes)
+}
+
 // Handles a signed exit upload with an already existing message
 func HandleExitAlreadyExists(w http.ResponseWriter, logger *slog.Logger) {
 	msg := "at least one signed exit message already exists"
@@ -88,6 +112,70 @@ func HandleExitAlreadyExists(w http.ResponseWriter, logger *slog.Logger) {
 	writeResponse(w, logger, http.StatusBadRequest, bytes)
 }
 
+// Handles an exit message that isn't valid for the validator's state on the Beacon chain
+func HandleInvalidExitMessage(w http.ResponseWriter, logger *slog.Logger, err error) {
+	msg := err.Error()
+	bytes := formatError(msg, common.InvalidExitMessageKey)
+	writeResponse(w, logger, http.StatusBadRequest, bytes)
+}
+
+// Handles a deposit root that isn't the Beacon deposit contract's current root. NodeSet doesn't have a separate error for
+// this, so it's reported the same way as a root that's already been assigned; clients retry with a fresh root either way.
+func HandleStaleDepositRoot(w http.ResponseWriter, logger *slog.Logger, depositRoot ethcommon.Hash, currentRoot ethcommon.Hash) {
+	msg := fmt.Sprintf("deposit root [%s] doesn't match the current deposit root [%s]", depositRoot.Hex(), currentRoot.Hex())
+	bytes := formatError(msg, v3stakewise.DepositRootAlreadyAssignedKey)
+	writeResponse(w, logger, http.StatusConflict, bytes)
+}
+
+// Handles a validator registration for a deposit root that another node operator already used
+func HandleDepositRootAlreadyAssigned(w http.ResponseWriter, logger *slog.Logger, depositRoot ethcommon.Hash) {
+	msg := fmt.Sprintf("deposit root [%s] has already been assigned to another node operator", depositRoot.Hex())
+	bytes := formatError(msg, v3stakewise.DepositRootAlreadyAssignedKey)
+	writeResponse(w, logger, http.StatusConflict, bytes)
+}
+
+// Handles a validator registration that the vault doesn't have enough ETH to deposit
+func HandleInsufficientVaultBalance(w http.ResponseWriter, logger *slog.Logger, vault ethcommon.Address, balance *big.Int, count int) {
+	msg := fmt.Sprintf("vault [%s] has %s wei, which isn't enough to register %d validators", vault.Hex(), balance.String(), count)
+	bytes := formatError(msg, common.InsufficientVaultBalanceKey)
+	writeResponse(w, logger, http.StatusUnprocessableEntity, bytes)
+}
+
+// Handles a minipool request from a node that can't create any more minipools
+func HandleMinipoolLimitReached(w http.ResponseWriter, logger *slog.Logger, address ethcommon.Address, limit int) {
+	msg := fmt.Sprintf("node [%s] has reached its limit of %d minipools", address.Hex(), limit)
+	bytes := formatError(msg, common.MinipoolLimitReachedKey)
+	writeResponse(w, logger, http.StatusForbidden, bytes)
+}
+
+// Handles a minipool request from a node that hasn't uploaded the signed exit for one of its previous minipools
+func HandleMissingExitMessage(w http.ResponseWriter, logger *slog.Logger, address ethcommon.Address) {
+	msg := fmt.Sprintf("node [%s] is missing a signed exit message for a previous minipool", address.Hex())
+	bytes := formatError(msg, common.MissingExitMessageKey)
+	writeResponse(w, logger, http.StatusForbidden, bytes)
+}
+
+// Handles a minipool request for an address another minipool already uses
+func HandleAddressAlreadyRegistered(w http.ResponseWriter, logger *slog.Logger, minipoolAddress ethcommon.Address) {
+	msg := fmt.Sprintf("minipool address [%s] is already registered", minipoolAddress.Hex())
+	bytes := formatError(msg, common.AddressAlreadyRegisteredKey)
+	writeResponse(w, logger, http.StatusForbidden, bytes)
+}
+
+// Write an error if a request was routed to a namespace that doesn't exist
+func HandleInvalidNamespace(w http.ResponseWriter, logger *slog.Logger, name string) {
+	msg := fmt.Sprintf("namespace [%s] doesn't exist", name)
+	bytes := formatError(msg, "")
+	writeResponse(w, logger, http.StatusNotFound, bytes)
+}
+
+// Write an error if the server can't serve requests right now, such as while it's shutting down
+func HandleServiceUnavailable(w http.ResponseWriter, logger *slog.Logger, err error) {
+	msg := err.Error()
+	bytes := formatError(msg, "")
+	writeResponse(w, logger, http.StatusServiceUnavailable, bytes)
+}
+
 // Write an error if the auth header couldn't be decoded
 func HandleServerError(w http.ResponseWriter, logger *slog.Logger, err error) {
 	msg := err.Error()
@@ -10,7 +10,9 @@ import (
 
 	"github.com/goccy/go-json"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/auth"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
 	"github.com/rocket-pool/node-manager-core/log"
@@ -78,7 +80,7 @@ func ProcessAuthHeader(serverImpl IServerImpl, w http.ResponseWriter, r *http.Re
 	db := mgr.GetDatabase()
 	session := db.Core.GetSessionByToken(token)
 	if session == nil {
-		HandleInvalidSessionError(w, logger, err)
+		HandleInvalidSessionError(w, logger, fmt.Errorf("no session found for the provided token"))
 		return nil
 	}
 	return session
@@ -103,3 +105,40 @@ func GetNodeForSession(serverImpl IServerImpl, w http.ResponseWriter, session *d
 	}
 	return node
 }
+
+// Makes sure the node's user is permitted to use a StakeWise vault, writing an error if it isn't
+func CheckStakeWiseVaultPermission(serverImpl IServerImpl, w http.ResponseWriter, node *db.Node, deploymentID string, vaultAddress ethcommon.Address) bool {
+	return checkPermission(serverImpl, w, node, api.Permission{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: deploymentID,
+		Vault:      &vaultAddress,
+	})
+}
+
+// Makes sure the node's user is permitted to use a StakeWise deployment, writing an error if it isn't.
+// An empty deployment ID only checks the StakeWise module as a whole.
+func CheckStakeWisePermission(serverImpl IServerImpl, w http.ResponseWriter, node *db.Node, deploymentID string) bool {
+	return checkPermission(serverImpl, w, node, api.Permission{
+		Module:     api.PermissionModule_StakeWise,
+		Deployment: deploymentID,
+	})
+}
+
+// Makes sure the node's user is permitted to use a Constellation deployment, writing an error if it isn't.
+// An empty deployment ID only checks the Constellation module as a whole.
+func CheckConstellationPermission(serverImpl IServerImpl, w http.ResponseWriter, node *db.Node, deploymentID string) bool {
+	return checkPermission(serverImpl, w, node, api.Permission{
+		Module:     api.PermissionModule_Constellation,
+		Deployment: deploymentID,
+	})
+}
+
+// Makes sure the node's user has the permission, writing an error if it doesn't
+func checkPermission(serverImpl IServerImpl, w http.ResponseWriter, node *db.Node, permission api.Permission) bool {
+	user := node.GetUser()
+	if !user.HasPermission(permission) {
+		HandleInvalidPermissions(w, serverImpl.GetLogger(), user.Email, permission)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package eth
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/gorilla/mux"
+	v3stakewise "github.com/nodeset-org/nodeset-client-go/api-v3/stakewise"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+	"github.com/rocket-pool/node-manager-core/log"
+)
+
+const (
+	// JSON-RPC error code for unsupported methods
+	methodNotFoundCode int = -32601
+
+	// JSON-RPC error code for invalid parameters
+	invalidParamsCode int = -32602
+)
+
+var (
+	// Selector for the Beacon deposit contract's get_deposit_count() function
+	getDepositCountSelector []byte = crypto.Keccak256([]byte("get_deposit_count()"))[:4]
+)
+
+// A JSON-RPC request
+type rpcRequest struct {
+	JsonRpc string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// A JSON-RPC error
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// A JSON-RPC response
+type rpcResponse struct {
+	JsonRpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// Parameters for an eth_call request
+type callParams struct {
+	To    string        `json:"to"`
+	Data  hexutil.Bytes `json:"data"`
+	Input hexutil.Bytes `json:"input"`
+}
+
+// Minimal Execution client JSON-RPC API for the server mock, serving the simulated Beacon deposit contract
+type EthServer struct {
+	logger  *slog.Logger
+	manager *manager.NodeSetMockManager
+}
+
+// Creates a new Execution client server mock
+func NewEthServer(logger *slog.Logger, manager *manager.NodeSetMockManager) *EthServer {
+	return &EthServer{
+		logger:  logger,
+		manager: manager,
+	}
+}
+
+// Registers the routes for the server
+func (s *EthServer) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/"+api.EthRpcPath, s.handleRpc)
+}
+
+// Handle a JSON-RPC request
+func (s *EthServer) handleRpc(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	var request rpcRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		common.HandleInputError(w, s.logger, fmt.Errorf("error decoding JSON-RPC request: %w", err))
+		return
+	}
+	s.logger.Debug("Execution client request", slog.String("method", request.Method))
+
+	response := rpcResponse{
+		JsonRpc: "2.0",
+		ID:      request.ID,
+	}
+	db := s.manager.GetDatabase()
+	switch request.Method {
+	case "eth_chainId":
+		response.Result = hexutil.EncodeBig(db.Eth.ChainID)
+
+	case "eth_blockNumber":
+		response.Result = hexutil.EncodeUint64(db.Eth.GetBlockNumber())
+
+	case "eth_call":
+		if len(request.Params) == 0 {
+			response.Error = &rpcError{Code: invalidParamsCode, Message: "missing call parameters"}
+			break
+		}
+		var params callParams
+		err = json.Unmarshal(request.Params[0], &params)
+		if err != nil {
+			response.Error = &rpcError{Code: invalidParamsCode, Message: fmt.Sprintf("invalid call parameters: %s", err.Error())}
+			break
+		}
+		data := params.Input
+		if len(data) == 0 {
+			data = params.Data
+		}
+		if len(data) < 4 {
+			response.Error = &rpcError{Code: invalidParamsCode, Message: "missing function selector"}
+			break
+		}
+		switch hexutil.Encode(data[:4]) {
+		case hexutil.Encode(v3stakewise.GetDepositRootSelector):
+			response.Result = db.Eth.GetDepositRoot().Hex()
+		case hexutil.Encode(getDepositCountSelector):
+			// ABI-encoded bytes holding the count as a little-endian uint64
+			encoded := make([]byte, 96)
+			encoded[31] = 0x20
+			encoded[63] = 8
+			binary.LittleEndian.PutUint64(encoded[64:72], db.Eth.GetDepositCount())
+			response.Result = hexutil.Encode(encoded)
+		default:
+			response.Error = &rpcError{Code: invalidParamsCode, Message: fmt.Sprintf("unsupported function selector %s", hexutil.Encode(data[:4]))}
+		}
+
+	default:
+		response.Error = &rpcError{Code: methodNotFoundCode, Message: fmt.Sprintf("method %s is not supported", request.Method)}
+	}
+
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		common.HandleServerError(w, s.logger, fmt.Errorf("error serializing JSON-RPC response: %w", err))
+		return
+	}
+	s.logger.Debug("Execution client response", slog.String(log.BodyKey, string(bytes)))
+	w.Header().Add("Content-Type", "application/json")
+	_, err = w.Write(bytes)
+	if err != nil {
+		s.logger.Error("Error writing response", "error", err)
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Report that the server process is alive
+func (s *NodeSetMockServer) health(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Report whether the server is accepting requests; it isn't once it starts shutting down
+func (s *NodeSetMockServer) readiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+	if !s.ready.Load() {
+		common.HandleServiceUnavailable(w, s.logger, fmt.Errorf("server isn't accepting requests"))
+		return
+	}
+	common.HandleSuccess(w, s.logger, "")
+}
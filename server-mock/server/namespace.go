@@ -0,0 +1,97 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/admin"
+	v0server "github.com/nodeset-org/nodeset-client-go/server-mock/server/api-v0"
+	v2server "github.com/nodeset-org/nodeset-client-go/server-mock/server/api-v2"
+	v3server "github.com/nodeset-org/nodeset-client-go/server-mock/server/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/eth"
+)
+
+const (
+	// The longest name a namespace can have
+	maxNamespaceNameLength int = 64
+)
+
+// Names of namespaces can be used in URL paths and headers without escaping
+var namespaceNamePattern = regexp.MustCompile("^[A-Za-z0-9_-]+$")
+
+// A database with its own manager and routes, isolated from every other namespace on the server
+type namespace struct {
+	manager     *manager.NodeSetMockManager
+	router      *mux.Router
+	adminRouter *mux.Router
+
+	// Route handlers
+	adminServer *admin.AdminServer
+	apiv0Server *v0server.V0Server
+	apiv2Server *v2server.V2Server
+	apiv3Server *v3server.V3Server
+	ethServer   *eth.EthServer
+}
+
+// Creates a new namespace with an empty database
+func newNamespace(logger *slog.Logger) *namespace {
+	ns := &namespace{
+		manager: manager.NewNodeSetMockManager(logger),
+		router:  mux.NewRouter(),
+	}
+	ns.adminServer = admin.NewAdminServer(logger, ns.manager)
+	ns.apiv0Server = v0server.NewV0Server(logger, ns.manager)
+	ns.apiv2Server = v2server.NewV2Server(logger, ns.manager)
+	ns.apiv3Server = v3server.NewV3Server(logger, ns.manager)
+	ns.ethServer = eth.NewEthServer(logger, ns.manager)
+
+	// Register admin routes. The scenario routes only use the runner, which waits for its actions to finish when it's
+	// stopped, so they can't hold the lock its actions need.
+	ns.adminRouter = ns.router.PathPrefix("/admin").Subrouter()
+	ns.adminRouter.Use(common.LockMiddleware(ns.manager,
+		"/admin/"+api.AdminStartScenarioPath,
+		"/admin/"+api.AdminStopScenarioPath,
+		"/admin/"+api.AdminScenarioStatusPath,
+	))
+	ns.adminServer.RegisterRoutes(ns.adminRouter)
+
+	// Register API routes
+	apiRouter := ns.router.PathPrefix("/api").Subrouter()
+	apiRouter.Use(common.LockMiddleware(ns.manager))
+	apiRouter.Use(common.JournalMiddleware(ns.manager))
+	ns.apiv0Server.RegisterRoutes(apiRouter)
+	ns.apiv2Server.RegisterRoutes(apiRouter)
+	ns.apiv3Server.RegisterRoutes(apiRouter)
+
+	// Register the Execution client route
+	ethRouter := ns.router.NewRoute().Subrouter()
+	ethRouter.Use(common.LockMiddleware(ns.manager))
+	ns.ethServer.RegisterRoutes(ethRouter)
+
+	return ns
+}
+
+// Stops the namespace's background work, like scenarios and deposit data set cycling
+func (ns *namespace) stopBackgroundTasks() {
+	ns.adminServer.GetScenarioRunner().Stop()
+	ns.manager.GetDepositDataCycler().Stop()
+}
+
+// Make sure a namespace name can be used in paths and headers
+func validateNamespaceName(name string) error {
+	if name == "" {
+		return fmt.Errorf("missing namespace name")
+	}
+	if len(name) > maxNamespaceNameLength {
+		return fmt.Errorf("namespace name [%s] is longer than %d characters", name, maxNamespaceNameLength)
+	}
+	if !namespaceNamePattern.MatchString(name) {
+		return fmt.Errorf("namespace name [%s] can only contain letters, numbers, dashes, and underscores", name)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
+)
+
+// Create a new namespace with an empty database
+func (s *NodeSetMockServer) createNamespace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	_, err := s.CreateNamespace(name)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Created namespace", "name", name)
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// Delete a namespace and everything in it
+func (s *NodeSetMockServer) deleteNamespace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	err := s.DeleteNamespace(name)
+	if err != nil {
+		common.HandleInputError(w, s.logger, err)
+		return
+	}
+	s.logger.Info("Deleted namespace", "name", name)
+	common.HandleSuccess(w, s.logger, "")
+}
+
+// List the namespaces
+func (s *NodeSetMockServer) listNamespaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.HandleInvalidMethod(w, s.logger)
+		return
+	}
+
+	data := api.NamespacesData{
+		Namespaces: s.GetNamespaces(),
+	}
+	common.HandleSuccess(w, s.logger, data)
+}
+
+// Serve a request made under a namespace's path prefix with the namespace's routes
+func (s *NodeSetMockServer) serveNamespacePrefix(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["namespace"]
+	ns, exists := s.getNamespace(name)
+	if !exists {
+		common.HandleInvalidNamespace(w, s.logger, name)
+		return
+	}
+	http.StripPrefix("/"+api.NamespacePrefix+name, ns.router).ServeHTTP(w, r)
+}
@@ -2,68 +2,76 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
 	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
-	"github.com/nodeset-org/nodeset-client-go/server-mock/server/admin"
-	v0server "github.com/nodeset-org/nodeset-client-go/server-mock/server/api-v0"
-	v2server "github.com/nodeset-org/nodeset-client-go/server-mock/server/api-v2"
-	v3server "github.com/nodeset-org/nodeset-client-go/server-mock/server/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/scenario"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server/common"
 
 	"github.com/rocket-pool/node-manager-core/log"
 )
 
 type NodeSetMockServer struct {
-	logger  *slog.Logger
-	ip      string
-	port    uint16
-	socket  net.Listener
-	server  http.Server
-	router  *mux.Router
-	manager *manager.NodeSetMockManager
+	logger *slog.Logger
+	ip     string
+	port   uint16
+	socket net.Listener
+	server http.Server
+	router *mux.Router
 
-	// Route handlers
-	adminServer *admin.AdminServer
-	apiv0Server *v0server.V0Server
-	apiv2Server *v2server.V2Server
-	apiv3Server *v3server.V3Server
+	// The namespace served at the root of the server
+	root *namespace
+
+	// The namespaces created with the admin routes, by name
+	namespaces    map[string]*namespace
+	namespaceLock sync.RWMutex
+
+	// The TLS config to serve with, or nil to serve plain HTTP
+	tlsConfig *tls.Config
+
+	// True while the server is accepting requests
+	ready atomic.Bool
+
+	// How long to keep serving after reporting not ready when stopping, so load balancers can stop routing to it
+	readinessGracePeriod time.Duration
 }
 
 func NewNodeSetMockServer(logger *slog.Logger, ip string, port uint16) (*NodeSetMockServer, error) {
-	// Create the router
-	router := mux.NewRouter()
-
-	// Create the manager
+	// Create the root namespace, whose router serves everything that isn't routed to another namespace
+	root := newNamespace(logger)
 	server := &NodeSetMockServer{
-		logger: logger,
-		ip:     ip,
-		port:   port,
-		router: router,
-		server: http.Server{
-			Handler: router,
-		},
-		manager: manager.NewNodeSetMockManager(logger),
-	}
-	server.adminServer = admin.NewAdminServer(logger, server.manager)
-	server.apiv0Server = v0server.NewV0Server(logger, server.manager)
-	server.apiv2Server = v2server.NewV2Server(logger, server.manager)
-	server.apiv3Server = v3server.NewV3Server(logger, server.manager)
-
-	// Register admin routes
-	adminRouter := router.PathPrefix("/admin").Subrouter()
-	server.adminServer.RegisterRoutes(adminRouter)
-
-	// Register API routes
-	apiRouter := router.PathPrefix("/api").Subrouter()
-	server.apiv0Server.RegisterRoutes(apiRouter)
-	server.apiv2Server.RegisterRoutes(apiRouter)
-	server.apiv3Server.RegisterRoutes(apiRouter)
+		logger:     logger,
+		ip:         ip,
+		port:       port,
+		router:     root.router,
+		root:       root,
+		namespaces: map[string]*namespace{},
+	}
+	server.server = http.Server{
+		Handler:  http.HandlerFunc(server.ServeHTTP),
+		ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	// Register the namespace routes
+	root.adminRouter.HandleFunc("/"+api.AdminCreateNamespacePath, server.createNamespace)
+	root.adminRouter.HandleFunc("/"+api.AdminDeleteNamespacePath, server.deleteNamespace)
+	root.adminRouter.HandleFunc("/"+api.AdminListNamespacesPath, server.listNamespaces)
+	root.router.PathPrefix("/" + api.NamespacePrefix + "{namespace}/").HandlerFunc(server.serveNamespacePrefix)
+
+	// Register the probe routes
+	root.router.HandleFunc("/"+api.HealthPath, server.health)
+	root.router.HandleFunc("/"+api.ReadyPath, server.readiness)
 
 	return server, nil
 }
@@ -81,6 +89,9 @@ func (s *NodeSetMockServer) Start(wg *sync.WaitGroup) error {
 	if s.port == 0 {
 		s.port = uint16(socket.Addr().(*net.TCPAddr).Port)
 	}
+	if s.tlsConfig != nil {
+		socket = tls.NewListener(socket, s.tlsConfig)
+	}
 
 	// Start listening
 	wg.Add(1)
@@ -91,25 +102,178 @@ func (s *NodeSetMockServer) Start(wg *sync.WaitGroup) error {
 		}
 		wg.Done()
 	}()
+	s.ready.Store(true)
 
 	return nil
 }
 
-// Stops the HTTP listener
+// Stops the HTTP listener, waiting for any requests in progress to finish
 func (s *NodeSetMockServer) Stop() error {
-	err := s.server.Shutdown(context.Background())
+	s.stopAccepting()
+	return s.shutdown(context.Background())
+}
+
+// Stops the HTTP listener, waiting up to the timeout for any requests in progress to finish before closing their
+// connections
+func (s *NodeSetMockServer) StopWithTimeout(timeout time.Duration) error {
+	s.stopAccepting()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return s.shutdown(ctx)
+}
+
+// Reports the server as not ready, then keeps serving for the readiness grace period so the readiness probe can see it
+// before the listener closes
+func (s *NodeSetMockServer) stopAccepting() {
+	s.ready.Store(false)
+	if s.readinessGracePeriod > 0 {
+		s.logger.Info("Reporting not ready before stopping", "gracePeriod", s.readinessGracePeriod)
+		time.Sleep(s.readinessGracePeriod)
+	}
+}
+
+// Stops the HTTP listener, draining requests until the context is done
+func (s *NodeSetMockServer) shutdown(ctx context.Context) error {
+	s.root.stopBackgroundTasks()
+	s.namespaceLock.RLock()
+	for _, ns := range s.namespaces {
+		ns.stopBackgroundTasks()
+	}
+	s.namespaceLock.RUnlock()
+
+	err := s.server.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		closeErr := s.server.Close()
+		if closeErr != nil {
+			return fmt.Errorf("error closing connections after the drain timeout: %w", closeErr)
+		}
+		return fmt.Errorf("requests were still in progress after the drain timeout, so their connections were closed")
+	}
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("error stopping listener: %w", err)
 	}
 	return nil
 }
 
+// Serve HTTPS with the provided config instead of plain HTTP. This has to be called before Start.
+func (s *NodeSetMockServer) SetTLSConfig(config *tls.Config) {
+	s.tlsConfig = config
+}
+
+// Set how long the server keeps serving requests after it starts reporting that it isn't ready when it's stopped. The
+// drain timeout starts after this period. The default is 0, which stops the listener right away.
+func (s *NodeSetMockServer) SetReadinessGracePeriod(gracePeriod time.Duration) {
+	s.readinessGracePeriod = gracePeriod
+}
+
+// Check if the server is serving HTTPS
+func (s *NodeSetMockServer) IsTLS() bool {
+	return s.tlsConfig != nil
+}
+
+// Check if the server is accepting requests
+func (s *NodeSetMockServer) IsReady() bool {
+	return s.ready.Load()
+}
+
+// Serve an HTTP request with the server's routes directly, without going through the listener.
+// Requests with the namespace header are served by that namespace.
+func (s *NodeSetMockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.Header.Get(api.NamespaceHeader)
+	if name == "" {
+		s.router.ServeHTTP(w, r)
+		return
+	}
+	ns, exists := s.getNamespace(name)
+	if !exists {
+		common.HandleInvalidNamespace(w, s.logger, name)
+		return
+	}
+	ns.router.ServeHTTP(w, r)
+}
+
 // Get the port the server is listening on
 func (s *NodeSetMockServer) GetPort() uint16 {
 	return s.port
 }
 
-// Get the mock manager for direct access
+// Get the mock manager of the root namespace for direct access
 func (s *NodeSetMockServer) GetManager() *manager.NodeSetMockManager {
-	return s.manager
+	return s.root.manager
+}
+
+// Get the scenario runner of the root namespace
+func (s *NodeSetMockServer) GetScenarioRunner() *scenario.Runner {
+	return s.root.adminServer.GetScenarioRunner()
+}
+
+// Create a new namespace with an empty database, and get its manager
+func (s *NodeSetMockServer) CreateNamespace(name string) (*manager.NodeSetMockManager, error) {
+	err := validateNamespaceName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.namespaceLock.Lock()
+	defer s.namespaceLock.Unlock()
+	if _, exists := s.namespaces[name]; exists {
+		return nil, fmt.Errorf("namespace [%s] already exists", name)
+	}
+	ns := newNamespace(s.logger.With("namespace", name))
+	s.namespaces[name] = ns
+	return ns.manager, nil
+}
+
+// Delete a namespace and everything in it
+func (s *NodeSetMockServer) DeleteNamespace(name string) error {
+	s.namespaceLock.Lock()
+	ns, exists := s.namespaces[name]
+	if !exists {
+		s.namespaceLock.Unlock()
+		return fmt.Errorf("namespace [%s] doesn't exist", name)
+	}
+	delete(s.namespaces, name)
+	s.namespaceLock.Unlock()
+
+	ns.stopBackgroundTasks()
+	return nil
+}
+
+// Get the mock manager of a namespace for direct access
+func (s *NodeSetMockServer) GetNamespaceManager(name string) (*manager.NodeSetMockManager, bool) {
+	ns, exists := s.getNamespace(name)
+	if !exists {
+		return nil, false
+	}
+	return ns.manager, true
+}
+
+// Get the names of the namespaces, in alphabetical order
+func (s *NodeSetMockServer) GetNamespaces() []string {
+	s.namespaceLock.RLock()
+	defer s.namespaceLock.RUnlock()
+	names := make([]string, 0, len(s.namespaces))
+	for name := range s.namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get a namespace by name
+func (s *NodeSetMockServer) getNamespace(name string) (*namespace, bool) {
+	s.namespaceLock.RLock()
+	defer s.namespaceLock.RUnlock()
+	ns, exists := s.namespaces[name]
+	return ns, exists
+}
+
+// Get the API requests the server has recorded that match the filter, oldest first
+func (s *NodeSetMockServer) GetRequests(filter api.JournalFilter) []api.JournalEntry {
+	return s.root.manager.GetJournal().GetEntries(filter)
+}
+
+// Remove all of the API requests the server has recorded
+func (s *NodeSetMockServer) ClearRequests() {
+	s.root.manager.GetJournal().Clear()
 }
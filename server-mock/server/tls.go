@@ -0,0 +1,123 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	// How long generated certificates are valid for
+	selfSignedValidity time.Duration = 365 * 24 * time.Hour
+
+	// The organization on generated certificates
+	selfSignedOrganization string = "NodeSet Mock"
+)
+
+// The hosts generated certificates are always valid for, in addition to the ones provided
+var defaultCertificateHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// Create a TLS config that serves the certificate and key in the provided PEM files
+func LoadCertificate(certFile string, keyFile string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// Create a TLS config with a new self-signed certificate authority and a server certificate it signed for the provided
+// hosts, which can be DNS names or IP addresses. localhost and the loopback addresses are always included.
+// Returns the config and the PEM-encoded CA certificate, which clients need to trust to connect to the server.
+func GenerateSelfSignedCertificate(hosts []string) (*tls.Config, []byte, error) {
+	now := time.Now()
+
+	// Create the CA
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating CA key: %w", err)
+	}
+	caSerial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          caSerial,
+		Subject:               pkix.Name{Organization: []string{selfSignedOrganization}, CommonName: selfSignedOrganization + " CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating CA certificate: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(caDer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing CA certificate: %w", err)
+	}
+
+	// Create the server certificate
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating server key: %w", err)
+	}
+	serverSerial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	serverTemplate := &x509.Certificate{
+		SerialNumber: serverSerial,
+		Subject:      pkix.Name{Organization: []string{selfSignedOrganization}, CommonName: selfSignedOrganization},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range append(append([]string{}, defaultCertificateHosts...), hosts...) {
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else if host != "" {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+	serverDer, err := x509.CreateCertificate(rand.Reader, serverTemplate, caCert, &serverKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating server certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{
+			{
+				Certificate: [][]byte{serverDer, caDer},
+				PrivateKey:  serverKey,
+			},
+		},
+		MinVersion: tls.VersionTLS12,
+	}
+	caPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer})
+	return config, caPem, nil
+}
+
+// Get a random serial number for a certificate
+func newSerialNumber() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serial, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error generating certificate serial number: %w", err)
+	}
+	return serial, nil
+}
@@ -1,14 +1,19 @@
-package test
+// Package testkit provides deterministic keys, fixtures, and an in-process mock server for testing code that uses the NodeSet client bindings.
+// Everything is derived from the standard Hardhat test mnemonic, so the same index always produces the same node and validator keys.
+package testkit
 
 import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"sync"
+	"testing"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/rocket-pool/node-manager-core/node/validator"
 	"github.com/tyler-smith/go-bip39"
 	types "github.com/wealdtech/go-eth2-types/v2"
@@ -45,6 +50,13 @@ var (
 	WhitelistAddress      common.Address = common.HexToAddress(WhitelistAddressString)
 )
 
+// Derived keys are cached since BLS derivation is slow
+var (
+	keyLock       sync.Mutex
+	nodeKeys      map[uint]*ecdsa.PrivateKey    = map[uint]*ecdsa.PrivateKey{}
+	validatorKeys map[uint]*types.BLSPrivateKey = map[uint]*types.BLSPrivateKey{}
+)
+
 // Get the EL private key for the given index
 func GetEthPrivateKey(index uint) (*ecdsa.PrivateKey, error) {
 	// Check the mnemonic
@@ -80,6 +92,45 @@ func GetBeaconPrivateKey(index uint) (*types.BLSPrivateKey, error) {
 	return validator.GetPrivateKey(Mnemonic, path)
 }
 
+// Get the EL private key for the given index, failing the test if it can't be derived
+func GetNodeKey(tb testing.TB, index uint) *ecdsa.PrivateKey {
+	keyLock.Lock()
+	defer keyLock.Unlock()
+
+	nodeKey, exists := nodeKeys[index]
+	if !exists {
+		var err error
+		nodeKey, err = GetEthPrivateKey(index)
+		if err != nil {
+			tb.Fatalf("Error getting private key for node %d: %v", index, err)
+		}
+		nodeKeys[index] = nodeKey
+	}
+	return nodeKey
+}
+
+// Get the address of the node with the given index
+func GetNodeAddress(tb testing.TB, index uint) common.Address {
+	return crypto.PubkeyToAddress(GetNodeKey(tb, index).PublicKey)
+}
+
+// Get the BLS private key for the given index, failing the test if it can't be derived
+func GetValidatorKey(tb testing.TB, index uint) *types.BLSPrivateKey {
+	keyLock.Lock()
+	defer keyLock.Unlock()
+
+	validatorKey, exists := validatorKeys[index]
+	if !exists {
+		var err error
+		validatorKey, err = GetBeaconPrivateKey(index)
+		if err != nil {
+			tb.Fatalf("Error getting private key for validator %d: %v", index, err)
+		}
+		validatorKeys[index] = validatorKey
+	}
+	return validatorKey
+}
+
 // ==========================
 // === Internal Functions ===
 // ==========================
@@ -0,0 +1,106 @@
+package testkit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"testing"
+
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/api"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+)
+
+const (
+	// The longest prefix of a test name used in the names of its namespaces
+	maxNamespaceTestNameLength int = 40
+)
+
+// Matches runs of characters that can't be used in namespace names
+var invalidNamespaceCharacters = regexp.MustCompile("[^A-Za-z0-9_-]+")
+
+// A namespace on a running mock server, created for a single test so it can't interfere with any other test using
+// the same server
+type Namespace struct {
+	// The namespace's name
+	Name string
+
+	// The base URL of the namespace's routes, like [http://localhost:50512/ns/<name>]
+	baseUrl string
+
+	// The HTTP client for the server
+	httpClient *http.Client
+}
+
+// Create a namespace with a unique name on a running mock server, such as a shared nodeset-server-mock process.
+// serverUrl is the root of the server, like [http://localhost:50512]. The namespace is deleted automatically when
+// the test and its subtests finish.
+func CreateNamespace(tb testing.TB, logger *slog.Logger, serverUrl string) *Namespace {
+	return createNamespace(tb, logger, serverUrl, &http.Client{Timeout: DefaultTimeout})
+}
+
+// Create a namespace on a running mock server, sending requests with the provided HTTP client
+func createNamespace(tb testing.TB, logger *slog.Logger, serverUrl string, httpClient *http.Client) *Namespace {
+	// Name it after the test, with a random suffix so parallel runs of the same test don't collide
+	suffix := make([]byte, 4)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		tb.Fatalf("Error generating namespace name: %v", err)
+	}
+	prefix := invalidNamespaceCharacters.ReplaceAllString(tb.Name(), "-")
+	if len(prefix) > maxNamespaceTestNameLength {
+		prefix = prefix[:maxNamespaceTestNameLength]
+	}
+	name := prefix + "-" + hex.EncodeToString(suffix)
+
+	// Create it
+	rootClient := client.NewAdminClientWithHttpClient(serverUrl+"/admin", httpClient)
+	err = rootClient.CreateNamespace(context.Background(), logger, name)
+	if err != nil {
+		tb.Fatalf("Error creating namespace: %v", err)
+	}
+	tb.Cleanup(func() {
+		err := rootClient.DeleteNamespace(context.Background(), logger, name)
+		if err != nil {
+			tb.Errorf("Error deleting namespace [%s]: %v", name, err)
+		}
+	})
+
+	return &Namespace{
+		Name:       name,
+		baseUrl:    serverUrl + "/" + api.NamespacePrefix + name,
+		httpClient: httpClient,
+	}
+}
+
+// Get the base URL of the namespace's NodeSet API, for use with the client bindings
+func (n *Namespace) GetApiUrl() string {
+	return n.baseUrl + "/api"
+}
+
+// Get the base URL of the namespace's admin routes, for use with the mock's admin client
+func (n *Namespace) GetAdminUrl() string {
+	return n.baseUrl + "/admin"
+}
+
+// Get the URL of the namespace's Execution client JSON-RPC route
+func (n *Namespace) GetEthUrl() string {
+	return n.baseUrl + "/" + api.EthRpcPath
+}
+
+// Create a v3 API client for the namespace, authenticated with the session token if one is provided
+func (n *Namespace) NewApiClient(sessionToken string) *apiv3.NodeSetClient {
+	nsClient := apiv3.NewNodeSetClientWithHttpClient(n.GetApiUrl(), n.httpClient)
+	if sessionToken != "" {
+		nsClient.SetSessionToken(sessionToken)
+	}
+	return nsClient
+}
+
+// Create an admin client for the namespace
+func (n *Namespace) NewAdminClient() *client.AdminClient {
+	return client.NewAdminClientWithHttpClient(n.GetAdminUrl(), n.httpClient)
+}
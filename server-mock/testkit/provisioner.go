@@ -0,0 +1,221 @@
+package testkit
+
+import (
+	"log/slog"
+	"strconv"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/node/validator"
+	"github.com/stretchr/testify/require"
+	types "github.com/wealdtech/go-eth2-types/v2"
+)
+
+// Create a full database for testing. It has a StakeWise and Constellation deployment for Network, a StakeWise vault, and four users:
+//   - User0Email has no nodes
+//   - User1Email has node 0 with validator 0
+//   - User2Email has node 1 with validators 1 and 2
+//   - User3Email has node 2 with validator 3 and node 3 with validator 4
+//
+// Every node is registered and logged in. If includeDepositDataSet is true, a deposit data set with one validator per user
+// is created and marked as uploaded to StakeWise.
+func ProvisionFullDatabase(tb testing.TB, logger *slog.Logger, includeDepositDataSet bool) *db.Database {
+	// Make the DB
+	database := db.NewDatabase(logger)
+	swDeployment := database.StakeWise.AddDeployment(Network, ChainIDBig)
+	_ = database.Constellation.AddDeployment(Network, ChainIDBig, WhitelistAddress, SuperNodeAddress)
+
+	// Add a StakeWise vault to the database
+	vault := swDeployment.AddVault(StakeWiseVaultName, StakeWiseVaultAddress)
+	tb.Log("Added StakeWise vault to database")
+
+	// Add a users to the database
+	_ = AddUser(tb, database, User0Email)
+	user1 := AddUser(tb, database, User1Email)
+	user2 := AddUser(tb, database, User2Email)
+	user3 := AddUser(tb, database, User3Email)
+	tb.Log("Added users to database")
+
+	// Add nodes to the user
+	node0 := AddNode(tb, database, user1, 0)
+	node1 := AddNode(tb, database, user2, 1)
+	node2 := AddNode(tb, database, user3, 2)
+	node3 := AddNode(tb, database, user3, 3)
+	tb.Log("Added nodes to users")
+
+	// Get some deposit data
+	depositData0 := GenerateDepositData(tb, 0, StakeWiseVaultAddress)
+	depositData1 := GenerateDepositData(tb, 1, StakeWiseVaultAddress)
+	depositData2 := GenerateDepositData(tb, 2, StakeWiseVaultAddress)
+	depositData3 := GenerateDepositData(tb, 3, StakeWiseVaultAddress)
+	depositData4 := GenerateDepositData(tb, 4, StakeWiseVaultAddress)
+	tb.Log("Generated deposit data")
+
+	// Handle the deposit data upload
+	err := vault.HandleDepositDataUpload(node0, []beacon.ExtendedDepositData{depositData0})
+	require.NoError(tb, err)
+	err = vault.HandleDepositDataUpload(node1, []beacon.ExtendedDepositData{depositData1, depositData2})
+	require.NoError(tb, err)
+	err = vault.HandleDepositDataUpload(node2, []beacon.ExtendedDepositData{depositData3})
+	require.NoError(tb, err)
+	err = vault.HandleDepositDataUpload(node3, []beacon.ExtendedDepositData{depositData4})
+	require.NoError(tb, err)
+	tb.Log("Handled deposit data upload")
+
+	// Shortcut if skipping deposit data set generation
+	if !includeDepositDataSet {
+		return database
+	}
+
+	// Create a new set with 1 DD per user and verify
+	pubkeys := []beacon.ValidatorPubkey{
+		beacon.ValidatorPubkey(depositData0.PublicKey),
+		beacon.ValidatorPubkey(depositData1.PublicKey),
+		beacon.ValidatorPubkey(depositData2.PublicKey),
+		beacon.ValidatorPubkey(depositData3.PublicKey),
+		beacon.ValidatorPubkey(depositData4.PublicKey),
+	}
+	expectedMap := map[beacon.ValidatorPubkey]beacon.ExtendedDepositData{
+		pubkeys[0]: depositData0,
+		pubkeys[1]: depositData1,
+		pubkeys[2]: depositData2,
+		pubkeys[3]: depositData3,
+		pubkeys[4]: depositData4,
+	}
+	depositDataSet := vault.CreateNewDepositDataSet(1)
+	seenPubkeys := map[beacon.ValidatorPubkey]bool{}
+	for _, dd := range depositDataSet {
+		pubkey := beacon.ValidatorPubkey(dd.PublicKey)
+		seenPubkeys[pubkey] = true
+		require.Equal(tb, expectedMap[pubkey], dd)
+	}
+	require.True(tb, seenPubkeys[pubkeys[0]])
+	require.True(tb, seenPubkeys[pubkeys[1]] != seenPubkeys[pubkeys[2]]) // One from node 1
+	require.True(tb, seenPubkeys[pubkeys[3]] != seenPubkeys[pubkeys[4]]) // One from node 2
+
+	// Handle the deposit data upload
+	vault.UploadDepositDataToStakeWise(depositDataSet)
+	tb.Log("Uploaded deposit data to StakeWise")
+
+	// Finalize the upload
+	vault.MarkDepositDataSetUploaded(depositDataSet)
+	tb.Log("Marked deposit data set uploaded")
+
+	return database
+}
+
+// Add a user to the database
+func AddUser(tb testing.TB, database *db.Database, userEmail string) *db.User {
+	user, err := database.Core.AddUser(userEmail)
+	if err != nil {
+		tb.Fatalf("Error adding user [%s] to database: %v", userEmail, err)
+	}
+	return user
+}
+
+// Create the node with the given key index, register it with the user, and log it in with a new session
+func AddNode(tb testing.TB, database *db.Database, user *db.User, index uint) *db.Node {
+	nodeAddress := GetNodeAddress(tb, index)
+
+	// Whitelist the node
+	node := user.WhitelistNode(nodeAddress)
+
+	// Register the node
+	err := node.RegisterWithoutSignature()
+	require.NoError(tb, err)
+
+	// Create a new session for it
+	LoginNode(tb, database, nodeAddress)
+	return node
+}
+
+// Log a registered node in with a new session
+func LoginNode(tb testing.TB, database *db.Database, nodeAddress ethcommon.Address) *db.Session {
+	session := database.Core.CreateSession()
+	err := database.Core.LoginWithoutSignature(nodeAddress, session.Nonce)
+	if err != nil {
+		tb.Fatalf("Error logging in node [%s]: %v", nodeAddress.Hex(), err)
+	}
+	return session
+}
+
+// Mark every StakeWise validator in the database as active, as if it had been registered with NodeSet
+func MarkValidatorsActive(database *db.Database) {
+	for _, deployment := range database.StakeWise.GetDeployments() {
+		for _, vault := range deployment.GetVaults() {
+			for _, validators := range vault.Validators {
+				for _, validator := range validators {
+					validator.MarkActive()
+				}
+			}
+		}
+	}
+}
+
+// Generate deposit data for the validator key with the given index
+func GenerateDepositData(tb testing.TB, index uint, withdrawalAddress ethcommon.Address) beacon.ExtendedDepositData {
+	validatorKey := GetValidatorKey(tb, index)
+	depositData, err := validator.GetDepositData(
+		nil,
+		validatorKey,
+		validator.GetWithdrawalCredsFromAddress(withdrawalAddress),
+		GenesisForkVersion,
+		DepositAmount,
+		Network,
+	)
+	if err != nil {
+		tb.Fatalf("Error generating deposit data for validator %d: %v", index, err)
+	}
+	return depositData
+}
+
+// Generate a signed exit for the given validator index
+func GenerateSignedExit(tb testing.TB, index uint) common.ExitData {
+	// Create the exit domain
+	domain, err := types.ComputeDomain(types.DomainVoluntaryExit, CapellaForkVersion, GenesisValidatorsRoot)
+	if err != nil {
+		tb.Fatalf("Error computing domain for validator %d: %v", index, err)
+	}
+
+	// Get the exit signature
+	validatorKey := GetValidatorKey(tb, index)
+	validatorIndex := strconv.FormatUint(uint64(index), 10)
+	exitSignature, err := validator.GetSignedExitMessage(
+		validatorKey,
+		validatorIndex,
+		ExitEpoch,
+		domain,
+	)
+	if err != nil {
+		tb.Fatalf("Error generating signed exit for validator %d: %v", index, err)
+	}
+
+	// Return the exit data
+	pubkey := beacon.ValidatorPubkey(validatorKey.PublicKey().Marshal())
+	return common.ExitData{
+		Pubkey: pubkey.HexWithPrefix(),
+		ExitMessage: common.ExitMessage{
+			Message: common.ExitMessageDetails{
+				Epoch:          strconv.FormatUint(ExitEpoch, 10),
+				ValidatorIndex: validatorIndex,
+			},
+			Signature: exitSignature.HexWithPrefix(),
+		},
+	}
+}
+
+// Generate a signed exit for the given validator index, encrypted for the recipient's age public key
+func GenerateEncryptedExit(tb testing.TB, index uint, recipientPubkey string) common.EncryptedExitData {
+	exitData := GenerateSignedExit(tb, index)
+	encryptedMessage, err := common.EncryptSignedExitMessage(exitData.ExitMessage, recipientPubkey)
+	if err != nil {
+		tb.Fatalf("Error encrypting signed exit for validator %d: %v", index, err)
+	}
+	return common.EncryptedExitData{
+		Pubkey:      exitData.Pubkey,
+		ExitMessage: encryptedMessage,
+	}
+}
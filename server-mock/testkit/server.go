@@ -0,0 +1,165 @@
+package testkit
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	apiv3 "github.com/nodeset-org/nodeset-client-go/api-v3"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/client"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/db"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/manager"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/server"
+)
+
+const (
+	// The timeout used by clients created by the mock server
+	DefaultTimeout time.Duration = 5 * time.Second
+)
+
+// An in-process mock server started for a single test
+type MockServer struct {
+	// The underlying server
+	Server *server.NodeSetMockServer
+
+	logger     *slog.Logger
+	httpClient *http.Client
+}
+
+// Start a mock server on a random localhost port. It's stopped automatically when the test and its subtests finish.
+func StartServer(tb testing.TB, logger *slog.Logger) *MockServer {
+	return startServer(tb, logger, nil, &http.Client{Timeout: DefaultTimeout})
+}
+
+// Start a mock server that serves HTTPS with a new self-signed certificate on a random localhost port. Clients created
+// by the returned server trust the certificate. It's stopped automatically when the test and its subtests finish.
+func StartTLSServer(tb testing.TB, logger *slog.Logger) *MockServer {
+	tlsConfig, caPem, err := server.GenerateSelfSignedCertificate(nil)
+	if err != nil {
+		tb.Fatalf("Error generating certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		tb.Fatalf("Error adding the generated CA certificate to the pool")
+	}
+	httpClient := &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    pool,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+	return startServer(tb, logger, tlsConfig, httpClient)
+}
+
+// Start a mock server, serving HTTPS if a TLS config is provided
+func startServer(tb testing.TB, logger *slog.Logger, tlsConfig *tls.Config, httpClient *http.Client) *MockServer {
+	// Create the server
+	s, err := server.NewNodeSetMockServer(logger, "localhost", 0)
+	if err != nil {
+		tb.Fatalf("Error creating mock server: %v", err)
+	}
+	if tlsConfig != nil {
+		s.SetTLSConfig(tlsConfig)
+	}
+
+	// Start it
+	wg := &sync.WaitGroup{}
+	err = s.Start(wg)
+	if err != nil {
+		tb.Fatalf("Error starting mock server: %v", err)
+	}
+	tb.Cleanup(func() {
+		err := s.Stop()
+		if err != nil {
+			tb.Errorf("Error stopping mock server: %v", err)
+		}
+		wg.Wait()
+	})
+	tb.Logf("Started mock server on port %d", s.GetPort())
+
+	return &MockServer{
+		Server:     s,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+// Get the port the server is listening on
+func (m *MockServer) GetPort() uint16 {
+	return m.Server.GetPort()
+}
+
+// Get the root URL of the server, like [http://localhost:50512]
+func (m *MockServer) GetUrl() string {
+	scheme := "http"
+	if m.Server.IsTLS() {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://localhost:%d", scheme, m.Server.GetPort())
+}
+
+// Get the base URL of the NodeSet API, for use with the client bindings
+func (m *MockServer) GetApiUrl() string {
+	return m.GetUrl() + "/api"
+}
+
+// Get the base URL of the admin routes, for use with the mock's admin client
+func (m *MockServer) GetAdminUrl() string {
+	return m.GetUrl() + "/admin"
+}
+
+// Get an HTTP client for the server, which trusts its certificate if it serves HTTPS
+func (m *MockServer) GetHttpClient() *http.Client {
+	return m.httpClient
+}
+
+// Create a namespace on the server for a test; see CreateNamespace. Its manager is available with
+// Server.GetNamespaceManager.
+func (m *MockServer) CreateNamespace(tb testing.TB) *Namespace {
+	return createNamespace(tb, m.logger, m.GetUrl(), m.httpClient)
+}
+
+// Get the mock manager for direct access
+func (m *MockServer) GetManager() *manager.NodeSetMockManager {
+	return m.Server.GetManager()
+}
+
+// Get the server's current database
+func (m *MockServer) GetDatabase() *db.Database {
+	return m.Server.GetManager().GetDatabase()
+}
+
+// Replace the server's database with a fully provisioned one; see ProvisionFullDatabase for its contents
+func (m *MockServer) Provision(tb testing.TB, includeDepositDataSet bool) *db.Database {
+	database := ProvisionFullDatabase(tb, m.logger, includeDepositDataSet)
+	m.Server.GetManager().SetDatabase(database)
+	return database
+}
+
+// Log a registered node in with a new session on the server's current database
+func (m *MockServer) Login(tb testing.TB, nodeAddress ethcommon.Address) *db.Session {
+	return LoginNode(tb, m.GetDatabase(), nodeAddress)
+}
+
+// Create a v3 API client for the server, authenticated with the session if one is provided
+func (m *MockServer) NewApiClient(session *db.Session) *apiv3.NodeSetClient {
+	nsClient := apiv3.NewNodeSetClientWithHttpClient(m.GetApiUrl(), m.httpClient)
+	if session != nil {
+		nsClient.SetSessionToken(session.Token)
+	}
+	return nsClient
+}
+
+// Create an admin client for the server
+func (m *MockServer) NewAdminClient() *client.AdminClient {
+	return client.NewAdminClientWithHttpClient(m.GetAdminUrl(), m.httpClient)
+}
@@ -0,0 +1,128 @@
+package testkit_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"github.com/goccy/go-json"
+	"github.com/nodeset-org/nodeset-client-go/common"
+	"github.com/nodeset-org/nodeset-client-go/server-mock/testkit"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/rocket-pool/node-manager-core/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// Make sure a provisioned server can be used by an authenticated client
+func TestProvisionedServer(t *testing.T) {
+	logger := slog.Default()
+	server := testkit.StartServer(t, logger)
+	testkit.MarkValidatorsActive(server.Provision(t, true))
+
+	// Node 0 belongs to user 1 and owns validator 0
+	session := server.Login(t, testkit.GetNodeAddress(t, 0))
+	client := server.NewApiClient(session)
+	data, err := client.StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+	require.Len(t, data.Validators, 1)
+	expectedPubkey := beacon.ValidatorPubkey(testkit.GenerateDepositData(t, 0, testkit.StakeWiseVaultAddress).PublicKey)
+	require.Equal(t, expectedPubkey, data.Validators[0].Pubkey)
+	t.Logf("Node 0 has validator %s", expectedPubkey.Hex())
+
+	// The admin client can see the same state
+	state, err := server.NewAdminClient().GetState(context.Background(), logger)
+	require.NoError(t, err)
+	require.Len(t, state.Users, 4)
+}
+
+// Make sure a server can serve HTTPS to clients that trust its certificate, and report its health and readiness
+func TestTLSServer(t *testing.T) {
+	logger := slog.Default()
+	server := testkit.StartTLSServer(t, logger)
+	server.Provision(t, false)
+	require.True(t, strings.HasPrefix(server.GetApiUrl(), "https://"))
+
+	// The API should work over HTTPS
+	session := server.Login(t, testkit.GetNodeAddress(t, 0))
+	_, err := server.NewApiClient(session).StakeWise.Validators_Get(context.Background(), logger, testkit.Network, testkit.StakeWiseVaultAddress)
+	require.NoError(t, err)
+
+	// Clients that don't trust the certificate should be rejected
+	_, err = http.Get(server.GetUrl() + "/health")
+	require.Error(t, err)
+
+	// The probes should report the server as healthy and ready
+	for _, path := range []string{"/health", "/ready"} {
+		response, err := server.GetHttpClient().Get(server.GetUrl() + path)
+		require.NoError(t, err)
+		response.Body.Close()
+		require.Equal(t, http.StatusOK, response.StatusCode, path)
+	}
+	require.True(t, server.Server.IsReady())
+
+	// The server shouldn't be ready once it stops. Close the probes' connections first so they don't count as requests
+	// in progress.
+	server.GetHttpClient().CloseIdleConnections()
+	require.NoError(t, server.Server.StopWithTimeout(time.Second))
+	require.False(t, server.Server.IsReady())
+	recorder := httptest.NewRecorder()
+	server.Server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+// Make sure a stopping server reports that it isn't ready while it's still serving requests
+func TestReadinessGracePeriod(t *testing.T) {
+	logger := slog.Default()
+	server := testkit.StartServer(t, logger)
+	server.Server.SetReadinessGracePeriod(time.Second)
+	getStatus := func(path string) int {
+		response, err := server.GetHttpClient().Get(server.GetUrl() + path)
+		require.NoError(t, err)
+		response.Body.Close()
+		return response.StatusCode
+	}
+	require.Equal(t, http.StatusOK, getStatus("/ready"))
+
+	// Start stopping the server in the background
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- server.Server.StopWithTimeout(time.Second)
+	}()
+
+	// The readiness probe should fail while the health probe still succeeds
+	require.Eventually(t, func() bool {
+		return !server.Server.IsReady()
+	}, 500*time.Millisecond, 10*time.Millisecond)
+	require.Equal(t, http.StatusServiceUnavailable, getStatus("/ready"))
+	require.Equal(t, http.StatusOK, getStatus("/health"))
+	t.Log("Server reported not ready while it was still healthy")
+
+	// The server should finish stopping once the grace period is over
+	server.GetHttpClient().CloseIdleConnections()
+	require.NoError(t, <-stopped)
+	_, err := server.GetHttpClient().Get(server.GetUrl() + "/health")
+	require.Error(t, err)
+}
+
+// Make sure encrypted exits decrypt to the matching signed exit
+func TestEncryptedExit(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	encryptedExit := testkit.GenerateEncryptedExit(t, 1, id.Recipient().String())
+	signedExit := testkit.GenerateSignedExit(t, 1)
+	require.Equal(t, signedExit.Pubkey, encryptedExit.Pubkey)
+
+	encryptedBytes, err := utils.DecodeHex(encryptedExit.ExitMessage)
+	require.NoError(t, err)
+	reader, err := age.Decrypt(bytes.NewReader(encryptedBytes), id)
+	require.NoError(t, err)
+	var exitMessage common.ExitMessage
+	require.NoError(t, json.NewDecoder(reader).Decode(&exitMessage))
+	require.Equal(t, signedExit.ExitMessage, exitMessage)
+}